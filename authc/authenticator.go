@@ -18,7 +18,9 @@ import (
 	"fmt"
 
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/authc/awssigv4"
 	"github.com/relychan/gohttpc/authc/basicauth"
+	"github.com/relychan/gohttpc/authc/digestauth"
 	"github.com/relychan/gohttpc/authc/httpauth"
 	"github.com/relychan/gohttpc/authc/oauth2scheme"
 )
@@ -35,6 +37,10 @@ func NewAuthenticatorFromConfig(
 		return httpauth.NewHTTPCredential(conf, options)
 	case *oauth2scheme.OAuth2Config:
 		return oauth2scheme.NewOAuth2Credential(conf, options)
+	case *awssigv4.AWSSigV4Config:
+		return awssigv4.NewAWSSigV4Credential(conf, options)
+	case *digestauth.DigestAuthConfig:
+		return digestauth.NewDigestCredential(conf, options)
 	default:
 		return nil, fmt.Errorf("%w: %s", errUnsupportedSecurityScheme, config.GetType())
 	}
@@ -19,7 +19,9 @@ import (
 
 	"github.com/hasura/goenvconf"
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/authc/awssigv4"
 	"github.com/relychan/gohttpc/authc/basicauth"
+	"github.com/relychan/gohttpc/authc/digestauth"
 	"github.com/relychan/gohttpc/authc/httpauth"
 	"github.com/relychan/gohttpc/authc/oauth2scheme"
 )
@@ -54,6 +56,44 @@ func TestNewAuthenticatorFromConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("creates aws sigv4 authenticator from config", func(t *testing.T) {
+		config := &HTTPClientAuthConfig{
+			HTTPClientAuthenticatorConfig: awssigv4.NewAWSSigV4Config(
+				goenvconf.NewEnvStringValue("AKIDEXAMPLE"),
+				goenvconf.NewEnvStringValue("secret"),
+				"us-east-1",
+				"execute-api",
+			),
+		}
+
+		authenticator, err := NewAuthenticatorFromConfig(config, authscheme.NewHTTPClientAuthenticatorOptions())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if _, ok := authenticator.(*awssigv4.AWSSigV4Credential); !ok {
+			t.Errorf("expected AWSSigV4Credential, got %T", authenticator)
+		}
+	})
+
+	t.Run("creates digest authenticator from config", func(t *testing.T) {
+		config := &HTTPClientAuthConfig{
+			HTTPClientAuthenticatorConfig: digestauth.NewDigestAuthConfig(
+				goenvconf.NewEnvStringValue("user"),
+				goenvconf.NewEnvStringValue("pass"),
+			),
+		}
+
+		authenticator, err := NewAuthenticatorFromConfig(config, authscheme.NewHTTPClientAuthenticatorOptions())
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if _, ok := authenticator.(*digestauth.DigestCredential); !ok {
+			t.Errorf("expected DigestCredential, got %T", authenticator)
+		}
+	})
+
 	t.Run("creates http auth authenticator from config", func(t *testing.T) {
 		config := &HTTPClientAuthConfig{
 			HTTPClientAuthenticatorConfig: &httpauth.HTTPAuthConfig{
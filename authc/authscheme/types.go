@@ -32,6 +32,21 @@ type HTTPClientAuthenticator interface {
 	Close() error
 }
 
+// HTTPClientChallengeHandler abstracts an optional interface of authenticators
+// which answer the authentication challenge of 401 responses, e.g. the HTTP Digest scheme.
+type HTTPClientChallengeHandler interface {
+	// HandleChallenge updates the credential from the challenge of the 401 response.
+	// Returns true if the request should be authenticated and sent again.
+	HandleChallenge(resp *http.Response) bool
+}
+
+// HTTPClientSetter abstracts an optional interface of authenticators which send their own requests,
+// so they can use the configured HTTP client instead of the default one.
+type HTTPClientSetter interface {
+	// SetHTTPClient sets the HTTP client of the authenticator.
+	SetHTTPClient(client *http.Client)
+}
+
 // HTTPClientAuthenticatorConfig abstracts an interface of the HTTP client authentication config.
 type HTTPClientAuthenticatorConfig interface {
 	goutils.IsZeroer
@@ -48,17 +63,21 @@ const (
 	HTTPAuthScheme HTTPClientAuthType = iota + 1
 	BasicAuthScheme
 	OAuth2Scheme
+	AWSSigV4Scheme
+	DigestAuthScheme
 )
 
 var enumValueHTTPClientAuthTypes = []string{
 	"http",
 	"basic",
 	"oauth2",
+	"awsSigV4",
+	"digest",
 }
 
 // IsValid checks if the security scheme type is valid.
 func (j HTTPClientAuthType) IsValid() bool {
-	return j > 0 && j < 4
+	return j > 0 && j < 6
 }
 
 // String implements fmt.Stringer interface.
@@ -108,6 +127,10 @@ func ParseHTTPClientAuthType(value string) (HTTPClientAuthType, error) {
 		return HTTPAuthScheme, nil
 	case "oauth2":
 		return OAuth2Scheme, nil
+	case "awsSigV4":
+		return AWSSigV4Scheme, nil
+	case "digest":
+		return DigestAuthScheme, nil
 	default:
 		return 0, fmt.Errorf(
 			"%w; got: %s",
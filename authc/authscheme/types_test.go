@@ -24,6 +24,8 @@ func TestHTTPClientAuthType_Validate(t *testing.T) {
 			BasicAuthScheme,
 			HTTPAuthScheme,
 			OAuth2Scheme,
+			AWSSigV4Scheme,
+			DigestAuthScheme,
 		}
 
 		for _, authType := range supportedTypes {
@@ -43,6 +45,8 @@ func TestParseHTTPClientAuthType(t *testing.T) {
 			{"basic", BasicAuthScheme},
 			{"http", HTTPAuthScheme},
 			{"oauth2", OAuth2Scheme},
+			{"awsSigV4", AWSSigV4Scheme},
+			{"digest", DigestAuthScheme},
 		}
 
 		for _, tc := range testCases {
@@ -0,0 +1,403 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awssigv4 implements authentication interfaces for the AWS Signature Version 4 scheme.
+package awssigv4
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/relychan/gohttpc/authc/authscheme"
+)
+
+const (
+	signingAlgorithm    = "AWS4-HMAC-SHA256"
+	amzDateFormat       = "20060102T150405Z"
+	shortDateFormat     = "20060102"
+	headerAmzDate       = "X-Amz-Date"
+	headerAmzToken      = "X-Amz-Security-Token"
+	headerAmzContent    = "X-Amz-Content-Sha256"
+	headerAuthorization = "Authorization"
+)
+
+// ignoredHeaders are excluded from the signature because they may be modified after signing.
+var ignoredHeaders = map[string]bool{
+	"authorization":   true,
+	"user-agent":      true,
+	"x-amzn-trace-id": true,
+	"expect":          true,
+	"traceparent":     true,
+	"tracestate":      true,
+	"baggage":         true,
+}
+
+// AWSSigV4Credential represents the AWS Signature Version 4 credential.
+type AWSSigV4Credential struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	region          string
+	service         string
+	now             func() time.Time
+}
+
+var _ authscheme.HTTPClientAuthenticator = (*AWSSigV4Credential)(nil)
+
+// NewAWSSigV4Credential creates a new AWSSigV4Credential instance.
+func NewAWSSigV4Credential(
+	config *AWSSigV4Config,
+	options *authscheme.HTTPClientAuthenticatorOptions,
+) (*AWSSigV4Credential, error) {
+	if options == nil {
+		options = authscheme.NewHTTPClientAuthenticatorOptions()
+	}
+
+	err := config.Validate(false)
+	if err != nil {
+		return nil, err
+	}
+
+	getEnv := options.GetEnvFunc()
+
+	accessKeyID, err := config.AccessKeyID.GetCustom(getEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS SigV4 credential. Invalid access key ID: %w", err)
+	}
+
+	secretAccessKey, err := config.SecretAccessKey.GetCustom(getEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS SigV4 credential. Invalid secret access key: %w", err)
+	}
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, authscheme.ErrAuthCredentialEmpty
+	}
+
+	result := &AWSSigV4Credential{
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		region:          config.Region,
+		service:         config.Service,
+		now:             time.Now,
+	}
+
+	if config.SessionToken != nil {
+		sessionToken, err := config.SessionToken.GetCustom(getEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS SigV4 credential. Invalid session token: %w", err)
+		}
+
+		result.sessionToken = sessionToken
+	}
+
+	return result, nil
+}
+
+// Authenticate signs the request and sets the Authorization and X-Amz-Date headers.
+// The request body is read to compute the payload hash and rewound afterwards.
+func (ac *AWSSigV4Credential) Authenticate(
+	req *http.Request,
+	options ...authscheme.AuthenticateOption,
+) error {
+	payloadHash, err := hashRequestBody(req)
+	if err != nil {
+		return err
+	}
+
+	signingTime := ac.now().UTC()
+	amzDate := signingTime.Format(amzDateFormat)
+
+	req.Header.Del(headerAuthorization)
+	req.Header.Set(headerAmzDate, amzDate)
+
+	if ac.sessionToken != "" {
+		req.Header.Set(headerAmzToken, ac.sessionToken)
+	}
+
+	if ac.service == "s3" {
+		req.Header.Set(headerAmzContent, payloadHash)
+	}
+
+	canonicalRequest, signedHeaders := ac.buildCanonicalRequest(req, payloadHash)
+	scope := signingTime.Format(shortDateFormat) + "/" + ac.region + "/" + ac.service + "/aws4_request"
+	stringToSign := buildStringToSign(amzDate, scope, canonicalRequest)
+	signature := hex.EncodeToString(
+		hmacSHA256(ac.deriveSigningKey(signingTime), []byte(stringToSign)),
+	)
+
+	req.Header.Set(
+		headerAuthorization,
+		signingAlgorithm+" Credential="+ac.accessKeyID+"/"+scope+
+			", SignedHeaders="+signedHeaders+
+			", Signature="+signature,
+	)
+
+	return nil
+}
+
+// Equal checks if the target value is equal.
+func (ac AWSSigV4Credential) Equal(target AWSSigV4Credential) bool {
+	return ac.accessKeyID == target.accessKeyID &&
+		ac.secretAccessKey == target.secretAccessKey &&
+		ac.sessionToken == target.sessionToken &&
+		ac.region == target.region &&
+		ac.service == target.service
+}
+
+// Close terminates internal processes before destroyed.
+func (*AWSSigV4Credential) Close() error {
+	return nil
+}
+
+// buildCanonicalRequest builds the canonical request string and the list of signed headers.
+func (ac *AWSSigV4Credential) buildCanonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	canonicalHeaders, signedHeaders := buildCanonicalHeaders(req)
+
+	return strings.Join([]string{
+		req.Method,
+		ac.buildCanonicalURI(req),
+		buildCanonicalQuery(req.URL.RawQuery),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// buildCanonicalURI encodes the request path.
+// The path is encoded twice except for Amazon S3 which only gets encoded once.
+func (ac *AWSSigV4Credential) buildCanonicalURI(req *http.Request) string {
+	uri := req.URL.Opaque
+	if uri == "" {
+		uri = req.URL.EscapedPath()
+	}
+
+	if uri == "" {
+		return "/"
+	}
+
+	if ac.service == "s3" {
+		return uri
+	}
+
+	return escape(uri, false)
+}
+
+func (ac *AWSSigV4Credential) deriveSigningKey(signingTime time.Time) []byte {
+	key := hmacSHA256([]byte("AWS4"+ac.secretAccessKey), []byte(signingTime.Format(shortDateFormat)))
+	key = hmacSHA256(key, []byte(ac.region))
+	key = hmacSHA256(key, []byte(ac.service))
+
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+func buildStringToSign(amzDate string, scope string, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+
+	return strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// buildCanonicalQuery sorts and encodes query parameters.
+func buildCanonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	pairs := make([][2]string, 0, len(params))
+
+	for _, param := range params {
+		if param == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(param, "=")
+		pairs = append(pairs, [2]string{
+			escape(unescapeQuery(key), true),
+			escape(unescapeQuery(value), true),
+		})
+	}
+
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		if result := strings.Compare(a[0], b[0]); result != 0 {
+			return result
+		}
+
+		return strings.Compare(a[1], b[1])
+	})
+
+	var sb strings.Builder
+
+	for i, pair := range pairs {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+
+		sb.WriteString(pair[0])
+		sb.WriteByte('=')
+		sb.WriteString(pair[1])
+	}
+
+	return sb.String()
+}
+
+// buildCanonicalHeaders builds the canonical headers string and the signed headers list.
+func buildCanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{
+		"host": host,
+	}
+
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+		if ignoredHeaders[name] {
+			continue
+		}
+
+		trimmedValues := make([]string, len(values))
+
+		for i, value := range values {
+			trimmedValues[i] = strings.Join(strings.Fields(value), " ")
+		}
+
+		headers[name] = strings.Join(trimmedValues, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var sb strings.Builder
+
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(headers[name])
+		sb.WriteByte('\n')
+	}
+
+	return sb.String(), strings.Join(names, ";")
+}
+
+// hashRequestBody computes the SHA-256 hash of the request body and rewinds the body.
+func hashRequestBody(req *http.Request) (string, error) {
+	hash := sha256.New()
+
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+	case req.GetBody != nil:
+		body, err := req.GetBody()
+		if err != nil {
+			return "", err
+		}
+
+		_, err = io.Copy(hash, body)
+
+		_ = body.Close()
+
+		if err != nil {
+			return "", err
+		}
+	default:
+		bodyBytes, err := io.ReadAll(req.Body)
+
+		_ = req.Body.Close()
+
+		if err != nil {
+			return "", err
+		}
+
+		hash.Write(bodyBytes)
+
+		req.ContentLength = int64(len(bodyBytes))
+		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func hmacSHA256(key []byte, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// escape encodes the value using the URI encoding rules of AWS SigV4.
+// All characters except unreserved characters are percent-encoded.
+// The slash character is encoded if encodeSlash is true.
+func escape(value string, encodeSlash bool) string {
+	const upperHex = "0123456789ABCDEF"
+
+	var sb strings.Builder
+
+	sb.Grow(len(value))
+
+	for i := range len(value) {
+		c := value[i]
+
+		if isUnreserved(c) || (c == '/' && !encodeSlash) {
+			sb.WriteByte(c)
+
+			continue
+		}
+
+		sb.WriteByte('%')
+		sb.WriteByte(upperHex[c>>4])
+		sb.WriteByte(upperHex[c&15])
+	}
+
+	return sb.String()
+}
+
+func unescapeQuery(value string) string {
+	result, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+
+	return result
+}
+
+func isUnreserved(c byte) bool {
+	return (c >= 'A' && c <= 'Z') ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= '0' && c <= '9') ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
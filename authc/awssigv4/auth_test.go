@@ -0,0 +1,324 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awssigv4
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+// Test vectors from the AWS Signature Version 4 test suite.
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testAmzDate         = "20150830T123600Z"
+	testScope           = "AKIDEXAMPLE/20150830/us-east-1/service/aws4_request"
+)
+
+func newTestCredential(t *testing.T, sessionToken string) *AWSSigV4Credential {
+	t.Helper()
+
+	config := NewAWSSigV4Config(
+		goenvconf.NewEnvStringValue(testAccessKeyID),
+		goenvconf.NewEnvStringValue(testSecretAccessKey),
+		"us-east-1",
+		"service",
+	)
+
+	if sessionToken != "" {
+		token := goenvconf.NewEnvStringValue(sessionToken)
+		config.SessionToken = &token
+	}
+
+	cred, err := NewAWSSigV4Credential(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cred.now = func() time.Time {
+		return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	}
+
+	return cred
+}
+
+func TestAWSSigV4Credential_TestSuite(t *testing.T) {
+	testCases := []struct {
+		Name              string
+		Method            string
+		URL               string
+		Header            http.Header
+		Body              string
+		ExpectedCanonical string
+		ExpectedSignature string
+		ExpectedSigned    string
+	}{
+		{
+			Name:   "get-vanilla",
+			Method: http.MethodGet,
+			URL:    "https://example.amazonaws.com/",
+			ExpectedCanonical: "GET\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			ExpectedSigned:    "host;x-amz-date",
+			ExpectedSignature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			Name:   "get-vanilla-query-order-key-case",
+			Method: http.MethodGet,
+			URL:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			ExpectedCanonical: "GET\n/\nParam1=value1&Param2=value2\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			ExpectedSigned:    "host;x-amz-date",
+			ExpectedSignature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			Name:   "post-vanilla",
+			Method: http.MethodPost,
+			URL:    "https://example.amazonaws.com/",
+			ExpectedCanonical: "POST\n/\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+				"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			ExpectedSigned:    "host;x-amz-date",
+			ExpectedSignature: "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			Name:   "post-x-www-form-urlencoded",
+			Method: http.MethodPost,
+			URL:    "https://example.amazonaws.com/",
+			Header: http.Header{
+				"Content-Type": []string{"application/x-www-form-urlencoded"},
+			},
+			Body: "Param1=value1",
+			ExpectedCanonical: "POST\n/\n\ncontent-type:application/x-www-form-urlencoded\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"content-type;host;x-amz-date\n" +
+				"9095672bbd1f56dfc5b65f3e153adc8731a4a654192329106275f4c7b24d0b6e",
+			ExpectedSigned:    "content-type;host;x-amz-date",
+			ExpectedSignature: "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			cred := newTestCredential(t, "")
+
+			var body io.Reader
+			if tc.Body != "" {
+				body = strings.NewReader(tc.Body)
+			}
+
+			req, err := http.NewRequest(tc.Method, tc.URL, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for key, values := range tc.Header {
+				req.Header[key] = values
+			}
+
+			err = cred.Authenticate(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payloadHash, err := hashRequestBody(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			canonical, signedHeaders := cred.buildCanonicalRequest(req, payloadHash)
+			if canonical != tc.ExpectedCanonical {
+				t.Errorf("expected canonical request:\n%s\ngot:\n%s", tc.ExpectedCanonical, canonical)
+			}
+
+			if signedHeaders != tc.ExpectedSigned {
+				t.Errorf("expected signed headers %s, got: %s", tc.ExpectedSigned, signedHeaders)
+			}
+
+			if req.Header.Get(headerAmzDate) != testAmzDate {
+				t.Errorf("expected X-Amz-Date %s, got: %s", testAmzDate, req.Header.Get(headerAmzDate))
+			}
+
+			expectedAuth := "AWS4-HMAC-SHA256 Credential=" + testScope +
+				", SignedHeaders=" + tc.ExpectedSigned +
+				", Signature=" + tc.ExpectedSignature
+
+			if authHeader := req.Header.Get(headerAuthorization); authHeader != expectedAuth {
+				t.Errorf("expected Authorization:\n%s\ngot:\n%s", expectedAuth, authHeader)
+			}
+		})
+	}
+}
+
+func TestAWSSigV4Credential_Authenticate(t *testing.T) {
+	t.Run("signs the session token", func(t *testing.T) {
+		cred := newTestCredential(t, "session-token")
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cred.Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Header.Get(headerAmzToken) != "session-token" {
+			t.Errorf("expected the session token header, got: %s", req.Header.Get(headerAmzToken))
+		}
+
+		if !strings.Contains(req.Header.Get(headerAuthorization), "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+			t.Errorf("expected the session token to be signed, got: %s", req.Header.Get(headerAuthorization))
+		}
+	})
+
+	t.Run("rewinds non-replayable bodies", func(t *testing.T) {
+		cred := newTestCredential(t, "")
+
+		req, err := http.NewRequest(
+			http.MethodPost,
+			"https://example.amazonaws.com/",
+			io.MultiReader(strings.NewReader("Param1=value1")),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		err = cred.Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasSuffix(
+			req.Header.Get(headerAuthorization),
+			"Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		) {
+			t.Errorf("unexpected signature: %s", req.Header.Get(headerAuthorization))
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != "Param1=value1" || req.ContentLength != int64(len(body)) {
+			t.Errorf("expected the body to be rewound, got: %q", string(body))
+		}
+	})
+
+	t.Run("ignores headers added after signing", func(t *testing.T) {
+		cred := newTestCredential(t, "")
+
+		req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("User-Agent", "gohttpc")
+		req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+		err = cred.Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasSuffix(
+			req.Header.Get(headerAuthorization),
+			"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		) {
+			t.Errorf("unexpected signature: %s", req.Header.Get(headerAuthorization))
+		}
+	})
+}
+
+func TestNewAWSSigV4Credential(t *testing.T) {
+	t.Run("returns error when the credential is empty", func(t *testing.T) {
+		config := NewAWSSigV4Config(
+			goenvconf.NewEnvStringVariable("AWS_TEST_EMPTY_ACCESS_KEY"),
+			goenvconf.NewEnvStringValue(testSecretAccessKey),
+			"us-east-1",
+			"service",
+		)
+
+		_, err := NewAWSSigV4Credential(config, nil)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("loads credentials from environment variables", func(t *testing.T) {
+		t.Setenv("AWS_TEST_ACCESS_KEY", testAccessKeyID)
+		t.Setenv("AWS_TEST_SECRET_KEY", testSecretAccessKey)
+
+		config := NewAWSSigV4Config(
+			goenvconf.NewEnvStringVariable("AWS_TEST_ACCESS_KEY"),
+			goenvconf.NewEnvStringVariable("AWS_TEST_SECRET_KEY"),
+			"us-east-1",
+			"service",
+		)
+
+		cred, err := NewAWSSigV4Credential(config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cred.accessKeyID != testAccessKeyID || cred.secretAccessKey != testSecretAccessKey {
+			t.Errorf("unexpected credentials: %s", cred.accessKeyID)
+		}
+	})
+}
+
+func TestAWSSigV4Credential_Client(t *testing.T) {
+	var receivedAuth, receivedBody string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+		receivedAuth = r.Header.Get(headerAuthorization)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithAuthenticator(newTestCredential(t, "")))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	req := client.R(http.MethodPost, server.URL+"/path?b=2&a=1")
+	req.SetBody(io.MultiReader(strings.NewReader("hello world")))
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	if receivedBody != "hello world" {
+		t.Errorf("expected the full body to be sent, got: %q", receivedBody)
+	}
+
+	if !strings.HasPrefix(receivedAuth, "AWS4-HMAC-SHA256 Credential="+testScope+", SignedHeaders=host;x-amz-date, Signature=") {
+		t.Errorf("unexpected Authorization header: %s", receivedAuth)
+	}
+}
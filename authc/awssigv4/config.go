@@ -0,0 +1,113 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awssigv4
+
+import (
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/goutils"
+)
+
+// AWSSigV4Config contains configurations for the [AWS Signature Version 4] authentication.
+//
+// [AWS Signature Version 4]: https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv.html
+type AWSSigV4Config struct {
+	// Type of the AWS SigV4 authenticator.
+	Type authscheme.HTTPClientAuthType `json:"type" jsonschema:"type=string,enum=awsSigV4" yaml:"type"`
+	// The AWS access key ID.
+	AccessKeyID goenvconf.EnvString `json:"accessKeyId" yaml:"accessKeyId"`
+	// The AWS secret access key.
+	SecretAccessKey goenvconf.EnvString `json:"secretAccessKey" yaml:"secretAccessKey"`
+	// The optional session token of temporary credentials.
+	SessionToken *goenvconf.EnvString `json:"sessionToken,omitempty" yaml:"sessionToken,omitempty"`
+	// The AWS region to sign requests for, e.g. us-east-1.
+	Region string `json:"region" yaml:"region"`
+	// The name of the AWS service to sign requests for, e.g. execute-api.
+	Service string `json:"service" yaml:"service"`
+}
+
+var _ authscheme.HTTPClientAuthenticatorConfig = (*AWSSigV4Config)(nil)
+
+// NewAWSSigV4Config creates a new AWSSigV4Config instance.
+func NewAWSSigV4Config(
+	accessKeyID goenvconf.EnvString,
+	secretAccessKey goenvconf.EnvString,
+	region string,
+	service string,
+) *AWSSigV4Config {
+	return &AWSSigV4Config{
+		Type:            authscheme.AWSSigV4Scheme,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Region:          region,
+		Service:         service,
+	}
+}
+
+// IsZero if the current instance is empty.
+func (asc AWSSigV4Config) IsZero() bool {
+	return asc.Type == 0 &&
+		asc.AccessKeyID.IsZero() &&
+		asc.SecretAccessKey.IsZero() &&
+		(asc.SessionToken == nil || asc.SessionToken.IsZero()) &&
+		asc.Region == "" &&
+		asc.Service == ""
+}
+
+// Equal checks if the target value is equal.
+func (asc AWSSigV4Config) Equal(target AWSSigV4Config) bool {
+	return asc.Type == target.Type &&
+		asc.AccessKeyID.Equal(target.AccessKeyID) &&
+		asc.SecretAccessKey.Equal(target.SecretAccessKey) &&
+		goutils.EqualPtr(asc.SessionToken, target.SessionToken) &&
+		asc.Region == target.Region &&
+		asc.Service == target.Service
+}
+
+// Validate if the current instance is valid.
+func (asc AWSSigV4Config) Validate(strict bool) error {
+	authType := asc.GetType()
+
+	if asc.Type != authType {
+		return authscheme.NewUnmatchedSecuritySchemeError(authType, asc.Type)
+	}
+
+	if asc.Region == "" {
+		return authscheme.NewRequiredSecurityFieldError(authType, "region")
+	}
+
+	if asc.Service == "" {
+		return authscheme.NewRequiredSecurityFieldError(authType, "service")
+	}
+
+	if !strict {
+		return nil
+	}
+
+	if asc.AccessKeyID.IsZero() {
+		return authscheme.NewRequiredSecurityFieldError(authType, "accessKeyId")
+	}
+
+	if asc.SecretAccessKey.IsZero() {
+		return authscheme.NewRequiredSecurityFieldError(authType, "secretAccessKey")
+	}
+
+	return nil
+}
+
+// GetType get the type of security scheme.
+func (AWSSigV4Config) GetType() authscheme.HTTPClientAuthType {
+	return authscheme.AWSSigV4Scheme
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awssigv4
+
+import (
+	"testing"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc/authc/authscheme"
+)
+
+func TestAWSSigV4Config_Validate(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Config    AWSSigV4Config
+		Strict    bool
+		ExpectErr bool
+	}{
+		{
+			Name: "valid_strict",
+			Config: *NewAWSSigV4Config(
+				goenvconf.NewEnvStringValue("access"),
+				goenvconf.NewEnvStringValue("secret"),
+				"us-east-1",
+				"execute-api",
+			),
+			Strict: true,
+		},
+		{
+			Name: "unmatched_type",
+			Config: AWSSigV4Config{
+				Type:    authscheme.BasicAuthScheme,
+				Region:  "us-east-1",
+				Service: "execute-api",
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "missing_region",
+			Config: AWSSigV4Config{
+				Type:    authscheme.AWSSigV4Scheme,
+				Service: "execute-api",
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "missing_service",
+			Config: AWSSigV4Config{
+				Type:   authscheme.AWSSigV4Scheme,
+				Region: "us-east-1",
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "missing_credentials_non_strict",
+			Config: AWSSigV4Config{
+				Type:    authscheme.AWSSigV4Scheme,
+				Region:  "us-east-1",
+				Service: "execute-api",
+			},
+		},
+		{
+			Name: "missing_credentials_strict",
+			Config: AWSSigV4Config{
+				Type:    authscheme.AWSSigV4Scheme,
+				Region:  "us-east-1",
+				Service: "execute-api",
+			},
+			Strict:    true,
+			ExpectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Config.Validate(tc.Strict)
+			if tc.ExpectErr && err == nil {
+				t.Error("expected error, got nil")
+			} else if !tc.ExpectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAWSSigV4Config_IsZero(t *testing.T) {
+	if !(AWSSigV4Config{}).IsZero() {
+		t.Error("expected IsZero to return true")
+	}
+
+	if (AWSSigV4Config{Region: "us-east-1"}).IsZero() {
+		t.Error("expected IsZero to return false")
+	}
+}
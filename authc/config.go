@@ -21,7 +21,9 @@ import (
 	"reflect"
 
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/authc/awssigv4"
 	"github.com/relychan/gohttpc/authc/basicauth"
+	"github.com/relychan/gohttpc/authc/digestauth"
 	"github.com/relychan/gohttpc/authc/httpauth"
 	"github.com/relychan/gohttpc/authc/oauth2scheme"
 	"github.com/relychan/goutils"
@@ -89,6 +91,24 @@ func (j *HTTPClientAuthConfig) UnmarshalJSON(b []byte) error {
 			return err
 		}
 
+		j.HTTPClientAuthenticatorConfig = &config
+	case authscheme.AWSSigV4Scheme:
+		var config awssigv4.AWSSigV4Config
+
+		err := json.Unmarshal(b, &config)
+		if err != nil {
+			return err
+		}
+
+		j.HTTPClientAuthenticatorConfig = &config
+	case authscheme.DigestAuthScheme:
+		var config digestauth.DigestAuthConfig
+
+		err := json.Unmarshal(b, &config)
+		if err != nil {
+			return err
+		}
+
 		j.HTTPClientAuthenticatorConfig = &config
 	default:
 		return fmt.Errorf("%w: %s", errUnsupportedSecurityScheme, rawScheme.Type)
@@ -145,6 +165,24 @@ func (j *HTTPClientAuthConfig) UnmarshalYAML(value *yaml.Node) error {
 			return err
 		}
 
+		j.HTTPClientAuthenticatorConfig = &config
+	case authscheme.AWSSigV4Scheme:
+		var config awssigv4.AWSSigV4Config
+
+		err := value.Load(&config)
+		if err != nil {
+			return err
+		}
+
+		j.HTTPClientAuthenticatorConfig = &config
+	case authscheme.DigestAuthScheme:
+		var config digestauth.DigestAuthConfig
+
+		err := value.Load(&config)
+		if err != nil {
+			return err
+		}
+
 		j.HTTPClientAuthenticatorConfig = &config
 	default:
 		return fmt.Errorf("%w: %s", errUnsupportedSecurityScheme, *rawAuthType)
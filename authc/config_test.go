@@ -21,7 +21,9 @@ import (
 
 	"github.com/hasura/goenvconf"
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/authc/awssigv4"
 	"github.com/relychan/gohttpc/authc/basicauth"
+	"github.com/relychan/gohttpc/authc/digestauth"
 	"github.com/relychan/gohttpc/authc/httpauth"
 	"github.com/relychan/gohttpc/authc/oauth2scheme"
 	"go.yaml.in/yaml/v4"
@@ -119,6 +121,64 @@ func TestHTTPClientAuthConfig_UnmarshalJSON(t *testing.T) {
 		}
 	})
 
+	t.Run("unmarshals aws sigv4 config from JSON", func(t *testing.T) {
+		jsonData := `{
+			"type": "awsSigV4",
+			"accessKeyId": {"value": "AKIDEXAMPLE"},
+			"secretAccessKey": {"env": "AWS_SECRET_ACCESS_KEY"},
+			"region": "us-east-1",
+			"service": "execute-api"
+		}`
+
+		var config HTTPClientAuthConfig
+		err := json.Unmarshal([]byte(jsonData), &config)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if config.GetType() != authscheme.AWSSigV4Scheme {
+			t.Errorf("expected type %s, got %s", authscheme.AWSSigV4Scheme, config.GetType())
+		}
+
+		sigV4Config, ok := config.HTTPClientAuthenticatorConfig.(*awssigv4.AWSSigV4Config)
+		if !ok {
+			t.Fatalf("expected AWSSigV4Config, got %T", config.HTTPClientAuthenticatorConfig)
+		}
+
+		accessKeyID, _ := sigV4Config.AccessKeyID.Get()
+		if accessKeyID != "AKIDEXAMPLE" || sigV4Config.Region != "us-east-1" || sigV4Config.Service != "execute-api" {
+			t.Errorf("unexpected config: %+v", sigV4Config)
+		}
+	})
+
+	t.Run("unmarshals digest config from JSON", func(t *testing.T) {
+		jsonData := `{
+			"type": "digest",
+			"username": {"value": "user"},
+			"password": {"env": "DIGEST_PASSWORD"}
+		}`
+
+		var config HTTPClientAuthConfig
+		err := json.Unmarshal([]byte(jsonData), &config)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if config.GetType() != authscheme.DigestAuthScheme {
+			t.Errorf("expected type %s, got %s", authscheme.DigestAuthScheme, config.GetType())
+		}
+
+		digestConfig, ok := config.HTTPClientAuthenticatorConfig.(*digestauth.DigestAuthConfig)
+		if !ok {
+			t.Fatalf("expected DigestAuthConfig, got %T", config.HTTPClientAuthenticatorConfig)
+		}
+
+		username, _ := digestConfig.Username.Get()
+		if username != "user" {
+			t.Errorf("unexpected config: %+v", digestConfig)
+		}
+	})
+
 	t.Run("returns error for invalid JSON", func(t *testing.T) {
 		jsonData := `{invalid json}`
 
@@ -288,6 +348,61 @@ flows:
 		}
 	})
 
+	t.Run("unmarshals aws sigv4 config from YAML", func(t *testing.T) {
+		yamlData := `
+type: awsSigV4
+accessKeyId:
+  value: AKIDEXAMPLE
+secretAccessKey:
+  env: AWS_SECRET_ACCESS_KEY
+region: us-east-1
+service: execute-api
+`
+
+		var config HTTPClientAuthConfig
+		err := yaml.Unmarshal([]byte(yamlData), &config)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if config.GetType() != authscheme.AWSSigV4Scheme {
+			t.Errorf("expected type %s, got %s", authscheme.AWSSigV4Scheme, config.GetType())
+		}
+
+		sigV4Config, ok := config.HTTPClientAuthenticatorConfig.(*awssigv4.AWSSigV4Config)
+		if !ok {
+			t.Fatalf("expected AWSSigV4Config, got %T", config.HTTPClientAuthenticatorConfig)
+		}
+
+		if sigV4Config.Region != "us-east-1" || sigV4Config.Service != "execute-api" {
+			t.Errorf("unexpected config: %+v", sigV4Config)
+		}
+	})
+
+	t.Run("unmarshals digest config from YAML", func(t *testing.T) {
+		yamlData := `
+type: digest
+username:
+  value: user
+password:
+  env: DIGEST_PASSWORD
+`
+
+		var config HTTPClientAuthConfig
+		err := yaml.Unmarshal([]byte(yamlData), &config)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if config.GetType() != authscheme.DigestAuthScheme {
+			t.Errorf("expected type %s, got %s", authscheme.DigestAuthScheme, config.GetType())
+		}
+
+		if _, ok := config.HTTPClientAuthenticatorConfig.(*digestauth.DigestAuthConfig); !ok {
+			t.Fatalf("expected DigestAuthConfig, got %T", config.HTTPClientAuthenticatorConfig)
+		}
+	})
+
 	t.Run("returns error for invalid YAML", func(t *testing.T) {
 		yamlData := `
 type: basic
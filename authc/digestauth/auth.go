@@ -0,0 +1,291 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package digestauth implements authentication interfaces for the HTTP Digest scheme defined in RFC 7616.
+package digestauth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/relychan/gohttpc/authc/authscheme"
+)
+
+// DigestCredential represents the HTTP Digest credential.
+//
+// The digest scheme requires a server challenge before the first request can be authenticated.
+// If no challenge was received yet, Authenticate sends a HEAD probe request to the same URL
+// to obtain it, so the request itself is never sent twice. The challenge is cached and reused
+// for subsequent requests with an increasing nonce count.
+// The gohttpc client answers new challenges of 401 responses, e.g. a stale nonce, with [DigestCredential.HandleChallenge]
+// and sends the probe with its own HTTP client. For other HTTP clients, wrap the transport with [DigestCredential.Transport].
+type DigestCredential struct {
+	username   string
+	password   string
+	httpClient *http.Client
+
+	lock       sync.Mutex
+	challenge  *challenge
+	nonceCount uint32
+}
+
+var (
+	_ authscheme.HTTPClientAuthenticator    = (*DigestCredential)(nil)
+	_ authscheme.HTTPClientChallengeHandler = (*DigestCredential)(nil)
+	_ authscheme.HTTPClientSetter           = (*DigestCredential)(nil)
+)
+
+// NewDigestCredential creates a new DigestCredential instance.
+func NewDigestCredential(
+	config *DigestAuthConfig,
+	options *authscheme.HTTPClientAuthenticatorOptions,
+) (*DigestCredential, error) {
+	if options == nil {
+		options = authscheme.NewHTTPClientAuthenticatorOptions()
+	}
+
+	getEnv := options.GetEnvFunc()
+
+	username, err := config.Username.GetCustom(getEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest credential. Invalid username: %w", err)
+	}
+
+	password, err := config.Password.GetCustom(getEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest credential. Invalid password: %w", err)
+	}
+
+	if username == "" {
+		return nil, authscheme.ErrAuthCredentialEmpty
+	}
+
+	return &DigestCredential{
+		username:   username,
+		password:   password,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// SetHTTPClient sets the HTTP client to send the probe request for the digest challenge.
+// The gohttpc client sets its own HTTP client when it is created.
+func (dc *DigestCredential) SetHTTPClient(client *http.Client) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	dc.httpClient = client
+}
+
+// Authenticate computes the digest response and sets the Authorization header to the request.
+func (dc *DigestCredential) Authenticate(
+	req *http.Request,
+	options ...authscheme.AuthenticateOption,
+) error {
+	dc.lock.Lock()
+	hasChallenge := dc.challenge != nil
+	dc.lock.Unlock()
+
+	if !hasChallenge {
+		required, err := dc.probe(req)
+		if err != nil || !required {
+			return err
+		}
+	}
+
+	return dc.authorize(req)
+}
+
+// HandleChallenge updates the cached challenge from the WWW-Authenticate header of a 401 response.
+// Returns true if a supported digest challenge was found.
+func (dc *DigestCredential) HandleChallenge(resp *http.Response) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	ch, err := parseChallenges(resp.Header)
+	if err != nil {
+		return false
+	}
+
+	dc.setChallenge(ch)
+
+	return true
+}
+
+// Transport wraps the base round tripper to answer digest challenges.
+// When the server responds 401 with a digest challenge, the request is retried once with the new challenge.
+// Requests with a body which can't be rewound are not retried.
+func (dc *DigestCredential) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &digestTransport{
+		credential: dc,
+		base:       base,
+	}
+}
+
+// Equal checks if the target value is equal.
+func (dc *DigestCredential) Equal(target *DigestCredential) bool {
+	if dc == nil || target == nil {
+		return dc == target
+	}
+
+	return dc.username == target.username &&
+		dc.password == target.password
+}
+
+// Close terminates internal processes before destroyed.
+func (*DigestCredential) Close() error {
+	return nil
+}
+
+func (dc *DigestCredential) setChallenge(ch *challenge) {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	dc.challenge = ch
+	dc.nonceCount = 0
+}
+
+// probe sends a HEAD request to get the digest challenge.
+// HEAD is safe, so the probe never repeats the side effects of the request.
+// Returns false if the server does not challenge the probe.
+func (dc *DigestCredential) probe(req *http.Request) (bool, error) {
+	probeReq, err := http.NewRequestWithContext(req.Context(), http.MethodHead, req.URL.String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	probeReq.Host = req.Host
+
+	dc.lock.Lock()
+	httpClient := dc.httpClient
+	dc.lock.Unlock()
+
+	resp, err := httpClient.Do(probeReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to request the digest challenge: %w", err)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false, nil
+	}
+
+	ch, err := parseChallenges(resp.Header)
+	if err != nil {
+		return false, err
+	}
+
+	dc.setChallenge(ch)
+
+	return true, nil
+}
+
+func (dc *DigestCredential) authorize(req *http.Request) error {
+	cnonce, err := newClientNonce()
+	if err != nil {
+		return err
+	}
+
+	dc.lock.Lock()
+	ch := dc.challenge
+	dc.nonceCount++
+	nonceCount := dc.nonceCount
+	dc.lock.Unlock()
+
+	req.Header.Set(
+		headerAuthorization,
+		ch.authorization(dc.username, dc.password, req.Method, req.URL.RequestURI(), nonceCount, cnonce),
+	)
+
+	return nil
+}
+
+func newClientNonce() (string, error) {
+	buf := make([]byte, 16)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// digestTransport is a round tripper which retries the request once on the digest challenge.
+type digestTransport struct {
+	credential *DigestCredential
+	base       http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (dt *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dt.credential.lock.Lock()
+	hasChallenge := dt.credential.challenge != nil
+	dt.credential.lock.Unlock()
+
+	// The request must be cloned to modify the header.
+	nextReq := req.Clone(req.Context())
+
+	if hasChallenge {
+		err := dt.credential.authorize(nextReq)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := dt.base.RoundTrip(nextReq)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) ||
+		!dt.credential.HandleChallenge(resp) {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil //nolint:nilerr
+		}
+
+		retryReq.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	err = dt.credential.authorize(retryReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return dt.base.RoundTrip(retryReq)
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digestauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/goutils"
+)
+
+const (
+	testUsername = "Mufasa"
+	testPassword = "Circle of Life"
+	testRealm    = "test@example.com"
+)
+
+// digestServer is a test server which verifies the digest authorization.
+type digestServer struct {
+	algorithm string
+
+	lock       sync.Mutex
+	nonce      string
+	lastNC     string
+	challenges atomic.Int32
+	bodies     []string
+	// methods of the requests which were challenged.
+	challengedMethods []string
+}
+
+func (ds *digestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	auth := r.Header.Get(headerAuthorization)
+	if !strings.HasPrefix(auth, "Digest ") {
+		ds.challengedMethods = append(ds.challengedMethods, r.Method)
+		ds.challenge(w, false)
+
+		return
+	}
+
+	params := parseParams(strings.TrimPrefix(auth, "Digest "))
+	if params["nonce"] != ds.nonce {
+		ds.challengedMethods = append(ds.challengedMethods, r.Method)
+		ds.challenge(w, true)
+
+		return
+	}
+
+	ch := &challenge{
+		Realm:     testRealm,
+		Nonce:     ds.nonce,
+		Opaque:    "opaque-value",
+		Algorithm: ds.algorithm,
+		Qop:       qopAuth,
+	}
+
+	ha1 := ch.hashHex(testUsername + ":" + testRealm + ":" + testPassword)
+	ha2 := ch.hashHex(r.Method + ":" + params["uri"])
+	expected := ch.hashHex(
+		ha1 + ":" + ds.nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":" + qopAuth + ":" + ha2,
+	)
+
+	if params["username"] != testUsername ||
+		params["uri"] != r.URL.RequestURI() ||
+		params["opaque"] != "opaque-value" ||
+		params["response"] != expected ||
+		params["nc"] <= ds.lastNC {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	ds.lastNC = params["nc"]
+
+	body, _ := io.ReadAll(r.Body)
+	ds.bodies = append(ds.bodies, string(body))
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (ds *digestServer) challenge(w http.ResponseWriter, stale bool) {
+	ds.challenges.Add(1)
+	ds.lastNC = ""
+
+	if stale {
+		ds.nonce += "x"
+	}
+
+	value := `Digest realm="` + testRealm + `", qop="auth", algorithm=` + ds.algorithm +
+		`, nonce="` + ds.nonce + `", opaque="opaque-value"`
+	if stale {
+		value += ", stale=true"
+	}
+
+	w.Header().Set(headerWWWAuthenticate, value)
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func (ds *digestServer) rotateNonce() {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	ds.nonce += "r"
+}
+
+func newTestCredential(t *testing.T) *DigestCredential {
+	t.Helper()
+
+	credential, err := NewDigestCredential(NewDigestAuthConfig(
+		goenvconf.NewEnvStringValue(testUsername),
+		goenvconf.NewEnvStringValue(testPassword),
+	), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return credential
+}
+
+func TestDigestCredential_Authenticate(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmMD5, AlgorithmSHA256} {
+		t.Run(algorithm, func(t *testing.T) {
+			ds := &digestServer{algorithm: algorithm, nonce: "nonce-" + algorithm}
+			server := httptest.NewServer(ds)
+			defer server.Close()
+
+			credential := newTestCredential(t)
+			client := gohttpc.NewClient(gohttpc.WithAuthenticator(credential))
+
+			for i := range 3 {
+				req := client.R(http.MethodPost, server.URL+"/resource?id=1")
+				req.SetBody(strings.NewReader("hello"))
+
+				resp, err := req.Execute(context.Background())
+				if err != nil {
+					t.Fatalf("request %d: %v", i, err)
+				}
+
+				goutils.CloseResponse(resp)
+
+				if resp.StatusCode != http.StatusOK {
+					t.Fatalf("request %d: expected status 200, got: %d", i, resp.StatusCode)
+				}
+			}
+
+			// The challenge is requested once and reused with an increasing nonce count.
+			if ds.challenges.Load() != 1 {
+				t.Errorf("expected 1 challenge, got: %d", ds.challenges.Load())
+			}
+
+			if len(ds.bodies) != 3 || ds.bodies[0] != "hello" {
+				t.Errorf("unexpected bodies: %v", ds.bodies)
+			}
+		})
+	}
+
+	t.Run("skips when the server does not require authentication", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(headerAuthorization) != "" {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		req := httptest.NewRequest(http.MethodGet, server.URL, nil)
+
+		err := newTestCredential(t).Authenticate(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req.Header.Get(headerAuthorization) != "" {
+			t.Errorf("expected no Authorization header, got: %s", req.Header.Get(headerAuthorization))
+		}
+	})
+
+	t.Run("returns error for unsupported challenges", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerWWWAuthenticate, `Digest nonce="abc", qop="auth-int"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		req := httptest.NewRequest(http.MethodGet, server.URL, nil)
+
+		err := newTestCredential(t).Authenticate(req)
+		if !errors.Is(err, ErrUnsupportedDigestQop) {
+			t.Errorf("expected ErrUnsupportedDigestQop, got: %v", err)
+		}
+	})
+}
+
+func TestDigestCredential_Transport(t *testing.T) {
+	ds := &digestServer{algorithm: AlgorithmSHA256, nonce: "nonce"}
+	server := httptest.NewServer(ds)
+	defer server.Close()
+
+	credential := newTestCredential(t)
+	httpClient := &http.Client{Transport: credential.Transport(nil)}
+
+	doRequest := func(body string) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(
+			context.Background(),
+			http.MethodPut,
+			server.URL+"/resource",
+			strings.NewReader(body),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got: %d", resp.StatusCode)
+		}
+	}
+
+	doRequest("first")
+	doRequest("second")
+
+	// The server rotates the nonce. The transport answers the stale challenge and replays the body.
+	ds.rotateNonce()
+	doRequest("third")
+
+	if ds.challenges.Load() != 2 {
+		t.Errorf("expected 2 challenges, got: %d", ds.challenges.Load())
+	}
+
+	expectedBodies := []string{"first", "second", "third"}
+	if strings.Join(ds.bodies, ",") != strings.Join(expectedBodies, ",") {
+		t.Errorf("expected bodies %v, got: %v", expectedBodies, ds.bodies)
+	}
+}
+
+// countingTransport counts the requests by method.
+type countingTransport struct {
+	lock    sync.Mutex
+	methods []string
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.lock.Lock()
+	ct.methods = append(ct.methods, req.Method)
+	ct.lock.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDigestCredential_StaleNonceWithClient(t *testing.T) {
+	ds := &digestServer{algorithm: AlgorithmSHA256, nonce: "nonce"}
+	server := httptest.NewServer(ds)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	credential := newTestCredential(t)
+	client := gohttpc.NewClient(
+		gohttpc.WithHTTPClient(&http.Client{Transport: transport}),
+		gohttpc.WithAuthenticator(credential),
+	)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	doRequest := func(body string) {
+		t.Helper()
+
+		req := client.R(http.MethodPost, server.URL+"/resource")
+		req.SetBody(strings.NewReader(body))
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got: %d", resp.StatusCode)
+		}
+	}
+
+	doRequest("first")
+	doRequest("second")
+
+	// The server rotates the nonce. The client answers the stale challenge and replays the body.
+	ds.rotateNonce()
+	doRequest("third")
+	doRequest("fourth")
+
+	if ds.challenges.Load() != 2 {
+		t.Errorf("expected 2 challenges, got: %d", ds.challenges.Load())
+	}
+
+	// The first challenge is requested with a HEAD probe, so the POST request isn't sent twice.
+	expectedMethods := []string{http.MethodHead, http.MethodPost}
+	if strings.Join(ds.challengedMethods, ",") != strings.Join(expectedMethods, ",") {
+		t.Errorf("expected challenged methods %v, got: %v", expectedMethods, ds.challengedMethods)
+	}
+
+	// The probe is sent with the HTTP client of the gohttpc client.
+	expectedTransportMethods := []string{
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPost,
+		http.MethodPost,
+		http.MethodPost,
+		http.MethodPost,
+	}
+	if strings.Join(transport.methods, ",") != strings.Join(expectedTransportMethods, ",") {
+		t.Errorf("expected requests %v, got: %v", expectedTransportMethods, transport.methods)
+	}
+
+	expectedBodies := []string{"first", "second", "third", "fourth"}
+	if strings.Join(ds.bodies, ",") != strings.Join(expectedBodies, ",") {
+		t.Errorf("expected bodies %v, got: %v", expectedBodies, ds.bodies)
+	}
+}
+
+func TestNewDigestCredential(t *testing.T) {
+	t.Run("loads credentials from env", func(t *testing.T) {
+		getEnv := func(name string) (string, error) {
+			return map[string]string{"DIGEST_USER": "user", "DIGEST_PASS": "pass"}[name], nil
+		}
+
+		credential, err := NewDigestCredential(NewDigestAuthConfig(
+			goenvconf.NewEnvStringVariable("DIGEST_USER"),
+			goenvconf.NewEnvStringVariable("DIGEST_PASS"),
+		), authscheme.NewHTTPClientAuthenticatorOptions(authscheme.WithGetEnvFunc(getEnv)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if credential.username != "user" || credential.password != "pass" {
+			t.Errorf("unexpected credential: %s:%s", credential.username, credential.password)
+		}
+	})
+
+	t.Run("returns error for empty username", func(t *testing.T) {
+		_, err := NewDigestCredential(NewDigestAuthConfig(
+			goenvconf.NewEnvStringValue(""),
+			goenvconf.NewEnvStringValue("pass"),
+		), nil)
+		if !errors.Is(err, authscheme.ErrAuthCredentialEmpty) {
+			t.Errorf("expected ErrAuthCredentialEmpty, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digestauth
+
+import (
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerAuthorization   = "Authorization"
+	headerWWWAuthenticate = "WWW-Authenticate"
+	digestPrefix          = "digest "
+	qopAuth               = "auth"
+)
+
+// Supported digest algorithms.
+const (
+	AlgorithmMD5        = "MD5"
+	AlgorithmMD5Sess    = "MD5-sess"
+	AlgorithmSHA256     = "SHA-256"
+	AlgorithmSHA256Sess = "SHA-256-sess"
+)
+
+var (
+	// ErrDigestChallengeNotFound occurs when the response does not contain any digest challenge.
+	ErrDigestChallengeNotFound = errors.New("digest challenge not found")
+	// ErrUnsupportedDigestAlgorithm occurs when the server requires an unsupported digest algorithm.
+	ErrUnsupportedDigestAlgorithm = errors.New("unsupported digest algorithm")
+	// ErrUnsupportedDigestQop occurs when the server does not offer the auth quality of protection.
+	ErrUnsupportedDigestQop = errors.New("unsupported digest qop")
+)
+
+// challenge represents the parameters of a WWW-Authenticate digest challenge.
+type challenge struct {
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Algorithm string
+	Qop       string
+	Stale     bool
+}
+
+// parseChallenges parses all digest challenges from WWW-Authenticate headers
+// and returns the strongest one that is supported.
+func parseChallenges(header http.Header) (*challenge, error) {
+	var (
+		result  *challenge
+		lastErr error
+	)
+
+	for _, value := range header.Values(headerWWWAuthenticate) {
+		if len(value) < len(digestPrefix) ||
+			!strings.EqualFold(value[:len(digestPrefix)], digestPrefix) {
+			continue
+		}
+
+		ch, err := parseChallenge(value[len(digestPrefix):])
+		if err != nil {
+			lastErr = err
+
+			continue
+		}
+
+		if result == nil || algorithmPriority(ch.Algorithm) > algorithmPriority(result.Algorithm) {
+			result = ch
+		}
+	}
+
+	if result != nil {
+		return result, nil
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+
+	return nil, ErrDigestChallengeNotFound
+}
+
+// parseChallenge parses the parameters of a digest challenge.
+func parseChallenge(raw string) (*challenge, error) {
+	params := parseParams(raw)
+
+	ch := &challenge{
+		Realm:     params["realm"],
+		Nonce:     params["nonce"],
+		Opaque:    params["opaque"],
+		Algorithm: AlgorithmMD5,
+		Stale:     strings.EqualFold(params["stale"], "true"),
+	}
+
+	if ch.Nonce == "" {
+		return nil, fmt.Errorf("%w: nonce is empty", ErrDigestChallengeNotFound)
+	}
+
+	if algorithm, ok := params["algorithm"]; ok && algorithm != "" {
+		ch.Algorithm = normalizeAlgorithm(algorithm)
+		if ch.Algorithm == "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedDigestAlgorithm, algorithm)
+		}
+	}
+
+	if qop, ok := params["qop"]; ok {
+		for option := range strings.SplitSeq(qop, ",") {
+			if strings.EqualFold(strings.TrimSpace(option), qopAuth) {
+				ch.Qop = qopAuth
+
+				break
+			}
+		}
+
+		if ch.Qop == "" {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedDigestQop, qop)
+		}
+	}
+
+	return ch, nil
+}
+
+// parseParams parses comma-separated key=value pairs. Values may be quoted strings.
+func parseParams(raw string) map[string]string {
+	result := map[string]string{}
+
+	for raw != "" {
+		raw = strings.TrimLeft(raw, " \t,")
+
+		key, rest, found := strings.Cut(raw, "=")
+		if !found {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+
+		if strings.HasPrefix(rest, `"`) {
+			value, raw = readQuotedString(rest[1:])
+		} else {
+			value, raw, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// readQuotedString reads a quoted string until the closing quote and returns the unescaped value with the remaining input.
+func readQuotedString(raw string) (string, string) {
+	var builder strings.Builder
+
+	for i := 0; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			if i+1 < len(raw) {
+				i++
+				builder.WriteByte(raw[i])
+			}
+		case '"':
+			return builder.String(), raw[i+1:]
+		default:
+			builder.WriteByte(raw[i])
+		}
+	}
+
+	return builder.String(), ""
+}
+
+func normalizeAlgorithm(value string) string {
+	for _, algorithm := range []string{
+		AlgorithmMD5,
+		AlgorithmMD5Sess,
+		AlgorithmSHA256,
+		AlgorithmSHA256Sess,
+	} {
+		if strings.EqualFold(value, algorithm) {
+			return algorithm
+		}
+	}
+
+	return ""
+}
+
+func algorithmPriority(algorithm string) int {
+	switch algorithm {
+	case AlgorithmSHA256, AlgorithmSHA256Sess:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// hashHex returns the lowercase hex digest of the value with the algorithm of the challenge.
+func (ch *challenge) hashHex(value string) string {
+	var h hash.Hash
+
+	switch ch.Algorithm {
+	case AlgorithmSHA256, AlgorithmSHA256Sess:
+		h = sha256.New()
+	default:
+		h = md5.New() //nolint:gosec
+	}
+
+	h.Write([]byte(value))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// authorization computes the value of the Authorization header for the request.
+func (ch *challenge) authorization(
+	username, password, method, uri string,
+	nonceCount uint32,
+	cnonce string,
+) string {
+	ha1 := ch.hashHex(username + ":" + ch.Realm + ":" + password)
+
+	if strings.HasSuffix(ch.Algorithm, "-sess") {
+		ha1 = ch.hashHex(ha1 + ":" + ch.Nonce + ":" + cnonce)
+	}
+
+	ha2 := ch.hashHex(method + ":" + uri)
+	nc := fmt.Sprintf("%08x", nonceCount)
+
+	var response string
+
+	if ch.Qop == "" {
+		response = ch.hashHex(ha1 + ":" + ch.Nonce + ":" + ha2)
+	} else {
+		response = ch.hashHex(ha1 + ":" + ch.Nonce + ":" + nc + ":" + cnonce + ":" + ch.Qop + ":" + ha2)
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("Digest username=")
+	builder.WriteString(quoteString(username))
+	builder.WriteString(", realm=")
+	builder.WriteString(quoteString(ch.Realm))
+	builder.WriteString(", nonce=")
+	builder.WriteString(quoteString(ch.Nonce))
+	builder.WriteString(", uri=")
+	builder.WriteString(quoteString(uri))
+	builder.WriteString(", algorithm=")
+	builder.WriteString(ch.Algorithm)
+	builder.WriteString(", response=")
+	builder.WriteString(quoteString(response))
+
+	if ch.Opaque != "" {
+		builder.WriteString(", opaque=")
+		builder.WriteString(quoteString(ch.Opaque))
+	}
+
+	if ch.Qop != "" {
+		builder.WriteString(", qop=")
+		builder.WriteString(ch.Qop)
+		builder.WriteString(", nc=")
+		builder.WriteString(nc)
+		builder.WriteString(", cnonce=")
+		builder.WriteString(quoteString(cnonce))
+	}
+
+	return builder.String()
+}
+
+func quoteString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digestauth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestChallengeAuthorization(t *testing.T) {
+	// Test vectors from RFC 7616, section 3.9.1.
+	baseChallenge := challenge{
+		Realm:  "http-auth@example.org",
+		Nonce:  "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+		Opaque: "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+		Qop:    qopAuth,
+	}
+
+	testCases := []struct {
+		algorithm string
+		expected  string
+	}{
+		{AlgorithmMD5, `response="8ca523f5e9506fed4657c9700eebdbec"`},
+		{AlgorithmSHA256, `response="753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.algorithm, func(t *testing.T) {
+			ch := baseChallenge
+			ch.Algorithm = tc.algorithm
+
+			result := ch.authorization(
+				"Mufasa",
+				"Circle of Life",
+				http.MethodGet,
+				"/dir/index.html",
+				1,
+				"f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			)
+
+			if !strings.Contains(result, tc.expected) {
+				t.Errorf("expected %s in the header, got: %s", tc.expected, result)
+			}
+
+			for _, part := range []string{
+				"algorithm=" + tc.algorithm,
+				"qop=auth",
+				"nc=00000001",
+				`opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`,
+			} {
+				if !strings.Contains(result, part) {
+					t.Errorf("expected %s in the header, got: %s", part, result)
+				}
+			}
+		})
+	}
+}
+
+func TestParseChallenges(t *testing.T) {
+	t.Run("prefers SHA-256 over MD5", func(t *testing.T) {
+		header := http.Header{}
+		header.Add(headerWWWAuthenticate, `Digest realm="test", qop="auth, auth-int", algorithm=MD5, nonce="abc"`)
+		header.Add(headerWWWAuthenticate, `Digest realm="test", qop="auth", algorithm=SHA-256, nonce="def", stale=TRUE`)
+		header.Add(headerWWWAuthenticate, `Basic realm="test"`)
+
+		ch, err := parseChallenges(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ch.Algorithm != AlgorithmSHA256 || ch.Nonce != "def" || !ch.Stale || ch.Qop != qopAuth {
+			t.Errorf("unexpected challenge: %+v", ch)
+		}
+	})
+
+	t.Run("parses escaped quoted strings", func(t *testing.T) {
+		header := http.Header{}
+		header.Set(headerWWWAuthenticate, `digest realm="a \"quoted\", realm", nonce="abc"`)
+
+		ch, err := parseChallenges(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ch.Realm != `a "quoted", realm` || ch.Algorithm != AlgorithmMD5 || ch.Qop != "" {
+			t.Errorf("unexpected challenge: %+v", ch)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			header   string
+			expected error
+		}{
+			{"no digest challenge", `Basic realm="test"`, ErrDigestChallengeNotFound},
+			{"empty nonce", `Digest realm="test"`, ErrDigestChallengeNotFound},
+			{"unsupported algorithm", `Digest nonce="abc", algorithm=SHA-512-256`, ErrUnsupportedDigestAlgorithm},
+			{"unsupported qop", `Digest nonce="abc", qop="auth-int"`, ErrUnsupportedDigestQop},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				header := http.Header{}
+				header.Set(headerWWWAuthenticate, tc.header)
+
+				_, err := parseChallenges(header)
+				if !errors.Is(err, tc.expected) {
+					t.Errorf("expected error %v, got: %v", tc.expected, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digestauth
+
+import (
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc/authc/authscheme"
+)
+
+// DigestAuthConfig contains configurations for the [HTTP Digest] authentication.
+//
+// [HTTP Digest]: https://www.rfc-editor.org/rfc/rfc7616
+type DigestAuthConfig struct {
+	// Type of the digest authenticator.
+	Type authscheme.HTTPClientAuthType `json:"type" jsonschema:"type=string,enum=digest" yaml:"type"`
+	// Username to authenticate.
+	Username goenvconf.EnvString `json:"username" yaml:"username"`
+	// Password to authenticate.
+	Password goenvconf.EnvString `json:"password" yaml:"password"`
+}
+
+var _ authscheme.HTTPClientAuthenticatorConfig = (*DigestAuthConfig)(nil)
+
+// NewDigestAuthConfig creates a new DigestAuthConfig instance.
+func NewDigestAuthConfig(username, password goenvconf.EnvString) *DigestAuthConfig {
+	return &DigestAuthConfig{
+		Type:     authscheme.DigestAuthScheme,
+		Username: username,
+		Password: password,
+	}
+}
+
+// IsZero if the current instance is empty.
+func (dac DigestAuthConfig) IsZero() bool {
+	return dac.Type == 0 &&
+		dac.Username.IsZero() &&
+		dac.Password.IsZero()
+}
+
+// Equal checks if the target value is equal.
+func (dac DigestAuthConfig) Equal(target DigestAuthConfig) bool {
+	return dac.Type == target.Type &&
+		dac.Username.Equal(target.Username) &&
+		dac.Password.Equal(target.Password)
+}
+
+// Validate if the current instance is valid.
+func (dac DigestAuthConfig) Validate(strict bool) error {
+	authType := dac.GetType()
+
+	if dac.Type != authType {
+		return authscheme.NewUnmatchedSecuritySchemeError(authType, dac.Type)
+	}
+
+	if !strict {
+		return nil
+	}
+
+	if dac.Username.IsZero() {
+		return authscheme.NewRequiredSecurityFieldError(authType, "username")
+	}
+
+	return nil
+}
+
+// GetType get the type of security scheme.
+func (DigestAuthConfig) GetType() authscheme.HTTPClientAuthType {
+	return authscheme.DigestAuthScheme
+}
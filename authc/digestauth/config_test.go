@@ -0,0 +1,82 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digestauth
+
+import (
+	"testing"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc/authc/authscheme"
+)
+
+func TestDigestAuthConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Config    DigestAuthConfig
+		Strict    bool
+		ExpectErr bool
+	}{
+		{
+			Name: "valid_strict",
+			Config: *NewDigestAuthConfig(
+				goenvconf.NewEnvStringValue("user"),
+				goenvconf.NewEnvStringValue("pass"),
+			),
+			Strict: true,
+		},
+		{
+			Name: "unmatched_type",
+			Config: DigestAuthConfig{
+				Type: authscheme.BasicAuthScheme,
+			},
+			ExpectErr: true,
+		},
+		{
+			Name: "missing_username_non_strict",
+			Config: DigestAuthConfig{
+				Type: authscheme.DigestAuthScheme,
+			},
+		},
+		{
+			Name: "missing_username_strict",
+			Config: DigestAuthConfig{
+				Type: authscheme.DigestAuthScheme,
+			},
+			Strict:    true,
+			ExpectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Config.Validate(tc.Strict)
+			if tc.ExpectErr && err == nil {
+				t.Error("expected error, got nil")
+			} else if !tc.ExpectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDigestAuthConfig_IsZero(t *testing.T) {
+	if !(DigestAuthConfig{}).IsZero() {
+		t.Error("expected IsZero to return true")
+	}
+
+	if (DigestAuthConfig{Type: authscheme.DigestAuthScheme}).IsZero() {
+		t.Error("expected IsZero to return false")
+	}
+}
@@ -16,24 +16,40 @@
 package oauth2scheme
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/hasura/goenvconf"
 	"github.com/relychan/gohttpc/authc/authscheme"
 	"github.com/relychan/goutils"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
-// OAuth2Credential represent the client of the OAuth2 client credentials.
+// ErrAuthorizationCodeRequired occurs when the authorization code flow has no code to exchange for tokens.
+var ErrAuthorizationCodeRequired = errors.New(
+	"code is required to exchange tokens for the OAuth2 authorization_code flow",
+)
+
+// OAuth2Credential represent the client of the OAuth2 flows.
 type OAuth2Credential struct {
+	flowType     OAuthFlowType
 	oauth2Config *clientcredentials.Config
+	flowConfig   *oauth2.Config
+	tokens       tokenProvider
 	location     *authscheme.TokenLocation
 }
 
+// tokenProvider abstracts the token source of an OAuth2 flow.
+type tokenProvider interface {
+	Token(ctx context.Context) (*oauth2.Token, error)
+}
+
 var _ authscheme.HTTPClientAuthenticator = (*OAuth2Credential)(nil)
 
 // NewOAuth2Credential creates an OAuth2 client from the security scheme.
@@ -61,14 +77,36 @@ func NewOAuth2Credential(
 		options = authscheme.NewHTTPClientAuthenticatorOptions()
 	}
 
-	oauth2Config, err := newClientCredentialsConfig(config, options)
-	if err != nil {
-		return nil, err
+	client := &OAuth2Credential{
+		flowType: config.Flows.GetFlowType(),
+		location: location,
 	}
 
-	client := &OAuth2Credential{
-		location:     location,
-		oauth2Config: oauth2Config,
+	switch client.flowType {
+	case AuthorizationCodeFlow:
+		flowConfig, tokens, err := newAuthorizationCodeConfig(config.Flows.AuthorizationCode, options)
+		if err != nil {
+			return nil, err
+		}
+
+		client.flowConfig = flowConfig
+		client.tokens = tokens
+	case RefreshTokenFlow:
+		flowConfig, tokens, err := newRefreshTokenConfig(config.Flows.RefreshToken, options)
+		if err != nil {
+			return nil, err
+		}
+
+		client.flowConfig = flowConfig
+		client.tokens = tokens
+	default:
+		oauth2Config, tokens, err := newClientCredentialsConfig(config, options)
+		if err != nil {
+			return nil, err
+		}
+
+		client.oauth2Config = oauth2Config
+		client.tokens = tokens
 	}
 
 	return client, nil
@@ -79,13 +117,12 @@ func (oc *OAuth2Credential) Authenticate(
 	req *http.Request,
 	options ...authscheme.AuthenticateOption,
 ) error {
-	oauth2Config := oc.oauth2Config
-	if oauth2Config == nil {
+	if oc.tokens == nil {
 		return authscheme.ErrAuthCredentialEmpty
 	}
 
-	// get the token from client credentials
-	token, err := oauth2Config.Token(req.Context())
+	// get the token from the OAuth2 flow
+	token, err := oc.tokens.Token(req.Context())
 	if err != nil {
 		return err
 	}
@@ -101,10 +138,22 @@ func (oc *OAuth2Credential) Authenticate(
 	return err
 }
 
+// AuthCodeURL returns the URL to the consent page of the authorization code flow.
+// Returns an empty string if the credential does not use the authorization code flow.
+func (oc *OAuth2Credential) AuthCodeURL(state string, options ...oauth2.AuthCodeOption) string {
+	if oc.flowType != AuthorizationCodeFlow || oc.flowConfig == nil {
+		return ""
+	}
+
+	return oc.flowConfig.AuthCodeURL(state, options...)
+}
+
 // Equal checks if the target value is equal.
 func (oc OAuth2Credential) Equal(target OAuth2Credential) bool {
-	return goutils.EqualPtr(oc.location, target.location) &&
-		EqualClientCredentialsConfig(oc.oauth2Config, target.oauth2Config)
+	return oc.flowType == target.flowType &&
+		goutils.EqualPtr(oc.location, target.location) &&
+		EqualClientCredentialsConfig(oc.oauth2Config, target.oauth2Config) &&
+		equalOAuth2Config(oc.flowConfig, target.flowConfig)
 }
 
 // Close terminates internal processes before destroyed.
@@ -150,36 +199,31 @@ func EqualClientCredentialsConfig(a, b *clientcredentials.Config) bool {
 func newClientCredentialsConfig(
 	config *OAuth2Config,
 	options *authscheme.HTTPClientAuthenticatorOptions,
-) (*clientcredentials.Config, error) {
+) (*clientcredentials.Config, tokenProvider, error) {
 	getter := options.GetEnvFunc()
 	flow := config.Flows.ClientCredentials
 
 	rawTokenURL, err := flow.TokenURL.GetCustom(getter)
 	if err != nil {
-		return nil, fmt.Errorf("tokenUrl: %w", err)
+		return nil, nil, fmt.Errorf("tokenUrl: %w", err)
 	}
 
 	tokenURL, err := goutils.ParsePathOrHTTPURL(rawTokenURL)
 	if err != nil {
-		return nil, fmt.Errorf("tokenUrl: %w", err)
+		return nil, nil, fmt.Errorf("tokenUrl: %w", err)
 	}
 
 	clientID, err := flow.ClientID.GetCustom(getter)
 	if err != nil {
-		return nil, fmt.Errorf("clientId: %w", err)
+		return nil, nil, fmt.Errorf("clientId: %w", err)
 	}
 
-	clientSecret, err := flow.ClientSecret.GetCustom(getter)
-	if err != nil {
-		return nil, fmt.Errorf("clientSecret: %w", err)
-	}
-
-	var endpointParams url.Values
+	endpointParams := url.Values{}
 
 	for key, envValue := range flow.EndpointParams {
 		value, err := envValue.GetCustom(getter)
 		if err != nil && !errors.Is(err, goenvconf.ErrEnvironmentVariableValueRequired) {
-			return nil, fmt.Errorf("endpointParams[%s]: %w", key, err)
+			return nil, nil, fmt.Errorf("endpointParams[%s]: %w", key, err)
 		}
 
 		if value != "" {
@@ -187,11 +231,238 @@ func newClientCredentialsConfig(
 		}
 	}
 
-	return &clientcredentials.Config{
+	result := &clientcredentials.Config{
 		ClientID:       clientID,
-		ClientSecret:   clientSecret,
 		Scopes:         flow.Scopes,
 		TokenURL:       tokenURL.String(),
 		EndpointParams: endpointParams,
+	}
+
+	if flow.ClientAssertion != nil {
+		// the client is authenticated by the signed assertion, so the client ID must be sent in the form body.
+		result.AuthStyle = oauth2.AuthStyleInParams
+
+		signer, err := newClientAssertionSigner(flow.ClientAssertion, clientID, result.TokenURL, getter)
+		if err != nil {
+			return nil, nil, fmt.Errorf("clientAssertion: %w", err)
+		}
+
+		return result, &clientAssertionTokens{
+			config: result,
+			signer: signer,
+		}, nil
+	}
+
+	if flow.ClientSecret == nil {
+		return nil, nil, ErrClientSecretRequired
+	}
+
+	result.ClientSecret, err = flow.ClientSecret.GetCustom(getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clientSecret: %w", err)
+	}
+
+	return result, result, nil
+}
+
+func equalOAuth2Config(a, b *oauth2.Config) bool {
+	if a == nil && b == nil {
+		return true
+	}
+
+	if a == nil || b == nil {
+		return false
+	}
+
+	return a.ClientID == b.ClientID &&
+		a.ClientSecret == b.ClientSecret &&
+		a.RedirectURL == b.RedirectURL &&
+		a.Endpoint == b.Endpoint &&
+		goutils.EqualSliceSorted(a.Scopes, b.Scopes)
+}
+
+func newAuthorizationCodeConfig(
+	flow *AuthorizationCodeOAuthFlow,
+	options *authscheme.HTTPClientAuthenticatorOptions,
+) (*oauth2.Config, tokenProvider, error) {
+	getter := options.GetEnvFunc()
+
+	authURL, err := getEnvURL(flow.AuthorizationURL, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("authorizationUrl: %w", err)
+	}
+
+	tokenURL, err := getEnvURL(flow.TokenURL, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tokenUrl: %w", err)
+	}
+
+	clientID, err := flow.ClientID.GetCustom(getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clientId: %w", err)
+	}
+
+	clientSecret, err := getOptionalEnvString(flow.ClientSecret, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clientSecret: %w", err)
+	}
+
+	redirectURL, err := getOptionalEnvString(flow.RedirectURL, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("redirectUrl: %w", err)
+	}
+
+	code, err := getOptionalEnvString(flow.Code, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("code: %w", err)
+	}
+
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       flow.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  authURL,
+			TokenURL: tokenURL,
+		},
+	}
+
+	refreshConfig := config
+
+	if flow.RefreshURL != nil && !flow.RefreshURL.IsZero() {
+		refreshURL, err := getEnvURL(flow.RefreshURL, getter)
+		if err != nil {
+			return nil, nil, fmt.Errorf("refreshUrl: %w", err)
+		}
+
+		newConfig := *config
+		newConfig.Endpoint.TokenURL = refreshURL
+		refreshConfig = &newConfig
+	}
+
+	return config, &authorizationCodeTokens{
+		config:        config,
+		refreshConfig: refreshConfig,
+		code:          code,
 	}, nil
 }
+
+func newRefreshTokenConfig(
+	flow *RefreshTokenOAuthFlow,
+	options *authscheme.HTTPClientAuthenticatorOptions,
+) (*oauth2.Config, tokenProvider, error) {
+	getter := options.GetEnvFunc()
+
+	refreshURL, err := getEnvURL(flow.RefreshURL, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refreshUrl: %w", err)
+	}
+
+	refreshToken, err := flow.RefreshToken.GetCustom(getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refreshToken: %w", err)
+	}
+
+	if refreshToken == "" {
+		return nil, nil, ErrRefreshTokenRequired
+	}
+
+	clientID, err := getOptionalEnvString(flow.ClientID, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clientId: %w", err)
+	}
+
+	clientSecret, err := getOptionalEnvString(flow.ClientSecret, getter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("clientSecret: %w", err)
+	}
+
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       flow.Scopes,
+		Endpoint: oauth2.Endpoint{
+			TokenURL: refreshURL,
+		},
+	}
+
+	// The token source renews the access token when it expires and keeps the rotated refresh token.
+	source := config.TokenSource(context.Background(), &oauth2.Token{
+		RefreshToken: refreshToken,
+	})
+
+	return config, &staticTokenSource{source: source}, nil
+}
+
+func getEnvURL(value *goenvconf.EnvString, getter goenvconf.GetEnvFunc) (string, error) {
+	rawURL, err := value.GetCustom(getter)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := goutils.ParsePathOrHTTPURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+func getOptionalEnvString(value *goenvconf.EnvString, getter goenvconf.GetEnvFunc) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	result, err := value.GetCustom(getter)
+	if err != nil && !errors.Is(err, goenvconf.ErrEnvironmentVariableValueRequired) {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// authorizationCodeTokens exchanges the authorization code for tokens on the first call
+// and renews the access token with the refresh token afterwards.
+type authorizationCodeTokens struct {
+	config        *oauth2.Config
+	refreshConfig *oauth2.Config
+	code          string
+
+	lock   sync.Mutex
+	source oauth2.TokenSource
+}
+
+// Token returns a valid token.
+func (act *authorizationCodeTokens) Token(ctx context.Context) (*oauth2.Token, error) {
+	act.lock.Lock()
+	defer act.lock.Unlock()
+
+	if act.source == nil {
+		if act.code == "" {
+			return nil, ErrAuthorizationCodeRequired
+		}
+
+		token, err := act.config.Exchange(ctx, act.code)
+		if err != nil {
+			return nil, err
+		}
+
+		act.source = act.refreshConfig.TokenSource(context.WithoutCancel(ctx), token)
+
+		// The token is freshly issued so it is returned directly.
+		return token, nil
+	}
+
+	return act.source.Token()
+}
+
+// staticTokenSource adapts the [oauth2.TokenSource] to the [tokenProvider] interface.
+type staticTokenSource struct {
+	source oauth2.TokenSource
+}
+
+// Token returns a valid token.
+func (sts *staticTokenSource) Token(_ context.Context) (*oauth2.Token, error) {
+	return sts.source.Token()
+}
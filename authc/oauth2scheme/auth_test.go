@@ -15,6 +15,13 @@
 package oauth2scheme
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/relychan/gohttpc/authc/authscheme"
@@ -55,3 +62,168 @@ func TestNewOAuth2Credential(t *testing.T) {
 		}
 	})
 }
+
+// newTokenServer creates a token endpoint which issues short-lived access tokens and rotates refresh tokens.
+func newTokenServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var (
+		lock   sync.Mutex
+		grants []string
+		count  int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		grantType := r.Form.Get("grant_type")
+
+		switch grantType {
+		case "authorization_code":
+			if r.Form.Get("code") != "auth-code" {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != fmt.Sprintf("refresh-%d", count) {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		grants = append(grants, grantType)
+		count++
+
+		w.Header().Set("Content-Type", "application/json")
+		// The token expires immediately to force a refresh on the next request.
+		_, _ = fmt.Fprintf(
+			w,
+			`{"access_token":"access-%d","token_type":"Bearer","refresh_token":"refresh-%d","expires_in":1}`,
+			count,
+			count,
+		)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &grants
+}
+
+func authenticateToken(t *testing.T, cred *OAuth2Credential) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	err := cred.Authenticate(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return req.Header.Get("Authorization")
+}
+
+func TestOAuth2Credential_AuthorizationCode(t *testing.T) {
+	server, grants := newTokenServer(t)
+
+	config := NewOAuth2Config(OAuth2Flows{
+		AuthorizationCode: &AuthorizationCodeOAuthFlow{
+			AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+			TokenURL:         ptrEnvString(server.URL),
+			RedirectURL:      ptrEnvString("https://example.com/callback"),
+			ClientID:         ptrEnvString("client-id"),
+			Code:             ptrEnvString("auth-code"),
+			Scopes:           []string{"read"},
+		},
+	})
+
+	cred, err := NewOAuth2Credential(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authCodeURL := cred.AuthCodeURL("state")
+	if !strings.HasPrefix(authCodeURL, "https://example.com/authorize?") ||
+		!strings.Contains(authCodeURL, "state=state") {
+		t.Errorf("unexpected auth code URL: %s", authCodeURL)
+	}
+
+	if token := authenticateToken(t, cred); token != "Bearer access-1" {
+		t.Errorf("expected Bearer access-1, got: %s", token)
+	}
+
+	if token := authenticateToken(t, cred); token != "Bearer access-2" {
+		t.Errorf("expected Bearer access-2, got: %s", token)
+	}
+
+	expectedGrants := []string{"authorization_code", "refresh_token"}
+	if !slices.Equal(*grants, expectedGrants) {
+		t.Errorf("expected grants %v, got: %v", expectedGrants, *grants)
+	}
+
+	t.Run("returns error without code", func(t *testing.T) {
+		config := NewOAuth2Config(OAuth2Flows{
+			AuthorizationCode: &AuthorizationCodeOAuthFlow{
+				AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+				TokenURL:         ptrEnvString(server.URL),
+				ClientID:         ptrEnvString("client-id"),
+			},
+		})
+
+		cred, err := NewOAuth2Credential(config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cred.Authenticate(httptest.NewRequest(http.MethodGet, "http://example.com", nil))
+		if !errors.Is(err, ErrAuthorizationCodeRequired) {
+			t.Errorf("expected ErrAuthorizationCodeRequired, got: %v", err)
+		}
+	})
+}
+
+func TestOAuth2Credential_RefreshToken(t *testing.T) {
+	server, grants := newTokenServer(t)
+
+	config := NewOAuth2Config(OAuth2Flows{
+		RefreshToken: &RefreshTokenOAuthFlow{
+			RefreshURL:   ptrEnvString(server.URL),
+			RefreshToken: ptrEnvString("refresh-0"),
+			ClientID:     ptrEnvString("client-id"),
+		},
+	})
+
+	cred, err := NewOAuth2Credential(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cred.AuthCodeURL("state") != "" {
+		t.Error("expected empty auth code URL")
+	}
+
+	// The access token is renewed with the rotated refresh token.
+	for i := 1; i <= 3; i++ {
+		expected := fmt.Sprintf("Bearer access-%d", i)
+
+		if token := authenticateToken(t, cred); token != expected {
+			t.Errorf("expected %s, got: %s", expected, token)
+		}
+	}
+
+	if len(*grants) != 3 {
+		t.Errorf("expected 3 refresh grants, got: %v", *grants)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2scheme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/goutils"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ClientAssertionTypeJWTBearer is the client assertion type of the JWT bearer client authentication.
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// defaultClientAssertionLifetime is the default lifetime of the client assertion JWT.
+const defaultClientAssertionLifetime = 5 * time.Minute
+
+// ClientAssertionAlgorithm represents the signing algorithm of the client assertion JWT.
+type ClientAssertionAlgorithm string
+
+const (
+	// ClientAssertionRS256 represents the RSASSA-PKCS1-v1_5 using SHA-256 algorithm.
+	ClientAssertionRS256 ClientAssertionAlgorithm = "RS256"
+	// ClientAssertionES256 represents the ECDSA using P-256 and SHA-256 algorithm.
+	ClientAssertionES256 ClientAssertionAlgorithm = "ES256"
+)
+
+var enumValueClientAssertionAlgorithms = []ClientAssertionAlgorithm{
+	ClientAssertionRS256,
+	ClientAssertionES256,
+}
+
+var (
+	// ErrUnsupportedClientAssertionAlgorithm occurs when the signing algorithm of the client assertion is not supported.
+	ErrUnsupportedClientAssertionAlgorithm = fmt.Errorf(
+		"unsupported client assertion algorithm. Expected %+v",
+		enumValueClientAssertionAlgorithms,
+	)
+	// ErrClientAssertionKeyRequired occurs when the signing key of the client assertion is empty.
+	ErrClientAssertionKeyRequired = errors.New("privateKey is required for the JWT client assertion")
+	// ErrInvalidClientAssertionKey occurs when the signing key of the client assertion can't be parsed.
+	ErrInvalidClientAssertionKey = errors.New("invalid private key of the JWT client assertion")
+)
+
+// Validate checks if the current value is valid.
+func (j ClientAssertionAlgorithm) Validate() error {
+	if !slices.Contains(enumValueClientAssertionAlgorithms, j) {
+		return fmt.Errorf("%w, got <%s>", ErrUnsupportedClientAssertionAlgorithm, j)
+	}
+
+	return nil
+}
+
+// JWTClientAssertion contains configurations to authenticate the client with a signed JWT assertion
+// (private_key_jwt) instead of the client secret.
+type JWTClientAssertion struct {
+	// The PEM-encoded private key to sign the assertion.
+	PrivateKey goenvconf.EnvString `json:"privateKey" yaml:"privateKey"`
+	// The signing algorithm of the assertion.
+	Algorithm ClientAssertionAlgorithm `json:"algorithm" jsonschema:"enum=RS256,enum=ES256" yaml:"algorithm"`
+	// The optional key ID which is set to the kid header of the assertion.
+	KeyID string `json:"keyId,omitempty" yaml:"keyId,omitempty"`
+	// The audience of the assertion. Defaults to the token URL.
+	Audience *goenvconf.EnvString `json:"audience,omitempty" yaml:"audience,omitempty"`
+}
+
+// IsZero if the current instance is empty.
+func (ja JWTClientAssertion) IsZero() bool {
+	return ja.PrivateKey.IsZero() &&
+		ja.Algorithm == "" &&
+		ja.KeyID == "" &&
+		(ja.Audience == nil || ja.Audience.IsZero())
+}
+
+// Equal checks if this instance equals the target value.
+func (ja JWTClientAssertion) Equal(target JWTClientAssertion) bool {
+	return ja.PrivateKey.Equal(target.PrivateKey) &&
+		ja.Algorithm == target.Algorithm &&
+		ja.KeyID == target.KeyID &&
+		goutils.EqualPtr(ja.Audience, target.Audience)
+}
+
+// Validate if the current instance is valid.
+func (ja JWTClientAssertion) Validate() error {
+	if ja.PrivateKey.IsZero() {
+		return ErrClientAssertionKeyRequired
+	}
+
+	return ja.Algorithm.Validate()
+}
+
+// clientAssertionSigner signs client assertion JWTs.
+type clientAssertionSigner struct {
+	algorithm ClientAssertionAlgorithm
+	key       crypto.Signer
+	keyID     string
+	clientID  string
+	audience  string
+	lifetime  time.Duration
+	now       func() time.Time
+}
+
+func newClientAssertionSigner(
+	assertion *JWTClientAssertion,
+	clientID string,
+	tokenURL string,
+	getter goenvconf.GetEnvFunc,
+) (*clientAssertionSigner, error) {
+	err := assertion.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := assertion.PrivateKey.GetCustom(getter)
+	if err != nil {
+		return nil, fmt.Errorf("privateKey: %w", err)
+	}
+
+	key, err := parseClientAssertionKey(assertion.Algorithm, rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	audience := tokenURL
+
+	if assertion.Audience != nil && !assertion.Audience.IsZero() {
+		audience, err = assertion.Audience.GetCustom(getter)
+		if err != nil {
+			return nil, fmt.Errorf("audience: %w", err)
+		}
+	}
+
+	return &clientAssertionSigner{
+		algorithm: assertion.Algorithm,
+		key:       key,
+		keyID:     assertion.KeyID,
+		clientID:  clientID,
+		audience:  audience,
+		lifetime:  defaultClientAssertionLifetime,
+		now:       time.Now,
+	}, nil
+}
+
+// Sign creates a new signed assertion JWT.
+func (cas *clientAssertionSigner) Sign() (string, error) {
+	header := map[string]string{
+		"alg": string(cas.algorithm),
+		"typ": "JWT",
+	}
+
+	if cas.keyID != "" {
+		header["kid"] = cas.keyID
+	}
+
+	now := cas.now()
+	claims := map[string]any{
+		"iss": cas.clientID,
+		"sub": cas.clientID,
+		"aud": cas.audience,
+		"iat": now.Unix(),
+		"exp": now.Add(cas.lifetime).Unix(),
+		"jti": uuid.NewString(),
+	}
+
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerBytes) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsBytes)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := cas.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the client assertion: %w", err)
+	}
+
+	if cas.algorithm == ClientAssertionES256 {
+		signature, err = convertECDSASignature(signature)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// convertECDSASignature converts the ASN.1 signature to the fixed-size R || S form which is required by JWS.
+func convertECDSASignature(signature []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+
+	_, err := asn1.Unmarshal(signature, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the ECDSA signature: %w", err)
+	}
+
+	result := make([]byte, 64)
+	sig.R.FillBytes(result[:32])
+	sig.S.FillBytes(result[32:])
+
+	return result, nil
+}
+
+func parseClientAssertionKey(algorithm ClientAssertionAlgorithm, rawKey string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(rawKey))
+	if block == nil {
+		return nil, fmt.Errorf("%w: failed to decode PEM block", ErrInvalidClientAssertionKey)
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClientAssertionKey, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == ClientAssertionRS256 {
+			return k, nil
+		}
+	case *ecdsa.PrivateKey:
+		if algorithm == ClientAssertionES256 && k.Curve.Params().BitSize == 256 {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"%w: the key type %T does not match the algorithm %s",
+		ErrInvalidClientAssertionKey,
+		key,
+		algorithm,
+	)
+}
+
+// clientAssertionTokens requests tokens of the client credentials flow with a new signed assertion per request.
+type clientAssertionTokens struct {
+	config *clientcredentials.Config
+	signer *clientAssertionSigner
+}
+
+// Token returns a new token.
+func (cat *clientAssertionTokens) Token(ctx context.Context) (*oauth2.Token, error) {
+	assertion, err := cat.signer.Sign()
+	if err != nil {
+		return nil, err
+	}
+
+	config := *cat.config
+	config.EndpointParams = url.Values{}
+
+	for key, values := range cat.config.EndpointParams {
+		config.EndpointParams[key] = values
+	}
+
+	config.EndpointParams.Set("client_assertion_type", ClientAssertionTypeJWTBearer)
+	config.EndpointParams.Set("client_assertion", assertion)
+
+	return config.Token(ctx)
+}
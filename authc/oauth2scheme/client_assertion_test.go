@@ -0,0 +1,277 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2scheme
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hasura/goenvconf"
+)
+
+func generateClientAssertionKey(t *testing.T, algorithm ClientAssertionAlgorithm) (string, crypto.PublicKey) {
+	t.Helper()
+
+	var (
+		key      crypto.Signer
+		keyBlock *pem.Block
+	)
+
+	switch algorithm {
+	case ClientAssertionRS256:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key = rsaKey
+		keyBlock = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}
+	default:
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(ecKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key = ecKey
+		keyBlock = &pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}
+	}
+
+	return string(pem.EncodeToMemory(keyBlock)), key.Public()
+}
+
+// verifyClientAssertion verifies the signature of the assertion and returns the decoded claims.
+func verifyClientAssertion(assertion string, publicKey crypto.PublicKey) (map[string]any, error) {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed JWT")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature)
+		if err != nil {
+			return nil, err
+		}
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return nil, errors.New("invalid ES256 signature length")
+		}
+
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return nil, errors.New("invalid ES256 signature")
+		}
+	}
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+
+	err = json.Unmarshal(claimsBytes, &claims)
+
+	return claims, err
+}
+
+func TestOAuth2Credential_ClientAssertion(t *testing.T) {
+	for _, algorithm := range []ClientAssertionAlgorithm{ClientAssertionRS256, ClientAssertionES256} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			privateKey, publicKey := generateClientAssertionKey(t, algorithm)
+
+			var (
+				lock       sync.Mutex
+				assertions []string
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				err := r.ParseForm()
+				if err != nil ||
+					r.Form.Get("grant_type") != "client_credentials" ||
+					r.Form.Get("client_id") != "client-id" ||
+					r.Form.Get("client_secret") != "" ||
+					r.Form.Get("client_assertion_type") != ClientAssertionTypeJWTBearer {
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				lock.Lock()
+				assertions = append(assertions, r.Form.Get("client_assertion"))
+				lock.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))
+			}))
+			defer server.Close()
+
+			tokenURL := server.URL + "/token"
+			config := NewOAuth2Config(OAuth2Flows{
+				ClientCredentials: ClientCredentialsOAuthFlow{
+					TokenURL: ptrEnvString(tokenURL),
+					ClientID: ptrEnvString("client-id"),
+					ClientAssertion: &JWTClientAssertion{
+						PrivateKey: goenvconf.NewEnvStringValue(privateKey),
+						Algorithm:  algorithm,
+						KeyID:      "key-1",
+					},
+				},
+			})
+
+			err := config.Validate(true)
+			if err != nil {
+				t.Fatalf("expected valid config, got: %s", err)
+			}
+
+			cred, err := NewOAuth2Credential(config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for range 2 {
+				token := authenticateToken(t, cred)
+				if token != "Bearer access-token" {
+					t.Errorf("expected Bearer access-token, got: %s", token)
+				}
+			}
+
+			if len(assertions) != 2 {
+				t.Fatalf("expected 2 assertions, got: %d", len(assertions))
+			}
+
+			if assertions[0] == assertions[1] {
+				t.Error("expected a new assertion per token request")
+			}
+
+			header, err := base64.RawURLEncoding.DecodeString(strings.Split(assertions[0], ".")[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedHeader := `{"alg":"` + string(algorithm) + `","kid":"key-1","typ":"JWT"}`
+			if string(header) != expectedHeader {
+				t.Errorf("expected header %s, got: %s", expectedHeader, header)
+			}
+
+			claims, err := verifyClientAssertion(assertions[0], publicKey)
+			if err != nil {
+				t.Fatalf("failed to verify the assertion: %s", err)
+			}
+
+			for _, key := range []string{"iss", "sub"} {
+				if claims[key] != "client-id" {
+					t.Errorf("expected %s = client-id, got: %v", key, claims[key])
+				}
+			}
+
+			if claims["aud"] != tokenURL {
+				t.Errorf("expected aud = %s, got: %v", tokenURL, claims["aud"])
+			}
+
+			exp, ok := claims["exp"].(float64)
+			if !ok {
+				t.Fatalf("expected numeric exp, got: %v", claims["exp"])
+			}
+
+			expiry := time.Unix(int64(exp), 0)
+			if expiry.Before(time.Now()) || expiry.After(time.Now().Add(defaultClientAssertionLifetime+time.Second)) {
+				t.Errorf("unexpected exp: %s", expiry)
+			}
+		})
+	}
+}
+
+func TestOAuth2Credential_ClientAssertionInvalidKey(t *testing.T) {
+	rsaKey, _ := generateClientAssertionKey(t, ClientAssertionRS256)
+
+	testCases := []struct {
+		Name      string
+		Assertion JWTClientAssertion
+		Expected  error
+	}{
+		{
+			Name: "invalid_pem",
+			Assertion: JWTClientAssertion{
+				PrivateKey: goenvconf.NewEnvStringValue("not a key"),
+				Algorithm:  ClientAssertionRS256,
+			},
+			Expected: ErrInvalidClientAssertionKey,
+		},
+		{
+			Name: "unmatched_algorithm",
+			Assertion: JWTClientAssertion{
+				PrivateKey: goenvconf.NewEnvStringValue(rsaKey),
+				Algorithm:  ClientAssertionES256,
+			},
+			Expected: ErrInvalidClientAssertionKey,
+		},
+		{
+			Name: "unsupported_algorithm",
+			Assertion: JWTClientAssertion{
+				PrivateKey: goenvconf.NewEnvStringValue(rsaKey),
+				Algorithm:  "HS256",
+			},
+			Expected: ErrUnsupportedClientAssertionAlgorithm,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			config := NewOAuth2Config(OAuth2Flows{
+				ClientCredentials: ClientCredentialsOAuthFlow{
+					TokenURL:        ptrEnvString("https://example.com/token"),
+					ClientID:        ptrEnvString("client-id"),
+					ClientAssertion: &tc.Assertion,
+				},
+			})
+
+			_, err := NewOAuth2Credential(config, nil)
+			if !errors.Is(err, tc.Expected) {
+				t.Errorf("expected error %s, got: %v", tc.Expected, err)
+			}
+		})
+	}
+}
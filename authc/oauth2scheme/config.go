@@ -33,9 +33,21 @@ var (
 	)
 	// ErrTokenURLRequired represents the token URL required error.
 	ErrTokenURLRequired = errors.New("tokenUrl: value and env are empty")
+	// ErrAuthorizationURLRequired represents the authorization URL required error.
+	ErrAuthorizationURLRequired = errors.New("authorizationUrl: value and env are empty")
+	// ErrRefreshURLRequired represents the refresh URL required error.
+	ErrRefreshURLRequired = errors.New("refreshUrl: value and env are empty")
+	// ErrRefreshTokenRequired represents the refresh token required error.
+	ErrRefreshTokenRequired = errors.New(
+		"refreshToken is required for the OAuth2 refresh_token flow",
+	)
+	// ErrAuthorizationCodeClientIDRequired represents the client ID required error of the authorization_code flow.
+	ErrAuthorizationCodeClientIDRequired = errors.New(
+		"clientId is required for the OAuth2 authorization_code flow",
+	)
 )
 
-// OAuth2Config contains configurations for OAuth 2.0 with client_credentials type.
+// OAuth2Config contains configurations for OAuth 2.0 with client_credentials, authorization_code or refresh_token type.
 type OAuth2Config struct {
 	// Type of the oauth2 authenticator.
 	Type authscheme.HTTPClientAuthType `json:"type" jsonschema:"type=string,enum=oauth2" yaml:"type"`
@@ -89,23 +101,71 @@ func (ss OAuth2Config) Validate(_ bool) error {
 		}
 	}
 
-	return ss.Flows.ClientCredentials.Validate()
+	return ss.Flows.Validate()
 }
 
 // OAuth2Flows contain configuration information for the flow types supported.
+// If many flows are configured, the client credentials flow takes precedence,
+// followed by the authorization code and refresh token flows.
 type OAuth2Flows struct {
 	// OAuth2 flow for client_credentials
-	ClientCredentials ClientCredentialsOAuthFlow `json:"clientCredentials" yaml:"clientCredentials"`
+	ClientCredentials ClientCredentialsOAuthFlow `json:"clientCredentials,omitzero" yaml:"clientCredentials,omitempty"`
+	// OAuth2 flow for authorization_code
+	AuthorizationCode *AuthorizationCodeOAuthFlow `json:"authorizationCode,omitempty" yaml:"authorizationCode,omitempty"`
+	// OAuth2 flow for refresh_token
+	RefreshToken *RefreshTokenOAuthFlow `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
 }
 
 // IsZero if the current instance is empty.
 func (ss OAuth2Flows) IsZero() bool {
-	return ss.ClientCredentials.IsZero()
+	return ss.ClientCredentials.IsZero() &&
+		(ss.AuthorizationCode == nil || ss.AuthorizationCode.IsZero()) &&
+		(ss.RefreshToken == nil || ss.RefreshToken.IsZero())
 }
 
 // Equal checks if this instance equals the target value.
 func (ss OAuth2Flows) Equal(target OAuth2Flows) bool {
-	return ss.ClientCredentials.Equal(target.ClientCredentials)
+	return ss.ClientCredentials.Equal(target.ClientCredentials) &&
+		goutils.EqualPtr(ss.AuthorizationCode, target.AuthorizationCode) &&
+		goutils.EqualPtr(ss.RefreshToken, target.RefreshToken)
+}
+
+// Validate if the current instance is valid.
+// The client credentials flow is validated if it is set or no other flow is configured.
+func (ss OAuth2Flows) Validate() error {
+	if ss.AuthorizationCode != nil {
+		err := ss.AuthorizationCode.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if ss.RefreshToken != nil {
+		err := ss.RefreshToken.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !ss.ClientCredentials.IsZero() || (ss.AuthorizationCode == nil && ss.RefreshToken == nil) {
+		return ss.ClientCredentials.Validate()
+	}
+
+	return nil
+}
+
+// GetFlowType returns the type of the flow which is used to get tokens.
+func (ss OAuth2Flows) GetFlowType() OAuthFlowType {
+	switch {
+	case !ss.ClientCredentials.IsZero():
+		return ClientCredentialsFlow
+	case ss.AuthorizationCode != nil:
+		return AuthorizationCodeFlow
+	case ss.RefreshToken != nil:
+		return RefreshTokenFlow
+	default:
+		return ClientCredentialsFlow
+	}
 }
 
 // ClientCredentialsOAuthFlow contains flow configurations for OAuth 2.0 client credential flow.
@@ -122,6 +182,8 @@ type ClientCredentialsOAuthFlow struct {
 	ClientSecret *goenvconf.EnvString `json:"clientSecret,omitempty"   yaml:"clientSecret,omitempty"`
 	// Optional query parameters for the endpoint.
 	EndpointParams map[string]goenvconf.EnvString `json:"endpointParams,omitempty" yaml:"endpointParams,omitempty"`
+	// Optional JWT client assertion which authenticates the client instead of the client secret.
+	ClientAssertion *JWTClientAssertion `json:"clientAssertion,omitempty" yaml:"clientAssertion,omitempty"`
 }
 
 // IsZero if the current instance is empty.
@@ -130,6 +192,7 @@ func (ss ClientCredentialsOAuthFlow) IsZero() bool {
 		(ss.RefreshURL == nil || ss.RefreshURL.IsZero()) &&
 		(ss.ClientID == nil || ss.ClientID.IsZero()) &&
 		(ss.ClientSecret == nil || ss.ClientSecret.IsZero()) &&
+		(ss.ClientAssertion == nil || ss.ClientAssertion.IsZero()) &&
 		len(ss.Scopes) == 0 && len(ss.EndpointParams) == 0
 }
 
@@ -140,7 +203,8 @@ func (ss ClientCredentialsOAuthFlow) Equal(target ClientCredentialsOAuthFlow) bo
 		goutils.EqualSliceSorted(ss.Scopes, target.Scopes) &&
 		goutils.EqualPtr(ss.ClientID, target.ClientID) &&
 		goutils.EqualPtr(ss.ClientSecret, target.ClientSecret) &&
-		goutils.EqualMap(ss.EndpointParams, target.EndpointParams, true)
+		goutils.EqualMap(ss.EndpointParams, target.EndpointParams, true) &&
+		goutils.EqualPtr(ss.ClientAssertion, target.ClientAssertion)
 }
 
 // Validate if the current instance is valid.
@@ -153,9 +217,122 @@ func (ss ClientCredentialsOAuthFlow) Validate() error {
 		return ErrClientIDRequired
 	}
 
+	if ss.ClientAssertion != nil {
+		return ss.ClientAssertion.Validate()
+	}
+
 	if ss.ClientSecret == nil || ss.ClientSecret.IsZero() {
 		return ErrClientSecretRequired
 	}
 
 	return nil
 }
+
+// AuthorizationCodeOAuthFlow contains flow configurations for OAuth 2.0 authorization code flow.
+// The authorization code, which is received on the redirect URL, is exchanged for tokens on the first request.
+// The access token is renewed with the refresh token afterwards.
+type AuthorizationCodeOAuthFlow struct {
+	// The authorization URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	AuthorizationURL *goenvconf.EnvString `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	// The token URL to be used for this flow. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	TokenURL *goenvconf.EnvString `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	// The URL to be used for obtaining refresh tokens. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	RefreshURL *goenvconf.EnvString `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
+	// The URL to redirect users after the authorization.
+	RedirectURL *goenvconf.EnvString `json:"redirectUrl,omitempty" yaml:"redirectUrl,omitempty"`
+	// The available scopes for the OAuth2 security scheme.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// Client ID of the OAuth2 client.
+	ClientID *goenvconf.EnvString `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// Client secret of the OAuth2 client.
+	ClientSecret *goenvconf.EnvString `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	// The authorization code to be exchanged for tokens.
+	Code *goenvconf.EnvString `json:"code,omitempty" yaml:"code,omitempty"`
+}
+
+// IsZero if the current instance is empty.
+func (ss AuthorizationCodeOAuthFlow) IsZero() bool {
+	return (ss.AuthorizationURL == nil || ss.AuthorizationURL.IsZero()) &&
+		(ss.TokenURL == nil || ss.TokenURL.IsZero()) &&
+		(ss.RefreshURL == nil || ss.RefreshURL.IsZero()) &&
+		(ss.RedirectURL == nil || ss.RedirectURL.IsZero()) &&
+		(ss.ClientID == nil || ss.ClientID.IsZero()) &&
+		(ss.ClientSecret == nil || ss.ClientSecret.IsZero()) &&
+		(ss.Code == nil || ss.Code.IsZero()) &&
+		len(ss.Scopes) == 0
+}
+
+// Equal checks if this instance equals the target value.
+func (ss AuthorizationCodeOAuthFlow) Equal(target AuthorizationCodeOAuthFlow) bool {
+	return goutils.EqualPtr(ss.AuthorizationURL, target.AuthorizationURL) &&
+		goutils.EqualPtr(ss.TokenURL, target.TokenURL) &&
+		goutils.EqualPtr(ss.RefreshURL, target.RefreshURL) &&
+		goutils.EqualPtr(ss.RedirectURL, target.RedirectURL) &&
+		goutils.EqualSliceSorted(ss.Scopes, target.Scopes) &&
+		goutils.EqualPtr(ss.ClientID, target.ClientID) &&
+		goutils.EqualPtr(ss.ClientSecret, target.ClientSecret) &&
+		goutils.EqualPtr(ss.Code, target.Code)
+}
+
+// Validate if the current instance is valid.
+func (ss AuthorizationCodeOAuthFlow) Validate() error {
+	if ss.AuthorizationURL == nil || ss.AuthorizationURL.IsZero() {
+		return ErrAuthorizationURLRequired
+	}
+
+	if ss.TokenURL == nil || ss.TokenURL.IsZero() {
+		return ErrTokenURLRequired
+	}
+
+	if ss.ClientID == nil || ss.ClientID.IsZero() {
+		return ErrAuthorizationCodeClientIDRequired
+	}
+
+	return nil
+}
+
+// RefreshTokenOAuthFlow contains flow configurations for OAuth 2.0 refresh token flow.
+// The access token is obtained and renewed with the provided refresh token.
+type RefreshTokenOAuthFlow struct {
+	// The URL to be used for obtaining refresh tokens. This MUST be in the form of a URL. The OAuth2 standard requires the use of TLS.
+	RefreshURL *goenvconf.EnvString `json:"refreshUrl,omitempty" yaml:"refreshUrl,omitempty"`
+	// The refresh token to obtain access tokens.
+	RefreshToken *goenvconf.EnvString `json:"refreshToken,omitempty" yaml:"refreshToken,omitempty"`
+	// The available scopes for the OAuth2 security scheme.
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	// Optional client ID of the OAuth2 client.
+	ClientID *goenvconf.EnvString `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// Optional client secret of the OAuth2 client.
+	ClientSecret *goenvconf.EnvString `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+}
+
+// IsZero if the current instance is empty.
+func (ss RefreshTokenOAuthFlow) IsZero() bool {
+	return (ss.RefreshURL == nil || ss.RefreshURL.IsZero()) &&
+		(ss.RefreshToken == nil || ss.RefreshToken.IsZero()) &&
+		(ss.ClientID == nil || ss.ClientID.IsZero()) &&
+		(ss.ClientSecret == nil || ss.ClientSecret.IsZero()) &&
+		len(ss.Scopes) == 0
+}
+
+// Equal checks if this instance equals the target value.
+func (ss RefreshTokenOAuthFlow) Equal(target RefreshTokenOAuthFlow) bool {
+	return goutils.EqualPtr(ss.RefreshURL, target.RefreshURL) &&
+		goutils.EqualPtr(ss.RefreshToken, target.RefreshToken) &&
+		goutils.EqualSliceSorted(ss.Scopes, target.Scopes) &&
+		goutils.EqualPtr(ss.ClientID, target.ClientID) &&
+		goutils.EqualPtr(ss.ClientSecret, target.ClientSecret)
+}
+
+// Validate if the current instance is valid.
+func (ss RefreshTokenOAuthFlow) Validate() error {
+	if ss.RefreshURL == nil || ss.RefreshURL.IsZero() {
+		return ErrRefreshURLRequired
+	}
+
+	if ss.RefreshToken == nil || ss.RefreshToken.IsZero() {
+		return ErrRefreshTokenRequired
+	}
+
+	return nil
+}
@@ -15,11 +15,13 @@
 package oauth2scheme
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/hasura/goenvconf"
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"go.yaml.in/yaml/v4"
 )
 
 // Helper function to create a pointer to EnvString
@@ -255,4 +257,239 @@ func TestClientCredentialsOAuthFlow_Validate(t *testing.T) {
 			t.Errorf("expected ErrClientSecretRequired, got %v", err)
 		}
 	})
+
+	t.Run("validates client assertion instead of client secret", func(t *testing.T) {
+		flow := ClientCredentialsOAuthFlow{
+			TokenURL: ptrEnvString("https://example.com/token"),
+			ClientID: ptrEnvString("client-id"),
+			ClientAssertion: &JWTClientAssertion{
+				PrivateKey: goenvconf.NewEnvStringVariable("CLIENT_PRIVATE_KEY"),
+				Algorithm:  ClientAssertionES256,
+			},
+		}
+
+		err := flow.Validate()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		flow.ClientAssertion.Algorithm = "HS256"
+
+		err = flow.Validate()
+		if !errors.Is(err, ErrUnsupportedClientAssertionAlgorithm) {
+			t.Errorf("expected ErrUnsupportedClientAssertionAlgorithm, got %v", err)
+		}
+
+		flow.ClientAssertion = &JWTClientAssertion{Algorithm: ClientAssertionRS256}
+
+		err = flow.Validate()
+		if !errors.Is(err, ErrClientAssertionKeyRequired) {
+			t.Errorf("expected ErrClientAssertionKeyRequired, got %v", err)
+		}
+	})
+}
+
+func TestAuthorizationCodeOAuthFlow_Validate(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Flow     AuthorizationCodeOAuthFlow
+		Expected error
+	}{
+		{
+			Name: "valid",
+			Flow: AuthorizationCodeOAuthFlow{
+				AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+				TokenURL:         ptrEnvString("https://example.com/token"),
+				ClientID:         ptrEnvString("client-id"),
+			},
+		},
+		{
+			Name: "missing_authorization_url",
+			Flow: AuthorizationCodeOAuthFlow{
+				TokenURL: ptrEnvString("https://example.com/token"),
+				ClientID: ptrEnvString("client-id"),
+			},
+			Expected: ErrAuthorizationURLRequired,
+		},
+		{
+			Name: "missing_token_url",
+			Flow: AuthorizationCodeOAuthFlow{
+				AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+				ClientID:         ptrEnvString("client-id"),
+			},
+			Expected: ErrTokenURLRequired,
+		},
+		{
+			Name: "missing_client_id",
+			Flow: AuthorizationCodeOAuthFlow{
+				AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+				TokenURL:         ptrEnvString("https://example.com/token"),
+			},
+			Expected: ErrAuthorizationCodeClientIDRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Flow.Validate()
+			if !errors.Is(err, tc.Expected) {
+				t.Errorf("expected error %v, got: %v", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenOAuthFlow_Validate(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Flow     RefreshTokenOAuthFlow
+		Expected error
+	}{
+		{
+			Name: "valid",
+			Flow: RefreshTokenOAuthFlow{
+				RefreshURL:   ptrEnvString("https://example.com/token"),
+				RefreshToken: ptrEnvString("refresh-token"),
+			},
+		},
+		{
+			Name: "missing_refresh_url",
+			Flow: RefreshTokenOAuthFlow{
+				RefreshToken: ptrEnvString("refresh-token"),
+			},
+			Expected: ErrRefreshURLRequired,
+		},
+		{
+			Name: "missing_refresh_token",
+			Flow: RefreshTokenOAuthFlow{
+				RefreshURL: ptrEnvString("https://example.com/token"),
+			},
+			Expected: ErrRefreshTokenRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Flow.Validate()
+			if !errors.Is(err, tc.Expected) {
+				t.Errorf("expected error %v, got: %v", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func TestOAuth2Flows(t *testing.T) {
+	refreshFlow := &RefreshTokenOAuthFlow{
+		RefreshURL:   ptrEnvString("https://example.com/token"),
+		RefreshToken: ptrEnvString("refresh-token"),
+	}
+
+	t.Run("skips the empty client credentials flow", func(t *testing.T) {
+		flows := OAuth2Flows{RefreshToken: refreshFlow}
+
+		err := flows.Validate()
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if flows.GetFlowType() != RefreshTokenFlow {
+			t.Errorf("expected flow %s, got: %s", RefreshTokenFlow, flows.GetFlowType())
+		}
+
+		if flows.IsZero() {
+			t.Error("expected IsZero to return false")
+		}
+	})
+
+	t.Run("validates all configured flows", func(t *testing.T) {
+		flows := OAuth2Flows{
+			RefreshToken:      refreshFlow,
+			AuthorizationCode: &AuthorizationCodeOAuthFlow{},
+		}
+
+		err := flows.Validate()
+		if !errors.Is(err, ErrAuthorizationURLRequired) {
+			t.Errorf("expected ErrAuthorizationURLRequired, got: %v", err)
+		}
+
+		if flows.GetFlowType() != AuthorizationCodeFlow {
+			t.Errorf("expected flow %s, got: %s", AuthorizationCodeFlow, flows.GetFlowType())
+		}
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		flows := OAuth2Flows{RefreshToken: refreshFlow}
+
+		if !flows.Equal(OAuth2Flows{RefreshToken: refreshFlow}) {
+			t.Error("expected equal flows")
+		}
+
+		if flows.Equal(OAuth2Flows{}) {
+			t.Error("expected different flows")
+		}
+	})
+
+	t.Run("unmarshals flows from JSON and YAML", func(t *testing.T) {
+		expected := OAuth2Flows{
+			AuthorizationCode: &AuthorizationCodeOAuthFlow{
+				AuthorizationURL: ptrEnvString("https://example.com/authorize"),
+				TokenURL:         ptrEnvString("https://example.com/token"),
+				ClientID:         ptrEnvString("client-id"),
+				Code:             ptrEnvString("code"),
+			},
+			RefreshToken: refreshFlow,
+		}
+
+		jsonData := `{
+			"authorizationCode": {
+				"authorizationUrl": {"value": "https://example.com/authorize"},
+				"tokenUrl": {"value": "https://example.com/token"},
+				"clientId": {"value": "client-id"},
+				"code": {"value": "code"}
+			},
+			"refreshToken": {
+				"refreshUrl": {"value": "https://example.com/token"},
+				"refreshToken": {"value": "refresh-token"}
+			}
+		}`
+
+		var jsonFlows OAuth2Flows
+
+		err := json.Unmarshal([]byte(jsonData), &jsonFlows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !jsonFlows.Equal(expected) {
+			t.Errorf("unexpected flows from JSON: %+v", jsonFlows)
+		}
+
+		yamlData := `
+authorizationCode:
+  authorizationUrl:
+    value: https://example.com/authorize
+  tokenUrl:
+    value: https://example.com/token
+  clientId:
+    value: client-id
+  code:
+    value: code
+refreshToken:
+  refreshUrl:
+    value: https://example.com/token
+  refreshToken:
+    value: refresh-token
+`
+
+		var yamlFlows OAuth2Flows
+
+		err = yaml.Load([]byte(yamlData), &yamlFlows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !yamlFlows.Equal(expected) {
+			t.Errorf("unexpected flows from YAML: %+v", yamlFlows)
+		}
+	})
 }
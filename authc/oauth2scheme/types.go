@@ -31,6 +31,8 @@ const (
 	PasswordFlow OAuthFlowType = "password"
 	// ClientCredentialsFlow represents the client credentials OAuth2 flow type.
 	ClientCredentialsFlow OAuthFlowType = "clientCredentials"
+	// RefreshTokenFlow represents the refresh token OAuth2 flow type.
+	RefreshTokenFlow OAuthFlowType = "refreshToken"
 )
 
 var enumValueOAuthFlowTypes = []OAuthFlowType{
@@ -38,6 +40,7 @@ var enumValueOAuthFlowTypes = []OAuthFlowType{
 	ImplicitFlow,
 	PasswordFlow,
 	ClientCredentialsFlow,
+	RefreshTokenFlow,
 }
 
 var errInvalidOAuthFlowType = fmt.Errorf(
@@ -80,15 +80,15 @@ func BenchmarkHTTPClient(b *testing.B) {
 
 		for b.Loop() {
 			resp, err := client.R(http.MethodGet, server.URL).
-				Execute(ctx)
+				Send(ctx)
 			if err != nil {
 				b.Fatal(err)
 			}
 
-			gohttpc.CloseResponse(resp)
+			resp.Close()
 
-			if resp.StatusCode != 200 {
-				slog.Error(resp.Status)
+			if resp.StatusCode() != 200 {
+				slog.Error(resp.RawResponse.Status)
 			}
 		}
 	})
@@ -0,0 +1,40 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import "math/rand/v2"
+
+// bodyCaptureSampler samples the fraction of requests which capture bodies in debug logs and span attributes.
+type bodyCaptureSampler struct {
+	rate float64
+	// random returns a pseudo-random number in [0, 1). It is replaceable for deterministic tests.
+	random func() float64
+}
+
+func newBodyCaptureSampler(rate float64) *bodyCaptureSampler {
+	return &bodyCaptureSampler{
+		rate:   min(max(rate, 0), 1),
+		random: rand.Float64,
+	}
+}
+
+// sample draws whether the bodies of the request are captured.
+func (s *bodyCaptureSampler) sample() bool {
+	if s == nil || s.rate >= 1 {
+		return true
+	}
+
+	return s.random() < s.rate
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/goutils"
+)
+
+func TestBodyCaptureSampleRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"secret":"response"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBodyCaptureSampleRate(0.5))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	draws := []float64{0.2, 0.8}
+	client.options.bodyCaptureSampler.random = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+
+		return draw
+	}
+
+	send := func(t *testing.T) string {
+		t.Helper()
+
+		var logs bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := context.WithValue(context.Background(), otelutils.LoggerContextKey, logger)
+
+		req := client.R(http.MethodPost, server.URL)
+		req.Header().Set("Content-Type", "application/json")
+		req.SetBody(strings.NewReader(`{"secret":"request"}`))
+
+		resp, err := req.Execute(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		return logs.String()
+	}
+
+	sampled := send(t)
+	if !strings.Contains(sampled, `\"secret\":\"request\"`) ||
+		!strings.Contains(sampled, `\"secret\":\"response\"`) {
+		t.Errorf("expected the bodies in the logs of the sampled request, got %s", sampled)
+	}
+
+	skipped := send(t)
+	if strings.Contains(skipped, "secret") {
+		t.Errorf("expected no bodies in the logs of the skipped request, got %s", skipped)
+	}
+
+	if !strings.Contains(skipped, `"status":200`) {
+		t.Errorf("expected the metadata in the logs of the skipped request, got %s", skipped)
+	}
+
+	if len(draws) != 0 {
+		t.Errorf("expected one draw per request, got %d remaining", len(draws))
+	}
+}
+
+func TestBodyCaptureSampler_Bounds(t *testing.T) {
+	never := func() float64 { return 0 }
+
+	if !(*bodyCaptureSampler)(nil).sample() {
+		t.Error("expected a nil sampler to capture all requests")
+	}
+
+	sampler := newBodyCaptureSampler(2)
+	sampler.random = never
+
+	if sampler.rate != 1 || !sampler.sample() {
+		t.Errorf("expected the rate to be clamped to 1, got %f", sampler.rate)
+	}
+
+	sampler = newBodyCaptureSampler(-1)
+	sampler.random = never
+
+	if sampler.rate != 0 || sampler.sample() {
+		t.Errorf("expected the rate to be clamped to 0, got %f", sampler.rate)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// cancelTagRegistry tracks cancel functions of in-flight requests keyed by the cancel tag.
+type cancelTagRegistry struct {
+	lock   sync.Mutex
+	nextID uint64
+	tags   map[string]map[uint64]context.CancelCauseFunc
+}
+
+func newCancelTagRegistry() *cancelTagRegistry {
+	return &cancelTagRegistry{
+		tags: map[string]map[uint64]context.CancelCauseFunc{},
+	}
+}
+
+// register tracks the cancel function of the in-flight request with the tag.
+// The returned function must be called to stop tracking when the request is done.
+func (ctr *cancelTagRegistry) register(tag string, cancel context.CancelCauseFunc) func() {
+	ctr.lock.Lock()
+	defer ctr.lock.Unlock()
+
+	ctr.nextID++
+	id := ctr.nextID
+
+	requests, ok := ctr.tags[tag]
+	if !ok {
+		requests = map[uint64]context.CancelCauseFunc{}
+		ctr.tags[tag] = requests
+	}
+
+	requests[id] = cancel
+
+	return func() {
+		ctr.lock.Lock()
+		defer ctr.lock.Unlock()
+
+		delete(requests, id)
+
+		// The tag may be re-registered with a new set after it was canceled.
+		if current, ok := ctr.tags[tag]; ok && len(current) == 0 {
+			delete(ctr.tags, tag)
+		}
+	}
+}
+
+// cancel cancels all in-flight requests with the tag and returns the number of canceled requests.
+func (ctr *cancelTagRegistry) cancel(tag string) int {
+	ctr.lock.Lock()
+
+	requests := ctr.tags[tag]
+	cancelFuncs := make([]context.CancelCauseFunc, 0, len(requests))
+
+	for _, cancel := range requests {
+		cancelFuncs = append(cancelFuncs, cancel)
+	}
+
+	delete(ctr.tags, tag)
+	ctr.lock.Unlock()
+
+	cause := fmt.Errorf("%w: %s", ErrRequestCanceledByTag, tag)
+
+	for _, cancel := range cancelFuncs {
+		cancel(cause)
+	}
+
+	return len(cancelFuncs)
+}
+
+// withCancelTag returns a copy of the context which is canceled when the cancel tag of the request is canceled,
+// and the function to release the context. The context is returned as-is if the request has no tag.
+func (r *Request) withCancelTag(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.cancelTag == "" || r.options.cancelTags == nil {
+		return ctx, nil
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	unregister := r.options.cancelTags.register(r.cancelTag, cancel)
+
+	return ctx, func() {
+		unregister()
+		cancel(nil)
+	}
+}
+
+// chainCancelFuncs returns a function which calls the cancel function and then the parent one if exists.
+func chainCancelFuncs(cancel context.CancelFunc, parent context.CancelFunc) context.CancelFunc {
+	if parent == nil {
+		return cancel
+	}
+
+	return func() {
+		cancel()
+		parent()
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+)
+
+func TestClient_CancelTag(t *testing.T) {
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 4)
+
+	// The server blocks until the request is canceled or released.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+
+		select {
+		case <-r.Context().Done():
+		case <-release:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	execute := func(tag string) <-chan error {
+		result := make(chan error, 1)
+
+		go func() {
+			req := client.R(http.MethodGet, server.URL)
+			req.SetCancelTag(tag)
+
+			resp, err := req.Execute(context.Background())
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			result <- err
+		}()
+
+		return result
+	}
+
+	tagged := []<-chan error{execute("page-1"), execute("page-1")}
+	otherTag := execute("page-2")
+	untagged := execute("")
+
+	// Wait until all requests are in-flight.
+	for range 4 {
+		select {
+		case <-arrived:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for in-flight requests")
+		}
+	}
+
+	if n := client.CancelTag("unknown"); n != 0 {
+		t.Fatalf("expected no canceled request, got: %d", n)
+	}
+
+	if n := client.CancelTag("page-1"); n != 2 {
+		t.Fatalf("expected 2 canceled requests, got: %d", n)
+	}
+
+	for _, result := range tagged {
+		select {
+		case err := <-result:
+			if !errors.Is(err, gohttpc.ErrRequestCanceledByTag) {
+				t.Errorf("expected request canceled by tag error, got: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the tagged request to be canceled promptly")
+		}
+	}
+
+	select {
+	case err := <-untagged:
+		t.Fatalf("expected the untagged request to continue, got: %v", err)
+	case err := <-otherTag:
+		t.Fatalf("expected the request with another tag to continue, got: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	for _, result := range []<-chan error{untagged, otherTag} {
+		if err := <-result; err != nil {
+			t.Errorf("expected no error, got: %s", err)
+		}
+	}
+
+	if n := client.CancelTag("page-2"); n != 0 {
+		t.Errorf("expected no in-flight request after completion, got: %d", n)
+	}
+}
+
+func TestClient_CancelTagOnClone(t *testing.T) {
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+
+		select {
+		case <-r.Context().Done():
+		case <-release:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	cloned := client.Clone()
+
+	result := make(chan error, 1)
+
+	go func() {
+		req := client.R(http.MethodGet, server.URL)
+		req.SetCancelTag("page-1")
+
+		resp, err := req.Execute(context.Background())
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		result <- err
+	}()
+
+	select {
+	case <-arrived:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the in-flight request")
+	}
+
+	if n := cloned.CancelTag("page-1"); n != 0 {
+		t.Fatalf("expected the clone not to cancel requests of the original client, got: %d", n)
+	}
+
+	close(release)
+
+	if err := <-result; err != nil {
+		t.Errorf("expected no error, got: %s", err)
+	}
+}
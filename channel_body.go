@@ -0,0 +1,80 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// SetBodyFromChannel sets the request body from chunks produced by the channel.
+// The body is sent with chunked transfer encoding and ends when the channel is closed.
+// Retries are disabled for the request because the body can't be replayed.
+func (r *Request) SetBodyFromChannel(ch <-chan []byte) {
+	r.body = newChannelReader(ch)
+	r.streaming = true
+	r.consumed = false
+}
+
+// channelReader adapts a channel of byte chunks to an [io.ReadCloser].
+type channelReader struct {
+	ch        <-chan []byte
+	ctx       context.Context //nolint:containedctx
+	buf       []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newChannelReader(ch <-chan []byte) *channelReader {
+	return &channelReader{
+		ch:   ch,
+		ctx:  context.Background(),
+		done: make(chan struct{}),
+	}
+}
+
+// Read reads the next chunk from the channel. It returns [io.EOF] when the channel is closed.
+// Reading stops if the context is canceled or the reader is closed.
+func (cr *channelReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		select {
+		case <-cr.ctx.Done():
+			return 0, cr.ctx.Err()
+		case <-cr.done:
+			return 0, io.ErrClosedPipe
+		case chunk, ok := <-cr.ch:
+			if !ok {
+				return 0, io.EOF
+			}
+
+			cr.buf = chunk
+		}
+	}
+
+	n := copy(p, cr.buf)
+	cr.buf = cr.buf[n:]
+
+	return n, nil
+}
+
+// Close stops reading the channel.
+func (cr *channelReader) Close() error {
+	cr.closeOnce.Do(func() {
+		close(cr.done)
+	})
+
+	return nil
+}
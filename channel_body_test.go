@@ -0,0 +1,124 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+)
+
+func TestRequestBodyFromChannel(t *testing.T) {
+	var attempts atomic.Int32
+
+	var receivedBody string
+
+	var transferEncoding []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		receivedBody = string(body)
+		transferEncoding = r.TransferEncoding
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 3,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithRetry(retry))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	t.Run("streams chunks until the channel is closed", func(t *testing.T) {
+		ch := make(chan []byte)
+
+		go func() {
+			defer close(ch)
+
+			for _, chunk := range []string{"event-1\n", "event-2\n", "event-3\n"} {
+				ch <- []byte(chunk)
+			}
+		}()
+
+		req := client.R(http.MethodPost, server.URL)
+		req.SetBodyFromChannel(ch)
+
+		resp, err := req.Execute(t.Context())
+		if err == nil {
+			t.Fatal("expected the service unavailable error, got nil")
+		}
+
+		goutils.CloseResponse(resp)
+
+		if receivedBody != "event-1\nevent-2\nevent-3\n" {
+			t.Errorf("expected the concatenated body, got: %q", receivedBody)
+		}
+
+		if !slices.Equal(transferEncoding, []string{"chunked"}) {
+			t.Errorf("expected chunked transfer encoding, got: %v", transferEncoding)
+		}
+
+		if attempts.Load() != 1 {
+			t.Errorf("expected retries to be disabled, got %d attempts", attempts.Load())
+		}
+	})
+
+	t.Run("stops reading when the context is canceled", func(t *testing.T) {
+		ch := make(chan []byte)
+		defer close(ch)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		defer cancel()
+
+		req := client.R(http.MethodPost, server.URL)
+		req.SetBodyFromChannel(ch)
+
+		start := time.Now()
+
+		_, err := req.Execute(ctx) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the request to stop after the context is canceled, got: %s", elapsed)
+		}
+	})
+}
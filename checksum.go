@@ -0,0 +1,145 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+const (
+	// ChecksumMD5 is the checksum algorithm that sets the Content-MD5 header (RFC 1864).
+	ChecksumMD5 = "MD5"
+	// ChecksumSHA256 is the checksum algorithm that sets the Digest header (RFC 3230) with SHA-256.
+	ChecksumSHA256 = "SHA-256"
+	// ChecksumSHA512 is the checksum algorithm that sets the Digest header (RFC 3230) with SHA-512.
+	ChecksumSHA512 = "SHA-512"
+)
+
+const (
+	headerContentMD5 = "Content-MD5"
+	headerDigest     = "Digest"
+)
+
+// BodyChecksum returns the checksum algorithm of the request body.
+func (r *Request) BodyChecksum() string {
+	return r.checksumAlgorithm
+}
+
+// SetBodyChecksum sets the algorithm to compute the checksum header of the request body.
+// Supported algorithms are [ChecksumMD5], [ChecksumSHA256] and [ChecksumSHA512].
+// An empty string disables the checksum.
+func (r *Request) SetBodyChecksum(algo string) {
+	r.checksumAlgorithm = algo
+}
+
+// applyBodyChecksum computes the digest of the body and sets the checksum header.
+// The returned reader is rewound to its original offset so the sent bytes match the checksum.
+func (r *Request) applyBodyChecksum(body io.Reader) (io.Reader, error) {
+	if r.checksumAlgorithm == "" || body == nil {
+		return body, nil
+	}
+
+	headerName, newHash, err := parseChecksumAlgorithm(r.checksumAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	bodySeeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		bodyBytes, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+
+		bodySeeker = bytes.NewReader(bodyBytes)
+	}
+
+	offset, err := bodySeeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	hasher := newHash()
+
+	_, err = io.Copy(hasher, bodySeeker)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = bodySeeker.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+
+	if headerName == headerDigest {
+		checksum = strings.ToUpper(r.checksumAlgorithm) + "=" + checksum
+	}
+
+	r.Header().Set(headerName, checksum)
+
+	return bodySeeker, nil
+}
+
+// bodyHash computes the SHA-256 hex digest of the seekable request body and rewinds the body afterward.
+// Returns an empty string if the body isn't seekable.
+func bodyHash(body io.Reader) (string, error) {
+	bodySeeker, ok := body.(io.ReadSeeker)
+	if !ok {
+		return "", nil
+	}
+
+	offset, err := bodySeeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	hasher := sha256.New()
+
+	_, err = io.Copy(hasher, bodySeeker)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = bodySeeker.Seek(offset, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func parseChecksumAlgorithm(algo string) (string, func() hash.Hash, error) {
+	switch strings.ToUpper(algo) {
+	case ChecksumMD5:
+		return headerContentMD5, md5.New, nil
+	case ChecksumSHA256:
+		return headerDigest, sha256.New, nil
+	case ChecksumSHA512:
+		return headerDigest, sha512.New, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedChecksumAlgorithm, algo)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	spanRecorder     *tracetest.SpanRecorder
+	spanRecorderOnce sync.Once
+)
+
+// getSpanRecorder registers the global tracer provider with a span recorder once,
+// because the tracer of the client delegates to the first registered provider only.
+func getSpanRecorder() *tracetest.SpanRecorder {
+	spanRecorderOnce.Do(func() {
+		spanRecorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+
+	return spanRecorder
+}
+
+// findSpanAttribute finds the attribute value of the last ended span with the name.
+func findSpanAttribute(recorder *tracetest.SpanRecorder, spanName string, key string) (string, bool) {
+	spans := recorder.Ended()
+
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() != spanName {
+			continue
+		}
+
+		for _, attr := range spans[i].Attributes() {
+			if string(attr.Key) == key {
+				return attr.Value.Emit(), true
+			}
+		}
+
+		return "", false
+	}
+
+	return "", false
+}
+
+func TestRequestBodyChecksum(t *testing.T) {
+	const body = "hello world"
+
+	testCases := []struct {
+		Name          string
+		Algorithm     string
+		Header        string
+		Expected      string
+		NonSeekable   bool
+		Offset        int64
+		ExpectedError error
+	}{
+		{
+			Name:      "md5",
+			Algorithm: gohttpc.ChecksumMD5,
+			Header:    "Content-MD5",
+			Expected:  "XrY7u+Ae7tCTyyK7j1rNww==",
+		},
+		{
+			Name:      "sha-256",
+			Algorithm: gohttpc.ChecksumSHA256,
+			Header:    "Digest",
+			Expected:  "SHA-256=uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=",
+		},
+		{
+			Name:        "sha-256_non_seekable",
+			Algorithm:   "sha-256",
+			Header:      "Digest",
+			Expected:    "SHA-256=uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=",
+			NonSeekable: true,
+		},
+		{
+			Name:      "sha-256_seeked",
+			Algorithm: gohttpc.ChecksumSHA256,
+			Header:    "Digest",
+			Expected:  "SHA-256=SG6kYiTRu0+2gPNPfJrZao8k7Ii+c+qOWmxlJg6cuKc=",
+			Offset:    6,
+		},
+		{
+			Name:          "unsupported",
+			Algorithm:     "crc32",
+			ExpectedError: gohttpc.ErrUnsupportedChecksumAlgorithm,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var receivedHeader, receivedBody string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedHeader = r.Header.Get(tc.Header)
+
+				rawBody, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("failed to read request body: %s", err)
+				}
+
+				receivedBody = string(rawBody)
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := gohttpc.NewClient()
+			defer goutils.CatchWarnErrorFunc(client.Close)
+
+			bodyReader := strings.NewReader(body)
+
+			_, err := bodyReader.Seek(tc.Offset, io.SeekStart)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var reader io.Reader = bodyReader
+			if tc.NonSeekable {
+				reader = io.MultiReader(reader)
+			}
+
+			req := client.R(http.MethodPost, server.URL)
+			req.SetBody(reader)
+			req.SetBodyChecksum(tc.Algorithm)
+
+			resp, err := req.Execute(t.Context())
+			if tc.ExpectedError != nil {
+				if !errors.Is(err, tc.ExpectedError) {
+					t.Fatalf("expected error %v, got %v", tc.ExpectedError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			if receivedHeader != tc.Expected {
+				t.Errorf("expected %s header %s, got: %s", tc.Header, tc.Expected, receivedHeader)
+			}
+
+			if receivedBody != body[tc.Offset:] {
+				t.Errorf("expected the body %q to be sent, got: %q", body[tc.Offset:], receivedBody)
+			}
+		})
+	}
+}
+
+func TestBodyHashAttribute(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithBodyHashAttribute(true))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	execute := func(t *testing.T, body io.Reader) (string, bool) {
+		t.Helper()
+
+		req := client.R(http.MethodPost, server.URL)
+		req.SetBody(body)
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		return findSpanAttribute(recorder, "Request", "http.request.body.hash")
+	}
+
+	hash1, ok := execute(t, strings.NewReader("hello world"))
+	if !ok {
+		t.Fatal("expected the body hash attribute")
+	}
+
+	const expected = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if hash1 != expected {
+		t.Errorf("expected hash %s, got: %s", expected, hash1)
+	}
+
+	hash2, _ := execute(t, strings.NewReader("hello world"))
+	if hash2 != hash1 {
+		t.Errorf("expected the hash to be stable, got: %s and %s", hash1, hash2)
+	}
+
+	hash3, _ := execute(t, strings.NewReader("hello gohttpc"))
+	if hash3 == hash1 {
+		t.Errorf("expected a different hash for a different body, got: %s", hash3)
+	}
+
+	_, ok = execute(t, io.MultiReader(strings.NewReader("hello world")))
+	if ok {
+		t.Error("expected no hash attribute for non-seekable bodies")
+	}
+}
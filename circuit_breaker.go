@@ -0,0 +1,154 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CircuitBreakerConfig holds the configuration of the circuit breaker of the client.
+type CircuitBreakerConfig struct {
+	// The name of the circuit breaker, e.g. the host of the remote service.
+	// It is recorded as the server address attribute of the server state metric.
+	Name string
+	// The number of consecutive failures to open the circuit. Defaults to 5.
+	FailureThreshold uint
+	// The number of consecutive successful trial requests to close the half-open circuit. Defaults to 1.
+	SuccessThreshold uint
+	// How long the circuit stays open before trial requests are permitted. Defaults to 1 minute.
+	Delay time.Duration
+}
+
+// IsServerOutageStatus checks if the HTTP status indicates a server outage, that is any status >= 502 except 504.
+// The gateway timeout status may be caused by the slow backend, so it isn't considered as an outage.
+func IsServerOutageStatus(status int) bool {
+	return status >= http.StatusBadGateway && status != http.StatusGatewayTimeout
+}
+
+// NewCircuitBreaker creates a circuit breaker from the config.
+// State transitions are recorded by the server state metric of the global metrics.
+func NewCircuitBreaker(config CircuitBreakerConfig) circuitbreaker.CircuitBreaker[*http.Response] {
+	return newCircuitBreaker(config, GetHTTPClientMetrics)
+}
+
+// newCircuitBreaker creates a circuit breaker which records state transitions to the metrics
+// which are resolved on every transition, so metrics which are set later are respected.
+func newCircuitBreaker(
+	config CircuitBreakerConfig,
+	getMetrics func() *HTTPClientMetrics,
+) circuitbreaker.CircuitBreaker[*http.Response] {
+	failureThreshold := config.FailureThreshold
+	if failureThreshold == 0 {
+		failureThreshold = 5
+	}
+
+	delay := config.Delay
+	if delay <= 0 {
+		delay = time.Minute
+	}
+
+	metricsAttrs := metric.WithAttributeSet(attribute.NewSet(semconv.ServerAddress(config.Name)))
+
+	breaker := circuitbreaker.NewBuilder[*http.Response]().
+		WithFailureThreshold(failureThreshold).
+		WithSuccessThreshold(max(config.SuccessThreshold, 1)).
+		WithDelay(delay).
+		OnStateChanged(func(sce circuitbreaker.StateChangedEvent) {
+			getMetrics().ServerState.Record(context.TODO(), int64(sce.NewState), metricsAttrs)
+		}).
+		Build()
+
+	// Record initial metrics for the closed state.
+	getMetrics().ServerState.Record(
+		context.TODO(),
+		int64(circuitbreaker.ClosedState),
+		metricsAttrs,
+	)
+
+	return breaker
+}
+
+// doRequestWithCircuitBreaker sends the request if the circuit breaker permits, and records the result.
+// Transport errors and server outage statuses are recorded as failures.
+// Requests which are canceled by the caller are not counted as failures.
+func (r *Request) doRequestWithCircuitBreaker(
+	ctx context.Context,
+	clientGetter HTTPClientGetter,
+	endpoint *url.URL,
+	body io.Reader,
+	logger *slog.Logger,
+) (*http.Response, error) {
+	breaker := r.options.CircuitBreaker
+	if breaker == nil {
+		return r.doRequest(ctx, clientGetter, endpoint, body, logger)
+	}
+
+	oldState := breaker.State()
+
+	if !breaker.TryAcquirePermit() {
+		return nil, fmt.Errorf(
+			"%w: retry after %s: %w",
+			ErrCircuitBreakerOpen,
+			breaker.RemainingDelay(),
+			circuitbreaker.ErrOpen,
+		)
+	}
+
+	resp, err := r.doRequest(ctx, clientGetter, endpoint, body, logger)
+
+	switch {
+	case resp != nil && IsServerOutageStatus(resp.StatusCode):
+		breaker.RecordFailure()
+	case resp == nil && err != nil && !errors.Is(err, context.Canceled):
+		breaker.RecordFailure()
+	default:
+		breaker.RecordSuccess()
+	}
+
+	if newState := breaker.State(); newState != oldState {
+		addCircuitBreakerSpanEvent(ctx, oldState, newState)
+	}
+
+	return resp, err
+}
+
+// addCircuitBreakerSpanEvent adds the state change event of the circuit breaker to the active span.
+func addCircuitBreakerSpanEvent(ctx context.Context, oldState circuitbreaker.State, newState circuitbreaker.State) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.AddEvent(
+		"circuit_breaker.state_change",
+		trace.WithAttributes(
+			attribute.String("circuit_breaker.old_state", oldState.String()),
+			attribute.String("circuit_breaker.new_state", newState.String()),
+		),
+	)
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		status   atomic.Int32
+		attempts atomic.Int32
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	delay := 100 * time.Millisecond
+	client := gohttpc.NewClient(gohttpc.WithCircuitBreaker(gohttpc.CircuitBreakerConfig{
+		Name:             "test",
+		FailureThreshold: 2,
+		Delay:            delay,
+	}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	send := func() error {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if resp != nil {
+			goutils.CloseResponse(resp)
+		}
+
+		return err
+	}
+
+	// Client errors are not server outages.
+	status.Store(http.StatusBadRequest)
+
+	for range 3 {
+		_ = send()
+	}
+
+	if attempts.Load() != 3 {
+		t.Fatalf("expected the circuit breaker to be closed, got %d attempts", attempts.Load())
+	}
+
+	// Consecutive outage statuses open the circuit.
+	status.Store(http.StatusServiceUnavailable)
+
+	for range 2 {
+		_ = send()
+	}
+
+	attempts.Store(0)
+
+	err = send()
+	if !errors.Is(err, gohttpc.ErrCircuitBreakerOpen) {
+		t.Fatalf("expected the circuit breaker open error, got: %v", err)
+	}
+
+	if attempts.Load() != 0 {
+		t.Fatalf("expected the request to fail fast, got %d attempts", attempts.Load())
+	}
+
+	assertServerState(t, reader, circuitbreaker.OpenState)
+
+	// A failed trial request in the half-open state reopens the circuit.
+	time.Sleep(delay + 20*time.Millisecond)
+
+	_ = send()
+
+	if attempts.Load() != 1 {
+		t.Fatalf("expected the trial request to be sent, got %d attempts", attempts.Load())
+	}
+
+	err = send()
+	if !errors.Is(err, gohttpc.ErrCircuitBreakerOpen) || attempts.Load() != 1 {
+		t.Fatalf("expected the circuit breaker to be reopened, got: %v", err)
+	}
+
+	// A successful trial request in the half-open state closes the circuit.
+	time.Sleep(delay + 20*time.Millisecond)
+	status.Store(http.StatusOK)
+
+	err = send()
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	err = send()
+	if err != nil {
+		t.Fatalf("expected the circuit breaker to be closed, got: %s", err)
+	}
+
+	assertServerState(t, reader, circuitbreaker.ClosedState)
+
+	t.Run("retries_stop_when_open", func(t *testing.T) {
+		retryDelay := int64(1)
+
+		retry, err := httpconfig.HTTPRetryConfig{
+			MaxAttempts: 5,
+			Delay:       &retryDelay,
+		}.ToRetryPolicy() //nolint:bodyclose
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRetry(retry),
+			gohttpc.WithCircuitBreaker(gohttpc.CircuitBreakerConfig{FailureThreshold: 2}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		status.Store(http.StatusServiceUnavailable)
+		attempts.Store(0)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if resp != nil {
+			goutils.CloseResponse(resp)
+		}
+
+		if !errors.Is(err, gohttpc.ErrCircuitBreakerOpen) {
+			t.Fatalf("expected the circuit breaker open error, got: %v", err)
+		}
+
+		if attempts.Load() != 2 {
+			t.Errorf("expected retries to stop once the circuit is open, got %d attempts", attempts.Load())
+		}
+	})
+}
+
+func assertServerState(t *testing.T, reader sdkmetric.Reader, expected circuitbreaker.State) {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			if !ok || m.Name != "http.client.server_state" {
+				continue
+			}
+
+			for _, dp := range gauge.DataPoints {
+				if dp.Value != int64(expected) {
+					t.Errorf("expected the server state %d, got: %d", expected, dp.Value)
+				}
+			}
+
+			return
+		}
+	}
+
+	t.Error("expected the server state metric to be recorded")
+}
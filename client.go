@@ -18,6 +18,8 @@ import (
 	"context"
 	"io"
 	"net/http"
+
+	"github.com/relychan/gohttpc/authc/authscheme"
 )
 
 // HTTPClientGetter abstracts an interface to get an HTTP client.
@@ -40,9 +42,26 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// ServerNameClient abstracts an HTTP client which can send a request with a custom TLS server name (SNI).
+type ServerNameClient interface {
+	// DoWithServerName sends an HTTP request with the TLS server name overridden for this request only.
+	DoWithServerName(req *http.Request, serverName string) (*http.Response, error)
+}
+
+// AuthenticatorClient abstracts an HTTP client which authenticates requests with its own authenticator
+// when they are created, e.g. a load-balanced host with per-host credentials.
+// The authenticator of the client takes precedence over the default authenticator of the client options,
+// but the authenticator of the request still overrides it.
+type AuthenticatorClient interface {
+	// Authenticator returns the authenticator which is applied when the request is created.
+	Authenticator() authscheme.HTTPClientAuthenticator
+}
+
 // Client represents an HTTP client wrapper with extended functionality.
 type Client struct {
 	options *ClientOptions
+	// Caches round-trippers of requests with overridden TLS server names.
+	serverNameTransports ServerNameTransports
 }
 
 // NewClient creates a new HTTP client wrapper.
@@ -58,6 +77,11 @@ func NewClientWithOptions(options *ClientOptions) *Client {
 		}
 	}
 
+	// The authenticator sends its own requests with the TLS, proxy and timeout settings of the client.
+	if setter, ok := options.Authenticator.(authscheme.HTTPClientSetter); ok {
+		setter.SetHTTPClient(options.HTTPClient)
+	}
+
 	return &Client{
 		options: options,
 	}
@@ -97,6 +121,11 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 	return c.options.HTTPClient.Do(req) //nolint:gosec
 }
 
+// DoWithServerName sends an HTTP request with the TLS server name overridden for this request only.
+func (c *Client) DoWithServerName(req *http.Request, serverName string) (*http.Response, error) {
+	return c.serverNameTransports.Do(c.options.HTTPClient, req, serverName)
+}
+
 // Clone creates a new client with properties copied.
 func (c *Client) Clone(options ...ClientOption) *Client {
 	return &Client{
@@ -104,12 +133,24 @@ func (c *Client) Clone(options ...ClientOption) *Client {
 	}
 }
 
+// CancelTag cancels all in-flight requests of the client with the cancel tag.
+// Returns the number of canceled requests.
+func (c *Client) CancelTag(tag string) int {
+	if c.options.cancelTags == nil {
+		return 0
+	}
+
+	return c.options.cancelTags.cancel(tag)
+}
+
 // Close terminates internal processes.
 func (c *Client) Close() error {
 	if c.options.HTTPClient != nil {
 		c.options.HTTPClient.CloseIdleConnections()
 	}
 
+	c.serverNameTransports.CloseIdleConnections()
+
 	if c.options.Authenticator != nil {
 		return c.options.Authenticator.Close()
 	}
@@ -0,0 +1,180 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/relychan/gocompress"
+)
+
+// EncodingBrotli represents the Brotli compression format.
+const EncodingBrotli gocompress.CompressionFormat = "br"
+
+// contentCompressors holds the compressors of supported content encodings.
+// It extends the default compressors of gocompress with Brotli.
+var contentCompressors = map[gocompress.CompressionFormat]gocompress.Compressor{ //nolint:gochecknoglobals
+	EncodingBrotli:             BrotliCompressor{},
+	gocompress.EncodingZstd:    gocompress.ZstdCompressor{},
+	gocompress.EncodingGzip:    gocompress.GzipCompressor{},
+	gocompress.EncodingDeflate: gocompress.DeflateCompressor{},
+}
+
+// AcceptEncoding returns the value of the Accept-Encoding header with content encodings supported by the client.
+func AcceptEncoding() string {
+	return "br, zstd, gzip, deflate"
+}
+
+// negotiateAcceptEncoding returns the Accept-Encoding header value of supported encodings in the input list.
+func negotiateAcceptEncoding(encodings []string) string {
+	if len(encodings) == 0 {
+		return AcceptEncoding()
+	}
+
+	supportedEncodings := make([]string, 0, len(encodings))
+
+	for _, encoding := range encodings {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+
+		if _, ok := contentCompressors[gocompress.CompressionFormat(encoding)]; ok &&
+			!slices.Contains(supportedEncodings, encoding) {
+			supportedEncodings = append(supportedEncodings, encoding)
+		}
+	}
+
+	return strings.Join(supportedEncodings, ", ")
+}
+
+// BrotliCompressor implements the compression handler for Brotli encoding.
+type BrotliCompressor struct{}
+
+var _ gocompress.Compressor = (*BrotliCompressor)(nil)
+
+// NewWriter will create a new compression encoder.
+func (bc BrotliCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriter(w), nil
+}
+
+// Compress the reader content with Brotli encoding.
+func (bc BrotliCompressor) Compress(w io.Writer, src io.Reader) (int64, error) {
+	bw := brotli.NewWriter(w)
+
+	size, err := io.Copy(bw, src)
+	closeErr := bw.Close()
+
+	if err != nil {
+		return size, err
+	}
+
+	return size, closeErr
+}
+
+// Decompress the reader content with Brotli encoding.
+func (bc BrotliCompressor) Decompress(reader io.ReadCloser) (io.ReadCloser, error) {
+	return brotliReader{
+		Reader:         brotli.NewReader(reader),
+		originalReader: reader,
+	}, nil
+}
+
+type brotliReader struct {
+	*brotli.Reader
+
+	originalReader io.ReadCloser
+}
+
+func (br brotliReader) Close() error {
+	return br.originalReader.Close()
+}
+
+// parseContentEncoding parses the formats of the Content-Encoding header in the order they were applied.
+// Unsupported formats are skipped. The first error is returned if there is any.
+func parseContentEncoding(encoding string) ([]gocompress.CompressionFormat, error) {
+	var err error
+
+	parts := strings.Split(strings.ToLower(encoding), ",")
+	formats := make([]gocompress.CompressionFormat, 0, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" || part == gocompress.EncodingIdentity {
+			continue
+		}
+
+		format := gocompress.CompressionFormat(part)
+
+		if _, ok := contentCompressors[format]; !ok {
+			if err == nil {
+				err = fmt.Errorf("%w: %s", gocompress.ErrUnsupportedCompressionFormat, part)
+			}
+
+			continue
+		}
+
+		formats = append(formats, format)
+	}
+
+	return formats, err
+}
+
+// compressContent compresses the data in the order of formats listed.
+// For example, deflate, gzip means the data is deflated first, then gzipped.
+func compressContent(w io.Writer, data io.Reader, formats []gocompress.CompressionFormat) error {
+	for i, format := range formats {
+		compressor := contentCompressors[format]
+
+		if i == len(formats)-1 {
+			_, err := compressor.Compress(w, data)
+
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+
+		_, err := compressor.Compress(buf, data)
+		if err != nil {
+			return err
+		}
+
+		data = buf
+	}
+
+	_, err := io.Copy(w, data)
+
+	return err
+}
+
+// decompressContent decompresses the reader in the reverse order of formats listed.
+// Because the decompression is lazy, the original reader should be closed on error.
+func decompressContent(
+	reader io.ReadCloser,
+	formats []gocompress.CompressionFormat,
+) (io.ReadCloser, error) {
+	var err error
+
+	for i := len(formats) - 1; i >= 0; i-- {
+		reader, err = contentCompressors[formats[i]].Decompress(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reader, nil
+}
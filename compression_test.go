@@ -0,0 +1,351 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/relychan/gocompress"
+	"github.com/relychan/gohttpc"
+)
+
+func TestCompression_ZstdRequestBrotliResponse(t *testing.T) {
+	const (
+		requestBody  = `{"message":"hello zstd"}`
+		responseBody = `{"message":"hello brotli"}`
+	)
+
+	var (
+		receivedEncoding string
+		receivedBody     string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get("Content-Encoding")
+
+		decoder, err := zstd.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		defer decoder.Close()
+
+		body, err := io.ReadAll(decoder)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		receivedBody = string(body)
+
+		w.Header().Set("Content-Encoding", "br")
+		w.Header().Set("Content-Type", "application/json")
+
+		bw := brotli.NewWriter(w)
+		_, _ = bw.Write([]byte(responseBody))
+		_ = bw.Close()
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	req := client.R(http.MethodPost, server.URL)
+	req.Header().Set("Content-Encoding", "zstd")
+	req.SetBody(strings.NewReader(requestBody))
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if receivedEncoding != "zstd" {
+		t.Errorf("expected zstd request encoding, got: %s", receivedEncoding)
+	}
+
+	if receivedBody != requestBody {
+		t.Errorf("expected request body %s, got: %s", requestBody, receivedBody)
+	}
+
+	if string(body) != responseBody {
+		t.Errorf("expected response body %s, got: %s", responseBody, string(body))
+	}
+}
+
+func TestCompression_MultipleEncodings(t *testing.T) {
+	const content = "hello world"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body is compressed with Brotli then gzip, so it must be gunzipped first.
+		w.Header().Set("Content-Encoding", r.Header.Get("Content-Encoding"))
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	req := client.R(http.MethodPost, server.URL)
+	req.Header().Set("Content-Encoding", "br, gzip")
+	req.SetBody(strings.NewReader(content))
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != content {
+		t.Errorf("expected %s, got: %s", content, string(body))
+	}
+}
+
+func TestCompression_UnsupportedResponseEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "compress")
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	if !errors.Is(err, gocompress.ErrUnsupportedCompressionFormat) {
+		t.Errorf("expected ErrUnsupportedCompressionFormat, got: %v", err)
+	}
+}
+
+func TestBrotliCompressor(t *testing.T) {
+	const content = "hello brotli"
+
+	var buf bytes.Buffer
+
+	compressor := gohttpc.BrotliCompressor{}
+
+	_, err := compressor.Compress(&buf, strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := compressor.Decompress(io.NopCloser(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer reader.Close()
+
+	result, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(result) != content {
+		t.Errorf("expected %s, got: %s", content, string(result))
+	}
+
+	if encoding := gohttpc.AcceptEncoding(); encoding != "br, zstd, gzip, deflate" {
+		t.Errorf("unexpected accept encoding: %s", encoding)
+	}
+}
+
+func TestWithAcceptEncoding(t *testing.T) {
+	const content = "hello gzip"
+
+	testCases := []struct {
+		name      string
+		encodings []string
+		expected  string
+	}{
+		{
+			name:     "all supported encodings",
+			expected: "br, zstd, gzip, deflate",
+		},
+		{
+			name:      "filter unsupported encodings",
+			encodings: []string{"GZIP", "compress", "gzip", " br "},
+			expected:  "gzip, br",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var acceptEncoding string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = r.Header.Get("Accept-Encoding")
+
+				if !strings.Contains(acceptEncoding, "gzip") {
+					_, _ = w.Write([]byte(content))
+
+					return
+				}
+
+				var buf bytes.Buffer
+
+				_, _ = gocompress.GzipCompressor{}.Compress(&buf, strings.NewReader(content))
+
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+				_, _ = w.Write(buf.Bytes())
+			}))
+			defer server.Close()
+
+			client := gohttpc.NewClient(
+				gohttpc.WithHTTPClient(&http.Client{
+					Transport: &http.Transport{DisableCompression: true},
+				}),
+				gohttpc.WithAcceptEncoding(tc.encodings...),
+			)
+			defer client.Close()
+
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if acceptEncoding != tc.expected {
+				t.Errorf("expected Accept-Encoding %s, got: %s", tc.expected, acceptEncoding)
+			}
+
+			if string(body) != content {
+				t.Errorf("expected %s, got: %s", content, string(body))
+			}
+
+			if resp.Header.Get("Content-Encoding") != "" || resp.Header.Get("Content-Length") != "" {
+				t.Errorf("expected encoding headers to be stripped, got: %v", resp.Header)
+			}
+
+			if resp.ContentLength != -1 || !resp.Uncompressed {
+				t.Errorf("expected an uncompressed response with unknown length, got: %d", resp.ContentLength)
+			}
+		})
+	}
+}
+
+func TestWithCompressionMinSize(t *testing.T) {
+	const minSize = 64
+
+	testCases := []struct {
+		name             string
+		body             string
+		expectedEncoding string
+	}{
+		{
+			name: "small body",
+			body: strings.Repeat("a", minSize-1),
+		},
+		{
+			name:             "body equal to the threshold",
+			body:             strings.Repeat("a", minSize),
+			expectedEncoding: "gzip",
+		},
+		{
+			name:             "large body",
+			body:             strings.Repeat("a", minSize*10),
+			expectedEncoding: "gzip",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				receivedEncoding string
+				receivedBody     string
+			)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedEncoding = r.Header.Get("Content-Encoding")
+
+				var reader io.Reader = r.Body
+
+				if receivedEncoding != "" {
+					decompressed, err := gocompress.DefaultCompressor.Decompress(r.Body, receivedEncoding)
+					if err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+
+						return
+					}
+
+					defer decompressed.Close()
+
+					reader = decompressed
+				}
+
+				body, _ := io.ReadAll(reader)
+				receivedBody = string(body)
+			}))
+			defer server.Close()
+
+			client := gohttpc.NewClient(gohttpc.WithCompressionMinSize(minSize))
+			defer client.Close()
+
+			req := client.R(http.MethodPost, server.URL)
+			req.Header().Set("Content-Encoding", "gzip")
+			req.SetBody(strings.NewReader(tc.body))
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			if receivedEncoding != tc.expectedEncoding {
+				t.Errorf("expected Content-Encoding %q, got: %q", tc.expectedEncoding, receivedEncoding)
+			}
+
+			if receivedBody != tc.body {
+				t.Errorf("expected body %s, got: %s", tc.body, receivedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"net/http/httptrace"
+	"time"
+)
+
+// connectTimer cancels the request if the connection isn't obtained within the timeout.
+// The timer is stopped when the transport gets the connection, so the response may take longer.
+type connectTimer struct {
+	timeout time.Duration
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+}
+
+// newConnectTimer returns the request context which is canceled with [ErrConnectTimeout]
+// when the timer is expired before the connection is obtained.
+func newConnectTimer(ctx context.Context, timeout time.Duration) (context.Context, *connectTimer) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	ct := &connectTimer{
+		timeout: timeout,
+		cancel:  cancel,
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			ct.stop()
+		},
+	})
+
+	return ctx, ct
+}
+
+// start starts the timer. It must be called right before sending the request.
+func (ct *connectTimer) start() {
+	ct.timer = time.AfterFunc(ct.timeout, func() {
+		ct.cancel(ErrConnectTimeout)
+	})
+}
+
+func (ct *connectTimer) stop() {
+	if ct.timer != nil {
+		ct.timer.Stop()
+	}
+}
+
+// release stops the timer and releases resources of the request context.
+func (ct *connectTimer) release() {
+	ct.stop()
+	ct.cancel(nil)
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+)
+
+func TestConnectTimeout(t *testing.T) {
+	t.Run("stalled_body", func(t *testing.T) {
+		// The server sends the response header immediately, then stalls the body.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		client := gohttpc.NewClient()
+		defer client.Close()
+
+		req := client.R(http.MethodGet, server.URL)
+		req.SetConnectTimeout(100 * time.Millisecond)
+		req.SetTimeout(300 * time.Millisecond)
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		defer resp.Body.Close()
+
+		// The connect timeout must not be triggered after the connection is obtained.
+		_, err = io.ReadAll(resp.Body)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the total timeout error, got: %v", err)
+		}
+
+		if errors.Is(err, gohttpc.ErrConnectTimeout) {
+			t.Fatalf("expected no connect timeout error, got: %s", err)
+		}
+	})
+
+	t.Run("connect_timeout", func(t *testing.T) {
+		// The listener accepts TCP connections but never completes the TLS handshake.
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer listener.Close()
+
+		go func() {
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				defer conn.Close()
+			}
+		}()
+
+		client := gohttpc.NewClient()
+		defer client.Close()
+
+		req := client.R(http.MethodGet, "https://"+listener.Addr().String())
+		req.SetConnectTimeout(100 * time.Millisecond)
+		req.SetTimeout(5 * time.Second)
+
+		start := time.Now()
+
+		resp, err := req.Execute(context.Background())
+		if err == nil {
+			resp.Body.Close()
+			t.Fatal("expected error, got nil")
+		}
+
+		if !errors.Is(err, gohttpc.ErrConnectTimeout) {
+			t.Fatalf("expected the connect timeout error, got: %s", err)
+		}
+
+		if elapsed := time.Since(start); elapsed >= 5*time.Second {
+			t.Fatalf("expected the request to fail before the total timeout, got: %s", elapsed)
+		}
+	})
+}
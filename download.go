@@ -0,0 +1,69 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/relychan/goutils"
+)
+
+// Download executes the request and streams the response body into the writer.
+// The progress callback is optional and invoked whenever a chunk of the body is written.
+// The response body isn't captured for debug logs and is closed when the download is done.
+func (r *Request) Download(
+	ctx context.Context,
+	client HTTPClientGetter,
+	w io.Writer,
+	progress ProgressFunc,
+) error {
+	r.streamResponse = true
+
+	resp, err := r.Execute(ctx, client)
+	if err != nil {
+		goutils.CloseResponse(resp)
+
+		return err
+	}
+
+	defer goutils.CloseResponse(resp)
+
+	if resp.Body == nil {
+		return nil
+	}
+
+	if progress != nil {
+		w = &progressWriter{
+			Writer:   w,
+			total:    resp.ContentLength,
+			progress: progress,
+		}
+	}
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// Download executes the request and streams the response body into the writer.
+// See [Request.Download] for more details.
+func (rwc *RequestWithClient) Download(
+	ctx context.Context,
+	w io.Writer,
+	progress ProgressFunc,
+) error {
+	return rwc.Request.Download(ctx, rwc.client, w, progress)
+}
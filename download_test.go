@@ -0,0 +1,106 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/gohttpc"
+)
+
+func TestRequest_Download(t *testing.T) {
+	const size = 4 << 20
+
+	payload := make([]byte, size)
+	_, _ = rand.Read(payload)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	// The debug logger would capture the response body if the download didn't bypass it.
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.WithValue(t.Context(), otelutils.LoggerContextKey, logger)
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	var (
+		progressCalls          int
+		lastWritten, lastTotal int64
+	)
+
+	req := client.R(http.MethodGet, server.URL)
+	req.Header().Set("Content-Type", "application/json")
+
+	var buf bytes.Buffer
+
+	err := req.Download(ctx, &buf, func(written, total int64) {
+		progressCalls++
+		lastWritten = written
+		lastTotal = total
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != size {
+		t.Errorf("expected %d bytes, got: %d", size, buf.Len())
+	}
+
+	if sha256.Sum256(buf.Bytes()) != sha256.Sum256(payload) {
+		t.Error("checksum mismatch")
+	}
+
+	if progressCalls < 2 || lastWritten != size || lastTotal != size {
+		t.Errorf("unexpected progress: calls=%d, written=%d, total=%d", progressCalls, lastWritten, lastTotal)
+	}
+
+	if logs.Len() >= size {
+		t.Errorf("expected the response body not captured in logs, got %d bytes of logs", logs.Len())
+	}
+
+	t.Run("returns error on failure status", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := client.R(http.MethodGet, server.URL+"/missing").Download(t.Context(), &buf, nil)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if buf.Len() != 0 {
+			t.Errorf("expected empty output, got %d bytes", buf.Len())
+		}
+	})
+}
@@ -15,10 +15,14 @@
 package gohttpc
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/relychan/goutils"
 	"github.com/relychan/goutils/httperror"
@@ -32,64 +36,228 @@ var (
 	ErrResponseBodyAlreadyRead = errors.New("response body was already read")
 	// ErrRequestMethodRequired occurs when the request method is null.
 	ErrRequestMethodRequired = errors.New("request method is required")
-	// ErrRequestAlreadyExecuted occurs when the request was already executed.
+	// ErrRequestAlreadyExecuted occurs when the request body was already sent by a previous execution.
 	ErrRequestAlreadyExecuted = errors.New("request was already executed")
+	// ErrUnsupportedChecksumAlgorithm occurs when the checksum algorithm of the request body is not supported.
+	ErrUnsupportedChecksumAlgorithm = errors.New("unsupported checksum algorithm")
+	// ErrResponseBodyTooSlow occurs when the throughput of the response body drops below the minimum rate.
+	ErrResponseBodyTooSlow = errors.New("response body throughput is below the minimum rate")
+	// ErrServerNameOverrideUnsupported occurs when the HTTP client can't override the TLS server name of the request.
+	ErrServerNameOverrideUnsupported = errors.New("the HTTP client does not support overriding the TLS server name")
+	// ErrPathParamMissing occurs when a placeholder in the request URL has no path parameter.
+	ErrPathParamMissing = errors.New("missing path parameter")
+	// ErrUnsupportedProxyScheme occurs when the scheme of the proxy URL is not supported.
+	ErrUnsupportedProxyScheme = errors.New("unsupported proxy scheme, expected one of http, https, socks5, socks5h")
+	// ErrResponseBodyTooLarge occurs when the response body exceeds the maximum size.
+	ErrResponseBodyTooLarge = errors.New("response body exceeds the maximum size")
+	// ErrCircuitBreakerOpen occurs when the request fails fast because the circuit breaker of the client is open.
+	ErrCircuitBreakerOpen = errors.New("circuit breaker of the client is open")
+	// ErrInvalidResponseSchema occurs when the JSON schema to validate response bodies is invalid.
+	ErrInvalidResponseSchema = errors.New("invalid response schema")
+	// ErrResponseSchemaValidation occurs when the response body does not conform to the JSON schema.
+	ErrResponseSchemaValidation = errors.New("response body does not conform to the schema")
+	// ErrRateLimiterBurstExceeded occurs when the burst of the rate limiter is zero so no request can be permitted.
+	ErrRateLimiterBurstExceeded = errors.New("rate limiter burst is exceeded")
+	// ErrConnectTimeout occurs when the connection isn't obtained within the connect timeout of the request.
+	ErrConnectTimeout = errors.New("connect timeout exceeded")
+	// ErrRequestCanceledByTag occurs when the in-flight request is canceled by [Client.CancelTag].
+	ErrRequestCanceledByTag = errors.New("request canceled by tag")
+	// ErrProxyHostRequired occurs when the proxy URL has no host.
+	ErrProxyHostRequired = errors.New("proxy host is required")
+	// ErrInvalidProxyURL occurs when the request is executed with an invalid proxy URL set by [Request.SetProxy].
+	ErrInvalidProxyURL = errors.New("invalid proxy URL")
+	// ErrTimeout occurs when the request isn't completed in time, e.g. the timeout or deadline of the request is exceeded.
+	ErrTimeout = errors.New("request timeout")
+	// ErrCircuitOpen is the alias of [ErrCircuitBreakerOpen].
+	ErrCircuitOpen = ErrCircuitBreakerOpen
+	// ErrTooManyRetries occurs when the request still fails after the retry policy or the retry budget is exhausted.
+	ErrTooManyRetries = errors.New("too many retries")
+	// ErrBodyTooLarge is the alias of [ErrResponseBodyTooLarge].
+	ErrBodyTooLarge = ErrResponseBodyTooLarge
+	// ErrBadRequest matches an [HTTPError] with the 400 Bad Request status.
+	ErrBadRequest = errors.New("bad request")
+	// ErrUnauthorized matches an [HTTPError] with the 401 Unauthorized status.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches an [HTTPError] with the 403 Forbidden status.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound matches an [HTTPError] with the 404 Not Found status.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict matches an [HTTPError] with the 409 Conflict status.
+	ErrConflict = errors.New("conflict")
+	// ErrTooManyRequests matches an [HTTPError] with the 429 Too Many Requests status.
+	ErrTooManyRequests = errors.New("too many requests")
+	// ErrInternalServerError matches an [HTTPError] with the 500 Internal Server Error status.
+	ErrInternalServerError = errors.New("internal server error")
+	// ErrBadGateway matches an [HTTPError] with the 502 Bad Gateway status.
+	ErrBadGateway = errors.New("bad gateway")
+	// ErrServiceUnavailable matches an [HTTPError] with the 503 Service Unavailable status.
+	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrGatewayTimeout matches an [HTTPError] with the 504 Gateway Timeout status.
+	ErrGatewayTimeout = errors.New("gateway timeout")
+	// ErrNegativeConfigValue occurs when a configuration field that must not be negative has a negative value.
+	ErrNegativeConfigValue = errors.New("must not be negative")
 )
 
-// httpErrorFromResponse creates an error from the HTTP response.
-func httpErrorFromResponse(resp *http.Response) *goutils.HTTPErrorWithExtensions {
-	if resp.Body == nil {
-		return httpErrorFromNoContentResponse(resp)
+// HTTPError is the error returned when the server responds with a status code of 400 or above.
+// It wraps the RFC 9457 problem details of the response,
+// so callers can inspect the error with [errors.As] for both *HTTPError and *goutils.HTTPErrorWithExtensions,
+// and match well-known statuses with [errors.Is], e.g. errors.Is(err, ErrNotFound).
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Status is the status line of the response, e.g. "404 Not Found".
+	Status string
+	// Body is the response body, captured up to [MaxCapturedErrorBodySize] or the capture limit of the request.
+	Body []byte
+	// Headers are the headers of the response.
+	Headers http.Header
+
+	problem *goutils.HTTPErrorWithExtensions
+}
+
+// NewHTTPError creates an [HTTPError] with the status code, e.g. to fail fast without sending the request.
+func NewHTTPError(statusCode int) *HTTPError {
+	return &HTTPError{
+		StatusCode: statusCode,
+		Status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		problem: &goutils.HTTPErrorWithExtensions{
+			HTTPError: *httperror.NewHTTPError(statusCode, ""),
+		},
 	}
+}
 
-	contentTypes := resp.Header[httpheader.ContentType]
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.problem == nil {
+		return e.Status
+	}
+
+	return e.problem.Error()
+}
+
+// Unwrap returns the RFC 9457 problem details of the response.
+func (e *HTTPError) Unwrap() error {
+	if e.problem == nil {
+		return nil
+	}
 
-	if len(contentTypes) > 0 && httpheader.IsContentTypeJSON(contentTypes[0]) {
-		var httpError goutils.HTTPErrorWithExtensions
+	return e.problem
+}
+
+// Is reports whether the target is the sentinel error of the status code, e.g. [ErrNotFound] for 404.
+func (e *HTTPError) Is(target error) bool {
+	sentinel, ok := statusErrors[e.StatusCode]
+
+	return ok && sentinel == target
+}
 
-		err := json.NewDecoder(resp.Body).Decode(&httpError)
+// Problem returns the RFC 9457 problem details of the response.
+func (e *HTTPError) Problem() *goutils.HTTPErrorWithExtensions {
+	return e.problem
+}
 
-		goutils.CloseResponse(resp)
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusConflict:            ErrConflict,
+	http.StatusTooManyRequests:     ErrTooManyRequests,
+	http.StatusInternalServerError: ErrInternalServerError,
+	http.StatusBadGateway:          ErrBadGateway,
+	http.StatusServiceUnavailable:  ErrServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrGatewayTimeout,
+}
+
+// MaxCapturedErrorBodySize is the maximum size of error response bodies which are captured by [WithCaptureErrorBody].
+const MaxCapturedErrorBodySize = 1 << 20
+
+// httpErrorFromResponse creates an error from the HTTP response and closes the body.
+func httpErrorFromResponse(resp *http.Response) *HTTPError {
+	return newHTTPErrorFromResponse(resp, MaxCapturedErrorBodySize, false)
+}
+
+// httpErrorFromResponseWithBody creates an error from the HTTP response and attaches the body to the error.
+// The body is read up to the limit and restored, so the caller can read it from the response.
+func httpErrorFromResponseWithBody(resp *http.Response, limit int64) *HTTPError {
+	return newHTTPErrorFromResponse(resp, limit, true)
+}
 
+func newHTTPErrorFromResponse(resp *http.Response, limit int64, captureBody bool) *HTTPError {
+	result := httpErrorFromNoContentResponse(resp)
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return result
+	}
+
+	rawBody, readErr := io.ReadAll(io.LimitReader(resp.Body, limit))
+
+	goutils.CatchWarnErrorFunc(resp.Body.Close)
+
+	result.Body = rawBody
+	contentTypes := resp.Header[httpheader.ContentType]
+
+	switch {
+	case readErr != nil:
+		result.problem.Extensions["read_error"] = readErr
+	case len(contentTypes) > 0 && httpheader.IsContentTypeJSON(contentTypes[0]):
+		var problem goutils.HTTPErrorWithExtensions
+
+		err := json.NewDecoder(bytes.NewReader(rawBody)).Decode(&problem)
 		if err != nil {
-			return httpErrorFromNoContentResponse(resp)
+			break
 		}
 
-		if httpError.Status == 0 {
-			httpError.Status = resp.StatusCode
+		if problem.Status == 0 {
+			problem.Status = resp.StatusCode
 		}
 
-		if httpError.Title == "" {
-			httpError.Title = resp.Status
+		if problem.Title == "" {
+			problem.Title = resp.Status
 		}
 
-		httpError.Extensions["headers"] = goutils.ExtractHeaders(resp.Header)
+		if problem.Extensions == nil {
+			problem.Extensions = map[string]any{}
+		}
 
-		return &httpError
+		problem.Extensions["headers"] = goutils.ExtractHeaders(resp.Header)
+		result.problem = &problem
+	default:
+		result.problem.Detail = string(rawBody)
 	}
 
-	result := httpErrorFromNoContentResponse(resp)
-	rawBody, readErr := io.ReadAll(resp.Body)
-
-	goutils.CatchWarnErrorFunc(resp.Body.Close)
-
-	if readErr == nil {
-		result.Detail = string(rawBody)
-	} else {
-		result.Extensions["read_error"] = readErr
+	if captureBody {
+		result.problem.Extensions["body"] = string(rawBody)
+		resp.Body = io.NopCloser(bytes.NewReader(rawBody))
 	}
 
 	return result
 }
 
-func httpErrorFromNoContentResponse(resp *http.Response) *goutils.HTTPErrorWithExtensions {
-	return &goutils.HTTPErrorWithExtensions{
-		HTTPError: httperror.HTTPError{
-			Status: resp.StatusCode,
-			Title:  resp.Status,
-		},
-		Extensions: map[string]any{
-			"headers": goutils.ExtractHeaders(resp.Header),
+func httpErrorFromNoContentResponse(resp *http.Response) *HTTPError {
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		problem: &goutils.HTTPErrorWithExtensions{
+			HTTPError: httperror.HTTPError{
+				Status: resp.StatusCode,
+				Title:  resp.Status,
+			},
+			Extensions: map[string]any{
+				"headers": goutils.ExtractHeaders(resp.Header),
+			},
 		},
 	}
 }
+
+// isTimeoutError checks if the error is caused by a timeout of the request or the connection.
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrConnectTimeout) {
+		return true
+	}
+
+	netErr, ok := errors.AsType[net.Error](err)
+
+	return ok && netErr.Timeout()
+}
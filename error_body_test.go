@@ -0,0 +1,120 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestCaptureErrorBody(t *testing.T) {
+	const body = `{"code":"invalid_input","message":"name is required"}`
+
+	// The body is chunked, so it's limited while reading instead of by the content length.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Options  []gohttpc.ClientOption
+		Expected string
+	}{
+		{
+			Name:     "enabled",
+			Options:  []gohttpc.ClientOption{gohttpc.WithCaptureErrorBody(true)},
+			Expected: body,
+		},
+		{
+			Name: "size_limited",
+			Options: []gohttpc.ClientOption{
+				gohttpc.WithCaptureErrorBody(true),
+				gohttpc.WithMaxResponseBodySize(10),
+			},
+			Expected: body[:10],
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			client := gohttpc.NewClient(tc.Options...)
+			defer goutils.CatchWarnErrorFunc(client.Close)
+
+			resp, err := client.R(http.MethodPost, server.URL).Execute(context.Background())
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			httpError, ok := errors.AsType[*goutils.HTTPErrorWithExtensions](err)
+			if !ok {
+				t.Fatalf("expected HTTP error, got: %T", err)
+			}
+
+			if httpError.Extensions["body"] != tc.Expected {
+				t.Errorf("expected the error body %q, got: %v", tc.Expected, httpError.Extensions["body"])
+			}
+
+			if resp == nil {
+				t.Fatal("expected the error response, got nil")
+			}
+			defer goutils.CloseResponse(resp)
+
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(respBody) != tc.Expected {
+				t.Errorf("expected the response body %q, got: %q", tc.Expected, string(respBody))
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		client := gohttpc.NewClient()
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodPost, server.URL).Execute(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		httpError, ok := errors.AsType[*goutils.HTTPErrorWithExtensions](err)
+		if !ok {
+			t.Fatalf("expected HTTP error, got: %T", err)
+		}
+
+		if _, ok := httpError.Extensions["body"]; ok {
+			t.Error("expected no captured body in the error")
+		}
+
+		if httpError.Detail != body {
+			t.Errorf("expected the error detail %q, got: %q", body, httpError.Detail)
+		}
+
+		goutils.CloseResponse(resp)
+	})
+}
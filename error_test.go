@@ -15,13 +15,19 @@
 package gohttpc_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
 	"github.com/relychan/goutils"
 )
 
@@ -42,7 +48,7 @@ func assertHTTPError(t *testing.T, err error, wantStatus int) *goutils.HTTPError
 		t.Fatal("expected an error, got nil")
 	}
 
-	httpErr, ok := err.(*goutils.HTTPErrorWithExtensions)
+	httpErr, ok := errors.AsType[*goutils.HTTPErrorWithExtensions](err)
 	if !ok {
 		t.Fatalf("expected *RFC9457ErrorWithExtensions, got %T: %v", err, err)
 	}
@@ -246,3 +252,185 @@ func TestHTTPErrorFromResponse_ErrorString(t *testing.T) {
 		t.Errorf("expected error string to contain 'token expired', got: %s", errStr)
 	}
 }
+
+func TestHTTPError_Statuses(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Status      int
+		ContentType string
+		Body        string
+		Sentinel    error
+	}{
+		{
+			Name:        "not_found",
+			Status:      http.StatusNotFound,
+			ContentType: "text/plain",
+			Body:        "no such item",
+			Sentinel:    gohttpc.ErrNotFound,
+		},
+		{
+			Name:        "service_unavailable",
+			Status:      http.StatusServiceUnavailable,
+			ContentType: "application/json",
+			Body:        `{"status":503,"title":"Service Unavailable","detail":"maintenance"}`,
+			Sentinel:    gohttpc.ErrServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.ContentType)
+				w.Header().Set("X-Request-Id", "abc")
+				w.WriteHeader(tc.Status)
+				_, _ = w.Write([]byte(tc.Body))
+			}))
+			defer server.Close()
+
+			_, err := executeGet(t, server.URL+"/")
+
+			var httpErr *gohttpc.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected *gohttpc.HTTPError, got %T: %v", err, err)
+			}
+
+			if httpErr.StatusCode != tc.Status {
+				t.Errorf("expected status code %d, got %d", tc.Status, httpErr.StatusCode)
+			}
+
+			if httpErr.Status != fmt.Sprintf("%d %s", tc.Status, http.StatusText(tc.Status)) {
+				t.Errorf("unexpected status: %s", httpErr.Status)
+			}
+
+			if string(httpErr.Body) != tc.Body {
+				t.Errorf("expected body %q, got %q", tc.Body, httpErr.Body)
+			}
+
+			if httpErr.Headers.Get("X-Request-Id") != "abc" {
+				t.Errorf("expected X-Request-Id header, got %v", httpErr.Headers)
+			}
+
+			if !errors.Is(err, tc.Sentinel) {
+				t.Errorf("expected errors.Is(err, %v) to be true", tc.Sentinel)
+			}
+
+			if errors.Is(err, gohttpc.ErrBadRequest) {
+				t.Error("expected errors.Is(err, ErrBadRequest) to be false")
+			}
+
+			assertHTTPError(t, err, tc.Status)
+		})
+	}
+}
+
+func TestNewHTTPError(t *testing.T) {
+	err := gohttpc.NewHTTPError(http.StatusBadGateway)
+
+	if err.StatusCode != http.StatusBadGateway || err.Status != "502 Bad Gateway" {
+		t.Errorf("unexpected status: %d %s", err.StatusCode, err.Status)
+	}
+
+	if !errors.Is(err, gohttpc.ErrBadGateway) {
+		t.Error("expected errors.Is(err, ErrBadGateway) to be true")
+	}
+
+	if err.Problem().Status != http.StatusBadGateway {
+		t.Errorf("expected problem status 502, got %d", err.Problem().Status)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	send := func(t *testing.T, client *gohttpc.Client, path string) error {
+		t.Helper()
+
+		resp, err := client.R(http.MethodGet, server.URL+path).Execute(t.Context())
+		if resp != nil {
+			if err == nil {
+				_, err = io.ReadAll(resp.Body)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+
+		return err
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		client := gohttpc.NewClient(gohttpc.WithTimeout(50 * time.Millisecond))
+
+		err := send(t, client, "/slow")
+		if !errors.Is(err, gohttpc.ErrTimeout) {
+			t.Errorf("expected ErrTimeout, got %v", err)
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the cause to be kept, got %v", err)
+		}
+	})
+
+	t.Run("circuit_open", func(t *testing.T) {
+		client := gohttpc.NewClient(gohttpc.WithCircuitBreaker(gohttpc.CircuitBreakerConfig{
+			Name:             "sentinel",
+			FailureThreshold: 1,
+			Delay:            time.Minute,
+		}))
+
+		_ = send(t, client, "/fail")
+
+		err := send(t, client, "/fail")
+		if !errors.Is(err, gohttpc.ErrCircuitOpen) {
+			t.Errorf("expected ErrCircuitOpen, got %v", err)
+		}
+
+		if errors.Is(err, gohttpc.ErrTooManyRetries) {
+			t.Errorf("expected no ErrTooManyRetries, got %v", err)
+		}
+	})
+
+	t.Run("too_many_retries", func(t *testing.T) {
+		delay := int64(1)
+
+		retry, err := httpconfig.HTTPRetryConfig{
+			MaxAttempts: 2,
+			Delay:       &delay,
+		}.ToRetryPolicy() //nolint:bodyclose
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := gohttpc.NewClient(gohttpc.WithRetry(retry))
+
+		err = send(t, client, "/fail")
+		if !errors.Is(err, gohttpc.ErrTooManyRetries) {
+			t.Errorf("expected ErrTooManyRetries, got %v", err)
+		}
+
+		if !errors.Is(err, gohttpc.ErrServiceUnavailable) {
+			t.Errorf("expected the last HTTP error to be kept, got %v", err)
+		}
+	})
+
+	t.Run("body_too_large", func(t *testing.T) {
+		client := gohttpc.NewClient(gohttpc.WithMaxResponseBodySize(10))
+
+		err := send(t, client, "/large")
+		if !errors.Is(err, gohttpc.ErrBodyTooLarge) {
+			t.Errorf("expected ErrBodyTooLarge, got %v", err)
+		}
+	})
+}
@@ -94,9 +94,10 @@ func getTodo(client *loadbalancer.LoadBalancerClient, id int) {
 	ctx, span := tracer.Start(context.Background(), "getTodo")
 	defer span.End()
 
-	endpoint := "/todos/" + strconv.Itoa(id)
+	req := client.R(http.MethodGet, "/todos/{id}")
+	req.SetPathParam("id", strconv.Itoa(id))
 
-	resp, err := client.R(http.MethodGet, endpoint).Execute(ctx)
+	resp, err := req.Execute(ctx)
 	if err != nil {
 		slog.Error(err.Error())
 	}
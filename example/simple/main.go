@@ -73,9 +73,10 @@ func getTodo(client *gohttpc.Client, id int) {
 	ctx, span := tracer.Start(context.Background(), "getTodo")
 	defer span.End()
 
-	endpoint := "https://jsonplaceholder.typicode.com/todos/" + strconv.Itoa(id)
+	req := client.R(http.MethodGet, "https://jsonplaceholder.typicode.com/todos/{id}")
+	req.SetPathParam("id", strconv.Itoa(id))
 
-	resp, err := client.R(http.MethodGet, endpoint).Execute(ctx)
+	resp, err := req.Execute(ctx)
 	if err != nil {
 		panic(err)
 	}
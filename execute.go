@@ -17,6 +17,8 @@ package gohttpc
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"maps"
@@ -24,23 +26,46 @@ import (
 	"net/url"
 	"slices"
 	"time"
+	"unicode/utf8"
 
 	"github.com/failsafe-go/failsafe-go"
+	"github.com/failsafe-go/failsafe-go/retrypolicy"
 	"github.com/hasura/gotel/otelutils"
-	"github.com/relychan/gocompress"
+	"github.com/relychan/gohttpc/authc/authscheme"
 	"github.com/relychan/goutils"
 	"github.com/relychan/goutils/httpheader"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
-	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// Execute handles the HTTP request to the remote server.
-func (r *Request) Execute( //nolint:funlen
+// Execute handles the HTTP request to the remote server and returns the raw HTTP response.
+// The execution is wrapped by execute middlewares of the client if configured.
+// Use [Request.Send] to get the [Response] wrapper with helper methods.
+func (r *Request) Execute(
+	ctx context.Context,
+	client HTTPClientGetter,
+) (*http.Response, error) {
+	middlewares := r.options.ExecuteMiddlewares
+	if len(middlewares) == 0 {
+		return r.execute(ctx, client)
+	}
+
+	next := ExecuteFunc(func(ctx context.Context, req *Request) (*http.Response, error) {
+		return req.execute(ctx, client)
+	})
+
+	// The first middleware is the outermost.
+	for _, middleware := range slices.Backward(middlewares) {
+		next = middleware(next)
+	}
+
+	return next(ctx, r)
+}
+
+func (r *Request) execute( //nolint:funlen
 	ctx context.Context,
 	client HTTPClientGetter,
 ) (*http.Response, error) {
@@ -48,16 +73,31 @@ func (r *Request) Execute( //nolint:funlen
 		return nil, ErrRequestMethodRequired
 	}
 
+	if r.proxyErr != nil {
+		return nil, r.proxyErr
+	}
+
+	// The body of the request can be read once only.
+	// Callers must set a new body, e.g. on a clone of the request, to send it again.
+	if r.consumed {
+		return nil, ErrRequestAlreadyExecuted
+	}
+
+	if cr, ok := r.body.(*channelReader); ok {
+		cr.ctx = ctx
+	}
+
 	r.retryAttempts = 0
 	startTime := time.Now()
 	logger := r.getLogger(ctx)
 	isDebug := logger.Enabled(ctx, slog.LevelDebug)
+	r.captureBody = isDebug && r.options.bodyCaptureSampler.sample()
 
 	var requestBodyStr string
 
 	contentTypes := r.Header()[httpheader.ContentType]
 
-	if isDebug && r.body != nil &&
+	if r.captureBody && r.body != nil && !r.streaming &&
 		len(contentTypes) > 0 &&
 		otelutils.IsContentTypeDebuggable(contentTypes[0]) {
 		body, err := io.ReadAll(r.body)
@@ -80,14 +120,16 @@ func (r *Request) Execute( //nolint:funlen
 		r.body = bytes.NewReader(body)
 	}
 
-	endpoint, err := goutils.ParsePathOrHTTPURL(r.url)
+	r.requestURL = r.url
+
+	endpoint, err := r.parseRequestURL()
 	if err != nil {
 		logger.Error(
 			"invalid request url: "+err.Error(),
 			slog.GroupAttrs(
 				"request",
 				slog.String("method", r.method),
-				slog.String("url", r.url),
+				slog.String("url", r.requestURL),
 			),
 			slog.Float64("latency", time.Since(startTime).Seconds()),
 		)
@@ -95,6 +137,9 @@ func (r *Request) Execute( //nolint:funlen
 		return nil, err
 	}
 
+	// The request passed the validation and will be dispatched, so the body is consumed from here.
+	r.consumed = r.body != nil
+
 	spanContext, span := clientTracer.Start(
 		ctx,
 		"Request",
@@ -103,6 +148,37 @@ func (r *Request) Execute( //nolint:funlen
 
 	defer span.End()
 
+	// The custom attributes are evaluated once and shared by all attempts of the request.
+	r.customAttributes = nil
+
+	if r.options.CustomAttributesFunc != nil {
+		r.customAttributes = r.options.CustomAttributesFunc(r)
+	}
+
+	if len(r.spanAttributes) > 0 {
+		span.SetAttributes(r.spanAttributes...)
+	}
+
+	if r.options.BodyHashAttribute && r.body != nil {
+		hash, err := bodyHash(r.body)
+		if err != nil {
+			return nil, r.logExecution(
+				ctx,
+				logger,
+				span,
+				endpoint,
+				nil,
+				requestBodyStr,
+				startTime,
+				err,
+			)
+		}
+
+		if hash != "" {
+			span.SetAttributes(attribute.String("http.request.body.hash", hash))
+		}
+	}
+
 	body, err := r.compressBody(logger)
 	if err != nil {
 		return nil, r.logExecution(
@@ -117,11 +193,25 @@ func (r *Request) Execute( //nolint:funlen
 		)
 	}
 
+	body, err = r.applyBodyChecksum(body)
+	if err != nil {
+		return nil, r.logExecution(
+			ctx,
+			logger,
+			span,
+			endpoint,
+			nil,
+			requestBodyStr,
+			startTime,
+			err,
+		)
+	}
+
 	var resp *http.Response
 
 	var cancel context.CancelFunc
 
-	timeout := r.getTimeout()
+	timeout := r.effectiveTimeout(spanContext)
 	if timeout > 0 {
 		span.SetAttributes(attribute.String("http.request.timeout", timeout.String()))
 		// The cancel function will be wrapped in the response body.
@@ -129,12 +219,71 @@ func (r *Request) Execute( //nolint:funlen
 		spanContext, cancel = context.WithTimeout(spanContext, timeout)
 	}
 
+	if r.cancelTag != "" {
+		var cancelTag context.CancelFunc
+
+		span.SetAttributes(attribute.String("http.request.cancel_tag", r.cancelTag))
+
+		spanContext, cancelTag = r.withCancelTag(spanContext)
+		if cancelTag != nil {
+			cancel = chainCancelFuncs(cancelTag, cancel)
+		}
+	}
+
+	r.idempotencyKey = r.newIdempotencyKey()
+
+	spanContext = contextWithHTTPClientMetrics(spanContext, r.options.Metrics)
+
+	if r.cost > 1 {
+		spanContext = WithRequestCost(spanContext, r.cost)
+	}
+
+	if r.balancerHint != "" {
+		spanContext = WithBalancerHint(spanContext, r.balancerHint)
+	}
+
+	if r.authDisabled {
+		spanContext = WithAuthDisabled(spanContext)
+	}
+
+	if r.proxyURL != nil {
+		spanContext = context.WithValue(spanContext, proxyURLContextKey{}, r.proxyURL)
+	}
+
 	if r.getRetryPolicy() == nil {
-		resp, err = r.doRequest(spanContext, client, endpoint, body, logger)
+		resp, err = r.doRequestWithCircuitBreaker(spanContext, client, endpoint, body, logger)
 	} else {
 		resp, err = r.executeWithRetries(spanContext, client, endpoint, body, logger)
 	}
 
+	if err != nil && !errors.Is(err, ErrRequestCanceledByTag) {
+		if cause := context.Cause(spanContext); errors.Is(cause, ErrRequestCanceledByTag) {
+			err = fmt.Errorf("%w: %w", cause, err)
+		}
+	}
+
+	if err != nil && !errors.Is(err, ErrTimeout) && isTimeoutError(err) {
+		err = fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+
+	if err == nil && r.options.RetryBudget != nil {
+		r.options.RetryBudget.Deposit()
+	}
+
+	if err == nil && !r.streamResponse {
+		if schema := r.ResponseSchema(); schema != nil {
+			err = schema.validateResponse(resp)
+		}
+	}
+
+	if r.options.ResponseDeadlinePerByte > 0 && resp != nil && resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = newThroughputBody(
+			resp.Body,
+			r.options.ResponseDeadlinePerByte,
+			r.options.ResponseReadGracePeriod,
+		)
+	}
+
 	if cancel != nil {
 		if resp != nil && resp.Body != nil {
 			resp.Body = &responseBodyWithCancel{
@@ -146,7 +295,7 @@ func (r *Request) Execute( //nolint:funlen
 		}
 	}
 
-	return resp, r.logExecution(
+	err = r.logExecution(
 		ctx,
 		logger,
 		span,
@@ -156,6 +305,83 @@ func (r *Request) Execute( //nolint:funlen
 		startTime,
 		err,
 	)
+	if r.options.FallbackResponse != nil && r.isFallbackFailure(ctx, err) {
+		resp, err = r.fallbackResponse(span, resp, err)
+	}
+
+	if r.options.OnBodyLeak != nil && resp != nil && resp.Body != nil && resp.Body != http.NoBody {
+		resp = newLeakDetectorResponse(resp, r.options.OnBodyLeak)
+	}
+
+	return resp, err
+}
+
+// parseRequestURL substitutes path parameters and parses the request URL.
+func (r *Request) parseRequestURL() (*url.URL, error) {
+	requestURL, err := r.resolveURL()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.options.BaseURL != "" {
+		requestURL = JoinURL(r.options.BaseURL, requestURL)
+	}
+
+	r.requestURL = requestURL
+
+	return goutils.ParsePathOrHTTPURL(requestURL)
+}
+
+// isFallbackFailure checks if the request failed after using up its retry or host attempts,
+// so the fallback response may replace the failure.
+// The cancellation of the caller and non-retryable failures, e.g. 4xx statuses, are returned as is.
+func (r *Request) isFallbackFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ErrRequestCanceledByTag) {
+		return false
+	}
+
+	if errors.Is(err, ErrTooManyRetries) || errors.Is(err, ErrCircuitBreakerOpen) || errors.Is(err, ErrTimeout) {
+		return true
+	}
+
+	// The retry policy stopped before exhausting attempts, so the failure isn't retryable.
+	if r.getRetryPolicy() != nil {
+		return false
+	}
+
+	// The single attempt is exhausted if it failed without a response or with a server failure status.
+	httpErr, ok := errors.AsType[*HTTPError](err)
+	if ok {
+		return httpErr.StatusCode >= http.StatusInternalServerError ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// fallbackResponse calls the fallback function to replace the failed response.
+func (r *Request) fallbackResponse(
+	span trace.Span,
+	resp *http.Response,
+	err error,
+) (*http.Response, error) {
+	fallbackResp, fallbackErr := r.options.FallbackResponse(r, err)
+	if fallbackResp == nil {
+		// A nil response without error means no fallback, keep the original failure.
+		if fallbackErr == nil {
+			return resp, err
+		}
+
+		return resp, fallbackErr
+	}
+
+	if resp != nil && resp != fallbackResp {
+		goutils.CloseResponse(resp)
+	}
+
+	span.SetAttributes(attribute.Bool("http.response.fallback", true))
+
+	return fallbackResp, fallbackErr
 }
 
 func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
@@ -172,29 +398,23 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 		requestHeaders, responseHeaders [][]string
 		requestSize, responseSize       int
 		requestURL                      string
-		requestDurationAttrs            []attribute.KeyValue
 	)
 
-	if r.options.CustomAttributesFunc != nil {
-		requestDurationAttrs = r.options.CustomAttributesFunc(r)
-	}
-
-	requestDurationAttrs = slices.Grow(requestDurationAttrs, 6)
+	requestDurationAttrs := make([]attribute.KeyValue, 0, len(r.customAttributes)+6)
+	requestDurationAttrs = append(requestDurationAttrs, r.customAttributes...)
 
 	if resp != nil {
 		if r.options.IsTraceRequestHeadersEnabled() {
-			requestHeaders = otelutils.ExtractTelemetryHeaders(
+			requestHeaders = r.options.extractTelemetryHeaders(
 				resp.Request.Header,
-				nil,
 				r.options.AllowedTraceRequestHeaders...,
 			)
 			otelutils.SetSpanHeaderMatrixAttributes(span, "http.request.header", requestHeaders)
 		}
 
 		if r.options.IsTraceResponseHeadersEnabled() {
-			responseHeaders = otelutils.ExtractTelemetryHeaders(
+			responseHeaders = r.options.extractTelemetryHeaders(
 				resp.Header,
-				nil,
 				r.options.AllowedTraceResponseHeaders...,
 			)
 			otelutils.SetSpanHeaderMatrixAttributes(span, "http.response.header", responseHeaders)
@@ -218,7 +438,7 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 			newNetworkProtocolVersion(resp.ProtoMajor, resp.ProtoMinor),
 		)
 	} else {
-		requestURL = r.url
+		requestURL = r.requestURL
 	}
 
 	span.SetAttributes(semconv.URLPath(requestURL))
@@ -249,14 +469,13 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 		)
 	}
 
-	GetHTTPClientMetrics().RequestDuration.Record(
-		ctx,
+	// The context of the request span lets the SDK attach the trace as an exemplar.
+	r.options.getMetrics().RequestDuration.Record(
+		trace.ContextWithSpan(ctx, span),
 		time.Since(startTime).Seconds(),
-		metric.WithAttributeSet(attribute.NewSet(requestDurationAttrs...)),
+		metric.WithAttributeSet(r.options.metricAttributeSet(requestDurationAttrs...)),
 	)
 
-	isDebug := logger.Enabled(ctx, slog.LevelDebug)
-
 	canPrintLog := logger.Enabled(ctx, r.options.LogLevel)
 	if !canPrintLog && err == nil {
 		span.SetStatus(codes.Ok, "")
@@ -276,12 +495,14 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 	}
 
 	if reqBody != "" {
+		logBody := truncateLogBody(reqBody, r.options.MaxLogBodySize)
+
 		requestLogAttrs = append(
 			requestLogAttrs,
-			slog.String("body", reqBody),
+			slog.String("body", logBody),
 		)
 
-		span.SetAttributes(attribute.String("http.request.body", reqBody))
+		span.SetAttributes(attribute.String("http.request.body", logBody))
 	}
 
 	if len(requestHeaders) > 0 {
@@ -314,7 +535,7 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 
 		span.SetAttributes(statusCodeAttr)
 
-		if resp.Body != nil && isDebug &&
+		if resp.Body != nil && r.captureBody && !r.streamResponse &&
 			len(contentTypes) > 0 &&
 			otelutils.IsContentTypeDebuggable(contentTypes[0]) {
 			body, readErr := io.ReadAll(resp.Body)
@@ -336,7 +557,7 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 				return readErr
 			}
 
-			respBodyString := string(body)
+			respBodyString := truncateLogBody(string(body), r.options.MaxLogBodySize)
 			responseLogAttrs = append(
 				responseLogAttrs,
 				slog.String("body", respBodyString),
@@ -345,7 +566,7 @@ func (r *Request) logExecution( //nolint:gocognit,funlen,maintidx,cyclop
 			span.SetAttributes(attribute.String("http.response.body", respBodyString))
 
 			if responseSize <= 0 {
-				responseSize = len(respBodyString)
+				responseSize = len(body)
 				span.SetAttributes(semconv.HTTPResponseBodySize(responseSize))
 			}
 
@@ -416,12 +637,60 @@ func (r *Request) executeWithRetries(
 		}
 	}
 
+	retryPolicy := r.getRetryPolicy()
+	executor := failsafe.With(retryPolicy)
+	retryBudget := r.options.RetryBudget
+	budget := failureBudget{
+		maxAttemptsOnError:  r.options.MaxAttemptsOnError,
+		maxAttemptsOnStatus: r.options.MaxAttemptsOnStatus,
+	}
+
+	if classifier, ok := retryPolicy.(failureClassifier); ok {
+		budget.isFailure = classifier.IsFailure
+	}
+
+	if budget.isEnabled() || retryBudget != nil || r.options.CircuitBreaker != nil {
+		// The execution is canceled to stop retrying when a budget is exhausted.
+		execCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		budget.cancel = cancel
+		executor = executor.WithContext(execCtx)
+	}
+
+	var (
+		lastResp *http.Response
+		lastErr  error
+		attempts int
+	)
+
 	operation := func() (*http.Response, error) {
+		if attempts > 0 && retryBudget != nil && !retryBudget.TryWithdraw() {
+			r.options.getMetrics().SuppressedRetries.Add(
+				ctx,
+				1,
+				metric.WithAttributeSet(r.options.metricAttributeSet(
+					httpRequestMethodAttr(r.method),
+					semconv.ServerAddress(endpoint.Host),
+				)),
+			)
+
+			budget.suppress(lastResp, lastErr)
+
+			return lastResp, lastErr
+		}
+
+		attempts++
+
+		if attempts > 1 {
+			addRetrySpanEvent(ctx, attempts, lastResp, lastErr)
+		}
+
 		if bodySeeker != nil {
 			_, _ = bodySeeker.Seek(0, io.SeekStart)
 		}
 
-		resp, err := r.doRequest(
+		resp, err := r.doRequestWithCircuitBreaker(
 			ctx,
 			client,
 			endpoint,
@@ -432,10 +701,164 @@ func (r *Request) executeWithRetries(
 			r.retryAttempts++
 		}
 
+		if errors.Is(err, ErrCircuitBreakerOpen) {
+			// Retrying is pointless until the circuit breaker permits requests again.
+			budget.suppress(nil, err)
+
+			return nil, err
+		}
+
+		budget.observe(resp, err)
+
+		lastResp = resp
+		lastErr = err
+
 		return resp, err
 	}
 
-	return failsafe.With(r.getRetryPolicy()).Get(operation)
+	resp, err := executor.Get(operation)
+	if budget.exhausted {
+		resp, err = budget.lastResponse, budget.lastError
+	}
+
+	if budget.exhausted || retrypolicy.IsExceededError(err) {
+		if r.options.OnRetryGiveUp != nil {
+			r.options.OnRetryGiveUp(r, lastResp, lastErr)
+		}
+
+		// The circuit breaker rejects the request without counting it as a retry.
+		if err != nil && !errors.Is(err, ErrCircuitBreakerOpen) {
+			err = fmt.Errorf("%w: %w", ErrTooManyRetries, err)
+		}
+	}
+
+	return resp, err
+}
+
+// addRetrySpanEvent adds the retry event with the attempt number and the result of the previous attempt
+// to the active span.
+func addRetrySpanEvent(ctx context.Context, attempt int, lastResp *http.Response, lastErr error) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	attrs = append(attrs, attribute.Int("http.request.attempt", attempt))
+
+	if lastResp != nil {
+		attrs = append(attrs, semconv.HTTPResponseStatusCode(lastResp.StatusCode))
+	}
+
+	if lastErr != nil {
+		attrs = append(attrs, attribute.String("exception.message", lastErr.Error()))
+	}
+
+	span.AddEvent("retry", trace.WithAttributes(attrs...))
+}
+
+// failureClassifier is implemented by retry policies of failsafe-go
+// to check if the result of an attempt is handled as a failure which is retried.
+type failureClassifier interface {
+	IsFailure(resp *http.Response, err error) bool
+}
+
+// failureBudget limits the number of failed attempts by the failure kind within a single execution.
+type failureBudget struct {
+	// isFailure checks if the retry policy retries the result. Only retried failures are counted.
+	isFailure           func(resp *http.Response, err error) bool
+	maxAttemptsOnError  int
+	maxAttemptsOnStatus int
+	errorAttempts       int
+	statusAttempts      int
+	exhausted           bool
+	lastResponse        *http.Response
+	lastError           error
+	cancel              context.CancelFunc
+}
+
+func (rb *failureBudget) isEnabled() bool {
+	return rb.maxAttemptsOnError > 0 || rb.maxAttemptsOnStatus > 0
+}
+
+// observe counts the failure of the attempt and cancels the execution if the budget of the failure kind is exhausted.
+func (rb *failureBudget) observe(resp *http.Response, err error) {
+	if rb.cancel == nil || (rb.isFailure != nil && !rb.isFailure(resp, err)) {
+		return
+	}
+
+	switch {
+	case resp != nil && resp.StatusCode >= http.StatusBadRequest:
+		rb.statusAttempts++
+		rb.exhausted = rb.maxAttemptsOnStatus > 0 && rb.statusAttempts >= rb.maxAttemptsOnStatus
+	case err != nil:
+		rb.errorAttempts++
+		rb.exhausted = rb.maxAttemptsOnError > 0 && rb.errorAttempts >= rb.maxAttemptsOnError
+	default:
+		return
+	}
+
+	if rb.exhausted {
+		rb.suppress(resp, err)
+	}
+}
+
+// suppress stops retrying and keeps the result of the last attempt.
+func (rb *failureBudget) suppress(resp *http.Response, err error) {
+	rb.exhausted = true
+	rb.lastResponse = resp
+	rb.lastError = err
+
+	rb.cancel()
+}
+
+// send sends the HTTP request, overriding the TLS server name if set.
+func (r *Request) send(client HTTPClient, req *http.Request) (*http.Response, error) {
+	if r.serverName == "" {
+		return client.Do(req)
+	}
+
+	return r.doWithServerName(client, req)
+}
+
+// answerAuthChallenge sends the request once again if the authenticator accepts the challenge
+// of the 401 response, e.g. when the nonce of the digest scheme is stale.
+// Requests with a body which can't be rewound are not sent again.
+func (r *Request) answerAuthChallenge(
+	client HTTPClient,
+	req *http.Request,
+	resp *http.Response,
+) (*http.Response, error) {
+	if r.authDisabled || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	authenticator, _ := r.resolveAuthenticator(client)
+
+	handler, ok := authenticator.(authscheme.HTTPClientChallengeHandler)
+	if !ok || !handler.HandleChallenge(resp) {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil //nolint:nilerr
+		}
+
+		retryReq.Body = body
+	}
+
+	goutils.CloseResponse(resp)
+
+	err := authenticator.Authenticate(retryReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.send(client, retryReq)
 }
 
 func (r *Request) compressBody(logger *slog.Logger) (io.Reader, error) {
@@ -453,7 +876,7 @@ func (r *Request) compressBody(logger *slog.Logger) (io.Reader, error) {
 	}
 
 	// should ignore the compression if the encoding isn't supported.
-	formats, err := gocompress.DefaultCompressor.ParseSupportedEncoding(encoding[0])
+	formats, err := parseContentEncoding(encoding[0])
 	if err != nil {
 		logger.Warn(err.Error())
 	}
@@ -464,9 +887,27 @@ func (r *Request) compressBody(logger *slog.Logger) (io.Reader, error) {
 		return body, nil
 	}
 
+	if r.options.CompressionMinSize > 0 {
+		// Peek the body to skip the compression if the body is smaller than the threshold.
+		peekBuf := new(bytes.Buffer)
+
+		size, err := io.CopyN(peekBuf, body, int64(r.options.CompressionMinSize))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		if size < int64(r.options.CompressionMinSize) {
+			r.Header().Del(httpheader.ContentEncoding)
+
+			return peekBuf, nil
+		}
+
+		body = io.MultiReader(peekBuf, body)
+	}
+
 	var buf bytes.Buffer
 
-	_, err = gocompress.DefaultCompressor.CompressFormat(&buf, body, formats...)
+	err = compressContent(&buf, body, formats)
 	if err != nil {
 		return nil, err
 	}
@@ -488,13 +929,9 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 
 	var span HTTPClientTracer
 
-	spanName := r.method
-
-	if r.options.TraceHighCardinalityPath {
-		spanName += " " + endpoint.Path
-	}
+	spanName := r.spanName(endpoint)
 
-	if r.options.ClientTraceEnabled {
+	if r.ClientTraceEnabled() {
 		ctx, span = startClientTrace(
 			ctx,
 			spanName,
@@ -511,13 +948,28 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 		span.SetAttributes(semconv.HTTPRequestResendCount(r.retryAttempts))
 	}
 
-	req, err := client.NewRequest(ctx, r.method, r.url, body)
+	reqCtx := ctx
+
+	var connTimer *connectTimer
+
+	if r.connectTimeout > 0 {
+		reqCtx, connTimer = newConnectTimer(ctx, r.connectTimeout)
+
+		// The timer is released when the response body is closed.
+		defer func() {
+			if connTimer != nil {
+				connTimer.release()
+			}
+		}()
+	}
+
+	req, err := client.NewRequest(reqCtx, r.method, r.requestURL, body)
 	if err != nil {
 		msg := "failed to create request"
 
 		span.SetAttributes(
 			httpRequestMethodAttr(r.method),
-			semconv.URLFull(r.url),
+			semconv.URLFull(r.requestURL),
 		)
 
 		span.SetStatus(codes.Error, msg)
@@ -538,21 +990,16 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 
 	_, port, _ := otelutils.SplitHostPort(req.URL.Host, req.URL.Scheme)
 
-	var commonAttrs []attribute.KeyValue
-
-	if r.options.CustomAttributesFunc != nil {
-		commonAttrs = r.options.CustomAttributesFunc(r)
-	}
-
-	commonAttrs = slices.Grow(commonAttrs, 8)
+	commonAttrs := make([]attribute.KeyValue, 0, len(r.customAttributes)+8)
+	commonAttrs = append(commonAttrs, r.customAttributes...)
 	commonAttrs = addRequestMetricAttributes(commonAttrs, r.method, req.URL, port)
 
 	span.SetAttributes(commonAttrs...)
 	span.SetAttributes(semconv.URLFull(req.URL.String()))
 
-	activeRequestsAttrSet := metric.WithAttributeSet(attribute.NewSet(commonAttrs...))
+	activeRequestsAttrSet := metric.WithAttributeSet(r.options.metricAttributeSet(commonAttrs...))
 
-	metrics := GetHTTPClientMetrics()
+	metrics := r.options.getMetrics()
 
 	metrics.ActiveRequests.Add(
 		ctx,
@@ -579,10 +1026,61 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 	commonAttrs = append(commonAttrs, protocolVersionAttr)
 
 	span.SetAttributes(protocolVersionAttr)
-	span.SetMetricAttributes(commonAttrs)
+	span.SetMetricAttributes(r.options.filterMetricAttributes(commonAttrs))
 	maps.Copy(req.Header, r.header)
 
-	err = r.applyAuth(req)
+	if r.idempotencyKey != "" {
+		req.Header.Set(r.options.IdempotencyKeyHeader, r.idempotencyKey)
+	}
+
+	if r.options.AcceptEncoding != "" && req.Header.Get(httpheader.AcceptEncoding) == "" {
+		req.Header.Set(httpheader.AcceptEncoding, r.options.AcceptEncoding)
+	}
+
+	r.injectTraceContext(ctx, req.Header)
+	req.Header.Set(httpheader.UserAgent, r.options.UserAgent)
+
+	if r.options.throttler != nil {
+		waitStartTime := time.Now()
+		err := r.options.throttler.Wait(ctx, req.URL.Host)
+
+		recordQueueDuration(ctx, waitStartTime, queueReasonThrottle, activeRequestsAttrSet)
+
+		if err != nil {
+			msg := "failed to wait for rate limit reset"
+			span.SetStatus(codes.Error, msg)
+			span.RecordError(err)
+
+			r.logRequestAttempt(ctx, span, logger, req, nil, err, msg)
+
+			return nil, err
+		}
+	}
+
+	err = WaitRateLimiter(ctx, r.options.RateLimiter, activeRequestsAttrSet)
+	if err != nil {
+		msg := "failed to wait for the rate limiter"
+		span.SetStatus(codes.Error, msg)
+		span.RecordError(err)
+
+		r.logRequestAttempt(ctx, span, logger, req, nil, err, msg)
+
+		return nil, err
+	}
+
+	err = r.runRequestHooks(req)
+	if err != nil {
+		msg := "request hook failed"
+		span.SetStatus(codes.Error, msg)
+		span.RecordError(err)
+
+		r.logRequestAttempt(ctx, span, logger, req, nil, err, msg)
+
+		return nil, err
+	}
+
+	// Authenticates after request hooks so signatures such as AWS SigV4 cover headers set by the hooks.
+	err = r.applyAuth(client, req)
 	if err != nil {
 		msg := "failed to authenticate request"
 
@@ -602,11 +1100,34 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 		return nil, err
 	}
 
-	propagator := otel.GetTextMapPropagator()
-	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
-	req.Header.Set(httpheader.UserAgent, r.options.UserAgent)
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = newByteCounterBody(ctx, req.Body, metrics.BytesSent, activeRequestsAttrSet)
+	}
+
+	r.withUploadProgress(req)
+
+	var cacheEntry *CacheEntry
+
+	cacheKey := r.responseCacheKey()
+	if cacheKey != "" {
+		cacheEntry = r.applyCacheValidators(ctx, logger, req, cacheKey)
+	}
+
+	var rawResp *http.Response
+
+	if connTimer != nil {
+		connTimer.start()
+	}
+
+	rawResp, err = r.send(client, req)
+	if err == nil && rawResp.StatusCode == http.StatusUnauthorized {
+		rawResp, err = r.answerAuthChallenge(client, req, rawResp)
+	}
+
+	if err != nil && connTimer != nil && errors.Is(context.Cause(reqCtx), ErrConnectTimeout) {
+		err = fmt.Errorf("%w: %w", ErrConnectTimeout, err)
+	}
 
-	rawResp, err := client.Do(req)
 	if err != nil {
 		msg := "failed to execute request"
 		span.SetStatus(codes.Error, msg)
@@ -617,9 +1138,47 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 		return nil, err
 	}
 
+	if connTimer != nil && rawResp.Body != nil {
+		rawResp.Body = &responseBodyWithCancel{
+			ReadCloser: rawResp.Body,
+			cancel:     connTimer.release,
+		}
+		connTimer = nil
+	}
+
+	if r.options.throttler != nil {
+		r.options.throttler.Observe(req.URL.Host, rawResp.Header)
+	}
+
+	err = r.runResponseHooks(rawResp)
+	if err != nil {
+		goutils.CloseResponse(rawResp)
+
+		msg := "response hook failed"
+		span.SetStatus(codes.Error, msg)
+		span.RecordError(err)
+
+		r.logRequestAttempt(ctx, span, logger, req, rawResp, err, msg)
+
+		return nil, err
+	}
+
+	// HTTP/2 requests are multiplexed as streams over shared connections.
+	if rawResp.ProtoMajor == 2 && rawResp.Body != nil && rawResp.Body != http.NoBody {
+		rawResp.Body = newActiveStreamBody(ctx, rawResp.Body, metrics, activeRequestsAttrSet)
+	}
+
+	metrics.Responses.Add(
+		ctx,
+		1,
+		metric.WithAttributeSet(r.options.metricAttributeSet(
+			append(slices.Clip(commonAttrs), httpResponseStatusClassAttr(rawResp.StatusCode))...,
+		)),
+	)
+
 	statusCodeAttr := semconv.HTTPResponseStatusCode(rawResp.StatusCode)
 	commonAttrs = append(commonAttrs, statusCodeAttr)
-	commonAttrsSet := metric.WithAttributeSet(attribute.NewSet(commonAttrs...))
+	commonAttrsSet := metric.WithAttributeSet(r.options.metricAttributeSet(commonAttrs...))
 
 	span.SetAttributes(statusCodeAttr)
 
@@ -636,6 +1195,17 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 			rawResp.ContentLength,
 			commonAttrsSet)
 		span.SetAttributes(semconv.HTTPResponseBodySize(int(rawResp.ContentLength)))
+	} else if rawResp.ContentLength < 0 && rawResp.Body != nil && rawResp.Body != http.NoBody &&
+		rawResp.StatusCode != http.StatusSwitchingProtocols {
+		// The length of chunked responses is unknown until the body is read,
+		// so the bytes which are read are recorded when the body is closed.
+		// The upgraded connection of 101 responses isn't wrapped to keep it writable.
+		rawResp.Body = newResponseSizeBody(ctx, rawResp.Body, metrics, commonAttrsSet)
+	}
+
+	if rawResp.Body != nil && rawResp.Body != http.NoBody &&
+		rawResp.StatusCode != http.StatusSwitchingProtocols {
+		rawResp.Body = newByteCounterBody(ctx, rawResp.Body, metrics.BytesReceived, commonAttrsSet)
 	}
 
 	remoteAddr := span.RemoteAddress()
@@ -660,7 +1230,20 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 		}
 	}
 
+	if cacheEntry != nil && rawResp.StatusCode == http.StatusNotModified {
+		goutils.CloseResponse(rawResp)
+
+		rawResp = newCachedResponse(rawResp, cacheEntry)
+		cacheKey = ""
+
+		span.SetAttributes(attribute.Bool("http.response.cached", true))
+	}
+
 	if rawResp.Body == nil || rawResp.Body == http.NoBody {
+		if cacheKey != "" {
+			r.storeResponseCache(ctx, logger, rawResp, req.Header, cacheKey)
+		}
+
 		if rawResp.StatusCode >= http.StatusBadRequest {
 			span.SetStatus(codes.Error, rawResp.Status)
 
@@ -679,10 +1262,13 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 	responseEncoding := rawResp.Header[httpheader.ContentEncoding]
 
 	if rawResp.Body != nil && len(responseEncoding) > 0 {
-		decompressedBody, err := gocompress.DefaultCompressor.Decompress(
-			rawResp.Body,
-			responseEncoding[0],
-		)
+		var decompressedBody io.ReadCloser
+
+		formats, err := parseContentEncoding(responseEncoding[0])
+		if err == nil {
+			decompressedBody, err = decompressContent(rawResp.Body, formats)
+		}
+
 		if err != nil {
 			goutils.CloseResponse(rawResp)
 
@@ -703,13 +1289,57 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 			return rawResp, err
 		}
 
-		rawResp.Body = decompressedBody
+		if len(formats) > 0 {
+			// The decoded body no longer matches the encoding and length of the raw response.
+			rawResp.Body = decompressedBody
+			rawResp.Header.Del(httpheader.ContentEncoding)
+			rawResp.Header.Del(httpheader.ContentLength)
+			rawResp.ContentLength = -1
+			rawResp.Uncompressed = true
+		}
+	}
+
+	if r.options.MaxResponseBodySize > 0 {
+		if rawResp.ContentLength > r.options.MaxResponseBodySize {
+			goutils.CloseResponse(rawResp)
+
+			err := fmt.Errorf(
+				"%w: content length %d is greater than %d bytes",
+				ErrResponseBodyTooLarge,
+				rawResp.ContentLength,
+				r.options.MaxResponseBodySize,
+			)
+
+			msg := "response body is too large"
+			span.SetStatus(codes.Error, msg)
+			span.RecordError(err)
+
+			r.logRequestAttempt(ctx, span, logger, req, rawResp, err, msg)
+
+			return rawResp, err
+		}
+
+		rawResp.Body = newLimitedBody(rawResp.Body, r.options.MaxResponseBodySize)
+	}
+
+	// Cache the untransformed body, so cached responses are wrapped by the middlewares only once when served.
+	if cacheKey != "" {
+		r.storeResponseCache(ctx, logger, rawResp, req.Header, cacheKey)
 	}
 
+	wrapResponseReader(rawResp, r.options.ResponseReaderMiddlewares)
+
 	if rawResp.StatusCode >= http.StatusBadRequest {
 		span.SetStatus(codes.Error, rawResp.Status)
 
-		err := httpErrorFromResponse(rawResp)
+		var err *HTTPError
+
+		if r.options.CaptureErrorBody {
+			err = httpErrorFromResponseWithBody(rawResp, r.capturedErrorBodySize())
+		} else {
+			err = httpErrorFromResponse(rawResp)
+		}
+
 		r.logRequestAttempt(ctx, span, logger, req, rawResp, err, rawResp.Status)
 
 		return rawResp, err
@@ -730,6 +1360,32 @@ func (r *Request) doRequest( //nolint:funlen,maintidx
 	return rawResp, nil
 }
 
+// capturedErrorBodySize returns the maximum size of the captured error response body.
+func (r *Request) capturedErrorBodySize() int64 {
+	if r.options.MaxResponseBodySize > 0 {
+		return min(r.options.MaxResponseBodySize, MaxCapturedErrorBodySize)
+	}
+
+	return MaxCapturedErrorBodySize
+}
+
+// truncatedLogBodyMarker is appended to bodies which are truncated in logs and span attributes.
+const truncatedLogBodyMarker = "…(truncated)"
+
+// truncateLogBody truncates the body to the maximum size in bytes without splitting a UTF-8 character.
+func truncateLogBody(body string, maxSize int) string {
+	if maxSize <= 0 || len(body) <= maxSize {
+		return body
+	}
+
+	size := maxSize
+	for size > 0 && !utf8.RuneStart(body[size]) {
+		size--
+	}
+
+	return body[:size] + truncatedLogBodyMarker
+}
+
 func (r *Request) logRequestAttempt(
 	ctx context.Context,
 	span HTTPClientTracer,
@@ -748,11 +1404,11 @@ func (r *Request) logRequestAttempt(
 	logAttrs := make([]any, 0, 4)
 
 	if req != nil {
-		requestHeaders := otelutils.ExtractTelemetryHeaders(req.Header, nil)
+		requestHeaders := r.options.extractTelemetryHeaders(req.Header)
 		otelutils.SetSpanHeaderMatrixAttributes(span, "http.request.header", requestHeaders)
 
 		requestLogAttrs := []slog.Attr{
-			slog.String("url", r.url),
+			slog.String("url", r.requestURL),
 			slog.String("method", r.method),
 			otelutils.NewHeaderMatrixLogGroupAttrs("headers", requestHeaders),
 		}
@@ -761,7 +1417,7 @@ func (r *Request) logRequestAttempt(
 	}
 
 	if resp != nil {
-		responseHeaders := otelutils.ExtractTelemetryHeaders(resp.Header, nil)
+		responseHeaders := r.options.extractTelemetryHeaders(resp.Header)
 
 		otelutils.SetSpanHeaderMatrixAttributes(span, "http.response.header", responseHeaders)
 
@@ -784,3 +1440,42 @@ func (r *Request) logRequestAttempt(
 
 	logger.Debug(message, logAttrs...)
 }
+
+// spanName returns the name of the client span of the request.
+func (r *Request) spanName(endpoint *url.URL) string {
+	if r.options.SpanNameFunc != nil {
+		if name := r.options.SpanNameFunc(r); name != "" {
+			return name
+		}
+	}
+
+	if r.options.TraceHighCardinalityPath {
+		return r.method + " " + endpoint.Path
+	}
+
+	return r.method
+}
+
+// runRequestHooks invokes request hooks in registration order.
+func (r *Request) runRequestHooks(req *http.Request) error {
+	for _, hook := range r.options.RequestHooks {
+		err := hook(req)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runResponseHooks invokes response hooks in registration order.
+func (r *Request) runResponseHooks(resp *http.Response) error {
+	for _, hook := range r.options.ResponseHooks {
+		err := hook(resp)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
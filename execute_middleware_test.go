@@ -0,0 +1,163 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+)
+
+func TestExecuteMiddleware(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// The first attempt fails to be retried.
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("short_circuit", func(t *testing.T) {
+		attempts.Store(0)
+
+		cached := func(next gohttpc.ExecuteFunc) gohttpc.ExecuteFunc {
+			return func(ctx context.Context, req *gohttpc.Request) (*http.Response, error) {
+				if req.Method() != http.MethodGet {
+					return next(ctx, req)
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("cached")),
+				}, nil
+			}
+		}
+
+		client := gohttpc.NewClient(gohttpc.WithExecuteMiddleware(cached))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(body) != "cached" {
+			t.Errorf("expected the cached body, got: %s", string(body))
+		}
+
+		if attempts.Load() != 0 {
+			t.Errorf("expected no request to be sent, got %d attempts", attempts.Load())
+		}
+	})
+
+	t.Run("wraps_the_whole_execution", func(t *testing.T) {
+		attempts.Store(0)
+
+		delay := int64(1)
+
+		retry, err := httpconfig.HTTPRetryConfig{
+			MaxAttempts: 2,
+			Delay:       &delay,
+		}.ToRetryPolicy() //nolint:bodyclose
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var (
+			calls    []string
+			duration time.Duration
+			status   int
+		)
+
+		timing := func(next gohttpc.ExecuteFunc) gohttpc.ExecuteFunc {
+			return func(ctx context.Context, req *gohttpc.Request) (*http.Response, error) {
+				calls = append(calls, "timing")
+				start := time.Now()
+
+				resp, err := next(ctx, req)
+
+				duration = time.Since(start)
+
+				if resp != nil {
+					status = resp.StatusCode
+				}
+
+				return resp, err
+			}
+		}
+
+		inner := func(next gohttpc.ExecuteFunc) gohttpc.ExecuteFunc {
+			return func(ctx context.Context, req *gohttpc.Request) (*http.Response, error) {
+				calls = append(calls, "inner")
+
+				return next(ctx, req)
+			}
+		}
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRetry(retry),
+			gohttpc.WithExecuteMiddleware(timing),
+			gohttpc.WithExecuteMiddleware(inner),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if strings.Join(calls, ",") != "timing,inner" {
+			t.Errorf("expected middlewares to be called once in registration order, got: %v", calls)
+		}
+
+		if attempts.Load() != 2 {
+			t.Errorf("expected the request to be retried inside the middleware, got %d attempts", attempts.Load())
+		}
+
+		if status != http.StatusOK {
+			t.Errorf("expected the middleware to observe the final status, got: %d", status)
+		}
+
+		if duration < 10*time.Millisecond {
+			t.Errorf("expected the duration to cover the whole execution, got: %s", duration)
+		}
+	})
+}
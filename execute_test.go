@@ -0,0 +1,716 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+)
+
+func TestFallbackResponse(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 2,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("returns the fallback response after exhausting retries", func(t *testing.T) {
+		attempts.Store(0)
+
+		var fallbackErr error
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRetry(retry),
+			gohttpc.WithFallbackResponse(func(req *gohttpc.Request, err error) (*http.Response, error) {
+				fallbackErr = err
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("fallback")),
+				}, nil
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+		defer goutils.CloseResponse(resp)
+
+		if attempts.Load() != 2 {
+			t.Errorf("expected 2 attempts, got: %d", attempts.Load())
+		}
+
+		if fallbackErr == nil {
+			t.Error("expected the fallback function to receive the failure")
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != http.StatusOK || string(body) != "fallback" {
+			t.Errorf("expected the fallback response, got: %d %s", resp.StatusCode, string(body))
+		}
+	})
+
+	t.Run("returns the fallback error on connection failures", func(t *testing.T) {
+		closedServer := httptest.NewServer(http.NotFoundHandler())
+		closedServer.Close()
+
+		expectedErr := errors.New("fallback error")
+
+		client := gohttpc.NewClient(
+			gohttpc.WithFallbackResponse(func(req *gohttpc.Request, err error) (*http.Response, error) {
+				return nil, expectedErr
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		_, err := client.R(http.MethodGet, closedServer.URL).Execute(t.Context()) //nolint:bodyclose
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected the fallback error, got: %v", err)
+		}
+	})
+
+	t.Run("keeps the original failure when the fallback returns nothing", func(t *testing.T) {
+		attempts.Store(0)
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRetry(retry),
+			gohttpc.WithFallbackResponse(func(req *gohttpc.Request, err error) (*http.Response, error) {
+				return nil, nil //nolint:nilnil
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		defer goutils.CloseResponse(resp)
+
+		if err == nil {
+			t.Fatal("expected the original error, got nil")
+		}
+
+		if !errors.Is(err, gohttpc.ErrServiceUnavailable) {
+			t.Errorf("expected the 503 error, got: %v", err)
+		}
+	})
+
+	t.Run("skips the fallback on non-retryable statuses", func(t *testing.T) {
+		notFoundServer := httptest.NewServer(http.NotFoundHandler())
+		defer notFoundServer.Close()
+
+		for _, options := range [][]gohttpc.ClientOption{nil, {gohttpc.WithRetry(retry)}} {
+			client := gohttpc.NewClient(append(options, gohttpc.WithFallbackResponse(
+				func(req *gohttpc.Request, err error) (*http.Response, error) {
+					t.Errorf("the fallback function must not be called on 4xx statuses, got: %v", err)
+
+					return nil, err
+				}),
+			)...)
+
+			resp, err := client.R(http.MethodGet, notFoundServer.URL).Execute(t.Context())
+			goutils.CloseResponse(resp)
+
+			if !errors.Is(err, gohttpc.ErrNotFound) {
+				t.Errorf("expected the 404 error, got: %v", err)
+			}
+
+			goutils.CatchWarnErrorFunc(client.Close)
+		}
+	})
+
+	t.Run("skips the fallback when the caller cancels", func(t *testing.T) {
+		arrived := make(chan struct{})
+
+		slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(arrived)
+			<-r.Context().Done()
+		}))
+		defer slowServer.Close()
+
+		client := gohttpc.NewClient(
+			gohttpc.WithFallbackResponse(func(req *gohttpc.Request, err error) (*http.Response, error) {
+				t.Errorf("the fallback function must not be called on the cancellation of the caller, got: %v", err)
+
+				return nil, err
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		ctx, cancel := context.WithCancel(t.Context())
+
+		go func() {
+			<-arrived
+			cancel()
+		}()
+
+		_, err := client.R(http.MethodGet, slowServer.URL).Execute(ctx) //nolint:bodyclose
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the context canceled error, got: %v", err)
+		}
+	})
+
+	t.Run("skips the fallback on success", func(t *testing.T) {
+		okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer okServer.Close()
+
+		client := gohttpc.NewClient(
+			gohttpc.WithFallbackResponse(func(req *gohttpc.Request, err error) (*http.Response, error) {
+				t.Error("the fallback function must not be called on success")
+
+				return nil, err
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, okServer.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+	})
+}
+
+func TestOnRetryGiveUp(t *testing.T) {
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 3,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type giveUpCall struct {
+		req  *gohttpc.Request
+		resp *http.Response
+		err  error
+	}
+
+	newClient := func(calls *[]giveUpCall, options ...gohttpc.ClientOption) *gohttpc.Client {
+		return gohttpc.NewClient(append(
+			options,
+			gohttpc.WithRetry(retry),
+			gohttpc.WithOnRetryGiveUp(func(req *gohttpc.Request, lastResp *http.Response, lastErr error) {
+				*calls = append(*calls, giveUpCall{req: req, resp: lastResp, err: lastErr})
+			}),
+		)...)
+	}
+
+	t.Run("connection errors", func(t *testing.T) {
+		closedServer := httptest.NewServer(http.NotFoundHandler())
+		closedServer.Close()
+
+		var calls []giveUpCall
+
+		client := newClient(&calls)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		req := client.R(http.MethodGet, closedServer.URL)
+
+		_, err := req.Execute(t.Context()) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if len(calls) != 1 {
+			t.Fatalf("expected the give-up callback to be called once, got: %d", len(calls))
+		}
+
+		if calls[0].req != req.Request {
+			t.Error("expected the give-up callback to receive the request")
+		}
+
+		if calls[0].resp != nil || calls[0].err == nil {
+			t.Errorf("expected the last connection error, got: %v, %v", calls[0].resp, calls[0].err)
+		}
+
+		if !errors.Is(err, calls[0].err) {
+			t.Errorf("expected the execution error to wrap the last error, got: %v", err)
+		}
+	})
+
+	t.Run("failure statuses", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		var calls []giveUpCall
+
+		client := newClient(&calls)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, _ := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		goutils.CloseResponse(resp)
+
+		if attempts.Load() != 3 {
+			t.Errorf("expected 3 attempts, got: %d", attempts.Load())
+		}
+
+		if len(calls) != 1 {
+			t.Fatalf("expected the give-up callback to be called once, got: %d", len(calls))
+		}
+
+		if calls[0].resp == nil || calls[0].resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected the last response with status 503, got: %v", calls[0].resp)
+		}
+	})
+
+	t.Run("retry budget", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		var calls []giveUpCall
+
+		client := newClient(&calls, gohttpc.WithStatusRetryBudget(2))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, _ := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		goutils.CloseResponse(resp)
+
+		if len(calls) != 1 {
+			t.Fatalf("expected the give-up callback to be called once, got: %d", len(calls))
+		}
+	})
+
+	t.Run("skips the callback on success", func(t *testing.T) {
+		var attempts atomic.Int32
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		var calls []giveUpCall
+
+		client := newClient(&calls)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if len(calls) != 0 {
+			t.Errorf("expected the give-up callback not to be called, got: %d", len(calls))
+		}
+	})
+}
+
+func TestExecute_AlreadyExecuted(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	req := client.R(http.MethodPost, server.URL)
+	req.SetBody(strings.NewReader("hello"))
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	_, err = req.Execute(t.Context()) //nolint:bodyclose
+	if !errors.Is(err, gohttpc.ErrRequestAlreadyExecuted) {
+		t.Fatalf("expected ErrRequestAlreadyExecuted, got: %v", err)
+	}
+
+	_, err = req.Clone().Execute(t.Context()) //nolint:bodyclose
+	if !errors.Is(err, gohttpc.ErrRequestAlreadyExecuted) {
+		t.Fatalf("expected ErrRequestAlreadyExecuted on the clone without a new body, got: %v", err)
+	}
+
+	clonedReq := req.Clone()
+	clonedReq.SetBody(strings.NewReader("world"))
+
+	resp, err = clonedReq.Execute(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error on the cloned request, got: %s", err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	if len(bodies) != 2 || bodies[0] != "hello" || bodies[1] != "world" {
+		t.Errorf("expected 2 requests with full bodies, got: %v", bodies)
+	}
+
+	t.Run("requests failing the validation can be executed again", func(t *testing.T) {
+		bodies = nil
+
+		invalidReq := client.R("", server.URL)
+		invalidReq.SetBody(strings.NewReader("retry"))
+
+		_, err := invalidReq.Execute(t.Context()) //nolint:bodyclose
+		if !errors.Is(err, gohttpc.ErrRequestMethodRequired) {
+			t.Fatalf("expected ErrRequestMethodRequired, got: %v", err)
+		}
+
+		invalidReq.SetMethod(http.MethodPost)
+		invalidReq.SetURL("://invalid")
+
+		_, err = invalidReq.Execute(t.Context()) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected the invalid url error, got nil")
+		}
+
+		invalidReq.SetURL(server.URL)
+
+		resp, err := invalidReq.Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if len(bodies) != 1 || bodies[0] != "retry" {
+			t.Errorf("expected the full body to be sent, got: %v", bodies)
+		}
+	})
+
+	t.Run("requests without body can be executed again", func(t *testing.T) {
+		getReq := client.R(http.MethodGet, server.URL)
+
+		for range 2 {
+			resp, err := getReq.Execute(t.Context())
+			if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(gohttpc.DefaultIdempotencyKeyHeader))
+
+		if len(keys)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 2,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(
+		gohttpc.WithRetry(retry),
+		gohttpc.WithIdempotencyKey(""),
+	)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	t.Run("reuses the same key across retry attempts", func(t *testing.T) {
+		keys = nil
+
+		req := client.R(http.MethodPost, server.URL)
+		req.SetBody(strings.NewReader("hello"))
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if len(keys) != 2 {
+			t.Fatalf("expected 2 attempts, got: %d", len(keys))
+		}
+
+		if keys[0] == "" || keys[0] != keys[1] {
+			t.Errorf("expected the same key on both attempts, got: %v", keys)
+		}
+
+		// a new logical request has a different key.
+		clonedReq := req.Clone()
+		clonedReq.SetBody(strings.NewReader("world"))
+
+		resp, err = clonedReq.Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if len(keys) != 4 || keys[2] == "" || keys[2] == keys[0] {
+			t.Errorf("expected a new key for the new request, got: %v", keys)
+		}
+	})
+
+	t.Run("keeps the key set by the caller", func(t *testing.T) {
+		keys = nil
+
+		req := client.R(http.MethodPatch, server.URL)
+		req.Header().Set(gohttpc.DefaultIdempotencyKeyHeader, "custom-key")
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if len(keys) != 2 || keys[0] != "custom-key" || keys[1] != "custom-key" {
+			t.Errorf("expected the custom key on all attempts, got: %v", keys)
+		}
+	})
+
+	t.Run("skips safe methods", func(t *testing.T) {
+		keys = nil
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		for _, key := range keys {
+			if key != "" {
+				t.Errorf("expected no idempotency key for GET requests, got: %s", key)
+			}
+		}
+	})
+}
+
+func TestRequestResponseHooks(t *testing.T) {
+	var nonces []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.Header.Get("X-Nonce"))
+
+		if len(nonces) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 2,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("runs hooks in order on every attempt", func(t *testing.T) {
+		nonces = nil
+
+		var calls []string
+
+		var counter atomic.Int32
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRetry(retry),
+			gohttpc.WithRequestHook(func(req *http.Request) error {
+				calls = append(calls, "request1")
+				req.Header.Set("X-Nonce", strconv.Itoa(int(counter.Add(1))))
+
+				return nil
+			}),
+			gohttpc.WithRequestHook(func(req *http.Request) error {
+				calls = append(calls, "request2")
+
+				return nil
+			}),
+			gohttpc.WithResponseHook(func(resp *http.Response) error {
+				calls = append(calls, "response:"+strconv.Itoa(resp.StatusCode))
+
+				return nil
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		expectedCalls := []string{"request1", "request2", "response:503", "request1", "request2", "response:200"}
+		if !slices.Equal(calls, expectedCalls) {
+			t.Errorf("expected hook calls %v, got: %v", expectedCalls, calls)
+		}
+
+		if !slices.Equal(nonces, []string{"1", "2"}) {
+			t.Errorf("expected refreshed nonces on each attempt, got: %v", nonces)
+		}
+	})
+
+	t.Run("authenticates after request hooks", func(t *testing.T) {
+		var signatures []string
+
+		signServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signatures = append(signatures, r.Header.Get("X-Signature"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer signServer.Close()
+
+		client := gohttpc.NewClient(
+			gohttpc.WithAuthenticator(headerSigner{header: "X-Nonce"}),
+			gohttpc.WithRequestHook(func(req *http.Request) error {
+				req.Header.Set("X-Nonce", "abc")
+
+				return nil
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, signServer.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if !slices.Equal(signatures, []string{"X-Nonce=abc"}) {
+			t.Errorf("expected the signature to cover the header set by the hook, got: %v", signatures)
+		}
+	})
+
+	t.Run("aborts the request if a request hook fails", func(t *testing.T) {
+		nonces = nil
+
+		expectedErr := errors.New("signing failed")
+
+		client := gohttpc.NewClient(
+			gohttpc.WithRequestHook(func(req *http.Request) error {
+				return expectedErr
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		_, err := client.R(http.MethodGet, server.URL).Execute(t.Context()) //nolint:bodyclose
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected the hook error, got: %v", err)
+		}
+
+		if len(nonces) != 0 {
+			t.Errorf("expected no request to be sent, got: %d", len(nonces))
+		}
+	})
+
+	t.Run("aborts the request if a response hook fails", func(t *testing.T) {
+		nonces = []string{""}
+
+		expectedErr := errors.New("invalid response")
+
+		client := gohttpc.NewClient(
+			gohttpc.WithResponseHook(func(resp *http.Response) error {
+				return expectedErr
+			}),
+		)
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		_, err := client.R(http.MethodGet, server.URL).Execute(t.Context()) //nolint:bodyclose
+		if !errors.Is(err, expectedErr) {
+			t.Errorf("expected the hook error, got: %v", err)
+		}
+	})
+}
+
+// headerSigner signs the value of a header to mimic signatures such as AWS SigV4.
+type headerSigner struct {
+	header string
+}
+
+func (hs headerSigner) Authenticate(req *http.Request, _ ...authscheme.AuthenticateOption) error {
+	req.Header.Set("X-Signature", hs.header+"="+req.Header.Get(hs.header))
+
+	return nil
+}
+
+func (headerSigner) Close() error {
+	return nil
+}
@@ -15,6 +15,8 @@
 package httpconfig
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -55,11 +57,59 @@ func (j HTTPClientConfig) Equal(target HTTPClientConfig) bool {
 		goutils.EqualPtr(j.Authentication, target.Authentication)
 }
 
+// Validate checks the configuration and all of its sub-configurations without building the client.
+// All errors are joined so every invalid field is reported at once.
+func (c HTTPClientConfig) Validate() error {
+	errs := []error{}
+
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("timeout %w", gohttpc.ErrNegativeConfigValue))
+	}
+
+	if c.Transport != nil {
+		err := c.Transport.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("transport: %w", err))
+		}
+	}
+
+	if c.TLS != nil {
+		err := c.TLS.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+
+	if c.Retry != nil {
+		_, err := c.Retry.ToRetryPolicy() //nolint:bodyclose
+		if err != nil {
+			errs = append(errs, fmt.Errorf("retry: %w", err))
+		}
+	}
+
+	if c.Authentication != nil {
+		err := c.Authentication.Validate(false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("authentication: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // NewClientFromConfig creates a HTTP client wrapper with configuration.
+// The configuration is validated first, see [HTTPClientConfig.Validate].
 func NewClientFromConfig(
 	config *HTTPClientConfig,
 	options ...gohttpc.ClientOption,
 ) (*gohttpc.Client, error) {
+	if config != nil {
+		err := config.Validate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	opts, err := NewClientOptionsFromConfig(config, options...)
 	if err != nil {
 		return nil, err
@@ -90,6 +140,8 @@ func NewClientOptionsFromConfig(
 		}
 
 		opts.Retry = retry
+		opts.MaxAttemptsOnError = config.Retry.MaxAttemptsOnError
+		opts.MaxAttemptsOnStatus = config.Retry.MaxAttemptsOnStatus
 	}
 
 	if config.Authentication != nil {
@@ -123,15 +175,35 @@ func NewHTTPClientFromConfig(
 		return options.HTTPClient, nil
 	}
 
+	if config.Transport != nil && config.Transport.Proxy != nil && !config.Transport.Proxy.IsZero() {
+		// Validate the proxy configuration early. The transport resolves the proxy URL itself.
+		_, err := config.Transport.Proxy.ProxyURL(options.GetEnvFunc())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	newTransport := gohttpc.TransportFromConfig(config.Transport, options)
 
 	if config.TLS != nil {
-		tlsConfig, err := loadTLSConfig(config.TLS)
+		var verifiers []gohttpc.VerifyPeerCertificateFunc
+
+		if options.VerifyPeerCertificate != nil {
+			verifiers = append(verifiers, options.VerifyPeerCertificate)
+		}
+
+		tlsConfig, reloader, err := loadTLSClientConfig(config.TLS, verifiers...)
 		if err != nil {
 			return nil, err
 		}
 
 		newTransport.TLSClientConfig = tlsConfig
+
+		if reloader != nil && reloader.verifyRoots {
+			// The connection state doesn't contain the server name of IP hosts.
+			// Dial TLS connections manually to verify the server certificate with the dial host.
+			newTransport.DialTLSContext = reloader.dialTLSContext(newTransport)
+		}
 	}
 
 	httpClient := &http.Client{
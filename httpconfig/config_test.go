@@ -15,7 +15,9 @@
 package httpconfig
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -380,6 +382,98 @@ func TestNewClientFromConfig(t *testing.T) {
 	})
 }
 
+func TestHTTPClientConfig_Validate(t *testing.T) {
+	t.Run("returns nil for a valid config", func(t *testing.T) {
+		config := HTTPClientConfig{
+			Timeout:   30,
+			Transport: &gohttpc.HTTPTransportConfig{MaxIdleConns: new(10)},
+			TLS:       &TLSConfig{MinVersion: "1.2"},
+			Retry:     &HTTPRetryConfig{MaxAttempts: 3},
+		}
+
+		if err := config.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("reports all errors of an invalid retry policy with a valid TLS", func(t *testing.T) {
+		config := HTTPClientConfig{
+			TLS: &TLSConfig{MinVersion: "1.2"},
+			Retry: &HTTPRetryConfig{
+				MaxAttempts: 3,
+				Delay:       new(int64(-1)),
+				Multiplier:  new(0.5),
+			},
+		}
+
+		err := config.Validate()
+		if !errors.Is(err, errRetryPolicyDelayPositive) {
+			t.Errorf("expected the retry delay error, got: %v", err)
+		}
+
+		if !errors.Is(err, errRetryPolicyInvalidMultiplier) {
+			t.Errorf("expected the retry multiplier error, got: %v", err)
+		}
+
+		if strings.Contains(err.Error(), "tls:") {
+			t.Errorf("expected no TLS error, got: %v", err)
+		}
+	})
+
+	t.Run("reports errors of every sub-configuration", func(t *testing.T) {
+		config := HTTPClientConfig{
+			Timeout: -1,
+			Transport: &gohttpc.HTTPTransportConfig{
+				MaxConnsPerHost: new(-1),
+				Dialer:          &gohttpc.HTTPDialerConfig{KeepAliveCount: new(-1)},
+			},
+			TLS:   &TLSConfig{MinVersion: "0.9"},
+			Retry: &HTTPRetryConfig{MaxAttempts: -1},
+		}
+
+		err := config.Validate()
+		if !errors.Is(err, gohttpc.ErrNegativeConfigValue) {
+			t.Errorf("expected the negative value error, got: %v", err)
+		}
+
+		if !errors.Is(err, errUnsupportedTLSVersion) {
+			t.Errorf("expected the TLS version error, got: %v", err)
+		}
+
+		if !errors.Is(err, errRetryPolicyTimesPositive) {
+			t.Errorf("expected the retry times error, got: %v", err)
+		}
+
+		for _, prefix := range []string{
+			"timeout must not be negative",
+			"transport: dialer: keepAliveCount must not be negative",
+			"maxConnsPerHost must not be negative",
+			"tls: minVersion:",
+			"retry:",
+		} {
+			if !strings.Contains(err.Error(), prefix) {
+				t.Errorf("expected error to contain %q, got: %v", prefix, err)
+			}
+		}
+	})
+
+	t.Run("NewClientFromConfig fails fast with the validation error", func(t *testing.T) {
+		config := &HTTPClientConfig{
+			TLS:   &TLSConfig{MinVersion: "0.9"},
+			Retry: &HTTPRetryConfig{MaxAttempts: -1},
+		}
+
+		client, err := NewClientFromConfig(config)
+		if client != nil {
+			t.Error("expected client to be nil")
+		}
+
+		if !errors.Is(err, errUnsupportedTLSVersion) || !errors.Is(err, errRetryPolicyTimesPositive) {
+			t.Errorf("expected both TLS and retry errors, got: %v", err)
+		}
+	})
+}
+
 func TestNewHTTPClientFromConfig(t *testing.T) {
 	t.Run("returns existing HTTP client when no transport or TLS config", func(t *testing.T) {
 		existingClient := &http.Client{}
@@ -0,0 +1,177 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/authc"
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/gohttpc/loadbalancer/roundrobin"
+)
+
+var errLoadBalancerHostsRequired = errors.New("load balancer requires at least one host")
+
+// LoadBalancerConfig contains configurations to create a load-balanced client with multiple hosts.
+type LoadBalancerConfig struct {
+	HTTPClientConfig `yaml:",inline"`
+
+	// Hosts to load balance requests with the weighted round-robin algorithm.
+	Hosts []LoadBalancerHostConfig `json:"hosts" yaml:"hosts"`
+	// Default health check configuration of hosts. Hosts can override it with their own config.
+	HealthCheck *loadbalancer.HTTPHealthCheckConfig `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+}
+
+// LoadBalancerHostConfig contains configurations of a load-balanced host.
+type LoadBalancerHostConfig struct {
+	// An optional unique name of the host. Default to the host of the URL.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// The base URL of the host.
+	URL goenvconf.EnvString `json:"url" yaml:"url"`
+	// The weight of the host for load balancing. Default to 1.
+	Weight int `json:"weight,omitempty" jsonschema:"minimum=0" yaml:"weight,omitempty"`
+	// Custom headers to be injected to requests of the host.
+	Headers map[string]goenvconf.EnvString `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Health check configuration of the host.
+	HealthCheck *loadbalancer.HTTPHealthCheckConfig `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	// Authentication configuration of the host.
+	Authentication *authc.HTTPClientAuthConfig `json:"authentication,omitempty" yaml:"authentication,omitempty"`
+}
+
+// NewLoadBalancerClientFromConfig creates a load-balanced client with the weighted round-robin algorithm from configuration.
+// If any host has a health check, the health check runs in the background until the context is canceled.
+func NewLoadBalancerClientFromConfig(
+	ctx context.Context,
+	config *LoadBalancerConfig,
+	options ...gohttpc.ClientOption,
+) (*loadbalancer.LoadBalancerClient, error) {
+	if config == nil || len(config.Hosts) == 0 {
+		return nil, errLoadBalancerHostsRequired
+	}
+
+	opts, err := NewClientOptionsFromConfig(&config.HTTPClientConfig, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]*loadbalancer.Host, len(config.Hosts))
+
+	var healthCheckInterval time.Duration
+
+	for i, hostConfig := range config.Hosts {
+		healthCheck := hostConfig.HealthCheck
+		if healthCheck == nil {
+			healthCheck = config.HealthCheck
+		}
+
+		host, interval, err := newHostFromConfig(&hostConfig, healthCheck, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create host %d: %w", i, err)
+		}
+
+		if interval > 0 && (healthCheckInterval <= 0 || interval < healthCheckInterval) {
+			healthCheckInterval = interval
+		}
+
+		hosts[i] = host
+	}
+
+	wrr, err := roundrobin.NewWeightedRoundRobin(
+		hosts,
+		roundrobin.WithHealthCheckInterval(healthCheckInterval),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := loadbalancer.NewLoadBalancerClientWithOptions(wrr, opts)
+
+	if healthCheckInterval > 0 {
+		go client.StartHealthCheck(ctx)
+	}
+
+	return client, nil
+}
+
+// newHostFromConfig creates a host from configuration and returns the health check interval of the host.
+func newHostFromConfig(
+	config *LoadBalancerHostConfig,
+	healthCheck *loadbalancer.HTTPHealthCheckConfig,
+	options *gohttpc.ClientOptions,
+) (*loadbalancer.Host, time.Duration, error) {
+	baseURL, err := config.URL.GetCustom(options.GetEnv)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get url: %w", err)
+	}
+
+	hostOptions := []loadbalancer.HostOption{loadbalancer.WithWeight(config.Weight)}
+
+	var interval time.Duration
+
+	if healthCheck != nil {
+		builder, err := healthCheck.ToPolicyBuilder()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		interval = builder.Interval()
+		hostOptions = append(hostOptions, loadbalancer.WithHTTPHealthCheckPolicyBuilder(builder))
+	}
+
+	host, err := loadbalancer.NewHost(options.HTTPClient, baseURL, hostOptions...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if config.Name != "" {
+		host.SetName(config.Name)
+	}
+
+	if len(config.Headers) > 0 {
+		headers := make(map[string]string, len(config.Headers))
+
+		for key, headerEnv := range config.Headers {
+			header, err := headerEnv.GetCustom(options.GetEnv)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to get header %s: %w", key, err)
+			}
+
+			if header != "" {
+				headers[key] = header
+			}
+		}
+
+		host.SetHeaders(headers)
+	}
+
+	if config.Authentication != nil {
+		authenticator, err := authc.NewAuthenticatorFromConfig(
+			config.Authentication,
+			&options.HTTPClientAuthenticatorOptions,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		host.SetAuthenticator(authenticator)
+	}
+
+	return host, interval, nil
+}
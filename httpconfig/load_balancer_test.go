@@ -0,0 +1,134 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc/loadbalancer"
+	"go.yaml.in/yaml/v4"
+)
+
+func TestNewLoadBalancerClientFromConfig(t *testing.T) {
+	newServer := func(t *testing.T, name string, counter *atomic.Int32) *httptest.Server {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Host") != name {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			if username, _, _ := r.BasicAuth(); name == "host2" && username != "user" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			counter.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	var counter1, counter2 atomic.Int32
+
+	server1 := newServer(t, "host1", &counter1)
+	server2 := newServer(t, "host2", &counter2)
+
+	rawConfig := fmt.Sprintf(`
+timeout: 10
+healthCheck:
+  path: /healthz
+  interval: 0
+hosts:
+  - name: host1
+    url:
+      value: %s
+    headers:
+      X-Host:
+        value: host1
+  - url:
+      value: %s
+    weight: 2
+    headers:
+      X-Host:
+        value: host2
+    authentication:
+      type: basic
+      username:
+        value: user
+      password:
+        value: pass
+`, server1.URL, server2.URL)
+
+	var config LoadBalancerConfig
+
+	err := yaml.Unmarshal([]byte(rawConfig), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Timeout != 10 || len(config.Hosts) != 2 {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+
+	client, err := NewLoadBalancerClientFromConfig(t.Context(), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	for range 6 {
+		resp, err := client.R(http.MethodGet, "/").Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = resp.Body.Close()
+	}
+
+	if counter1.Load() != 2 || counter2.Load() != 4 {
+		t.Errorf("expected 2 requests to host 1 and 4 requests to host 2, got: %d, %d", counter1.Load(), counter2.Load())
+	}
+
+	t.Run("returns error without hosts", func(t *testing.T) {
+		_, err := NewLoadBalancerClientFromConfig(t.Context(), &LoadBalancerConfig{})
+		if !errors.Is(err, errLoadBalancerHostsRequired) {
+			t.Errorf("expected errLoadBalancerHostsRequired, got: %v", err)
+		}
+	})
+
+	t.Run("returns error with invalid health check", func(t *testing.T) {
+		_, err := NewLoadBalancerClientFromConfig(t.Context(), &LoadBalancerConfig{
+			Hosts: config.Hosts,
+			HealthCheck: &loadbalancer.HTTPHealthCheckConfig{
+				Method: http.MethodPut,
+			},
+		})
+		if !errors.Is(err, loadbalancer.ErrInvalidHealthCheckMethod) {
+			t.Errorf("expected ErrInvalidHealthCheckMethod, got: %v", err)
+		}
+	})
+}
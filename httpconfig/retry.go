@@ -18,15 +18,20 @@ import (
 	"context"
 	"crypto/x509"
 	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
-	"github.com/failsafe-go/failsafe-go/failsafehttp"
+	"github.com/failsafe-go/failsafe-go"
 	"github.com/failsafe-go/failsafe-go/retrypolicy"
+	"github.com/relychan/gohttpc"
 	"github.com/relychan/goutils"
 )
 
@@ -43,6 +48,12 @@ var stoppedAfterRedirects = regexp.MustCompile(`stopped after \d+ redirects\z`)
 type HTTPRetryConfig struct {
 	// Maximum number of retry attempts.
 	MaxAttempts int `json:"maxAttempts,omitempty" jsonschema:"minimum=0" mapstructure:"maxAttempts" yaml:"maxAttempts"`
+	// Maximum number of attempts which fail with transport errors, such as connection resets or refusals.
+	// The total number of attempts is still capped by maxAttempts. Zero means no separate limit.
+	MaxAttemptsOnError int `json:"maxAttemptsOnError,omitempty" jsonschema:"minimum=0" mapstructure:"maxAttemptsOnError" yaml:"maxAttemptsOnError,omitempty"`
+	// Maximum number of attempts which fail with retryable HTTP statuses.
+	// The total number of attempts is still capped by maxAttempts. Zero means no separate limit.
+	MaxAttemptsOnStatus int `json:"maxAttemptsOnStatus,omitempty" jsonschema:"minimum=0" mapstructure:"maxAttemptsOnStatus" yaml:"maxAttemptsOnStatus,omitempty"`
 	// The initial wait time in milliseconds before a retry is attempted.
 	// Must be >0. Defaults to 1 second.
 	Delay *int64 `json:"delay,omitempty" jsonschema:"minimum=1,default=1" mapstructure:"delay" yaml:"delay,omitempty"`
@@ -62,17 +73,25 @@ type HTTPRetryConfig struct {
 	// For example: a retry delay of 100 milliseconds and a jitterFactor of .25 will result in a random retry delay between 75 and 125 milliseconds.
 	// Replaces any previously configured jitter duration.
 	JitterFactor *float64 `json:"jitterFactor,omitempty" mapstructure:"jitterFactor" yaml:"jitterFactor,omitempty"`
+	// Retry on timeout errors, such as dial, TLS handshake or response header timeouts. Defaults to true.
+	RetryOnTimeout *bool `json:"retryOnTimeout,omitempty" jsonschema:"default=true" mapstructure:"retryOnTimeout" yaml:"retryOnTimeout,omitempty"`
+	// Retry on connection errors, such as connection refusals, resets or unexpected EOF. Defaults to true.
+	RetryOnConnectionError *bool `json:"retryOnConnectionError,omitempty" jsonschema:"default=true" mapstructure:"retryOnConnectionError" yaml:"retryOnConnectionError,omitempty"`
 }
 
 // IsZero if the current instance is empty.
 func (rs HTTPRetryConfig) IsZero() bool {
 	return rs.MaxAttempts <= 0 &&
+		rs.MaxAttemptsOnError <= 0 &&
+		rs.MaxAttemptsOnStatus <= 0 &&
 		rs.Delay == nil &&
 		rs.MaxDelay == nil &&
 		len(rs.HTTPStatus) == 0 &&
 		rs.Multiplier == nil &&
 		rs.Jitter == nil &&
-		rs.JitterFactor == nil
+		rs.JitterFactor == nil &&
+		rs.RetryOnTimeout == nil &&
+		rs.RetryOnConnectionError == nil
 }
 
 // Equal checks if this instance equals the target.
@@ -82,8 +101,12 @@ func (rs HTTPRetryConfig) Equal(target HTTPRetryConfig) bool {
 		goutils.EqualComparablePtr(rs.Multiplier, target.Multiplier) &&
 		goutils.EqualComparablePtr(rs.Jitter, target.Jitter) &&
 		goutils.EqualComparablePtr(rs.JitterFactor, target.JitterFactor) &&
+		goutils.EqualComparablePtr(rs.RetryOnTimeout, target.RetryOnTimeout) &&
+		goutils.EqualComparablePtr(rs.RetryOnConnectionError, target.RetryOnConnectionError) &&
 		goutils.EqualSliceSorted(rs.HTTPStatus, target.HTTPStatus) &&
-		rs.MaxAttempts == target.MaxAttempts
+		rs.MaxAttempts == target.MaxAttempts &&
+		rs.MaxAttemptsOnError == target.MaxAttemptsOnError &&
+		rs.MaxAttemptsOnStatus == target.MaxAttemptsOnStatus
 }
 
 // ToRetryPolicy validates and create the retry policy.
@@ -101,7 +124,7 @@ func (rs HTTPRetryConfig) ToRetryPolicy() ( //nolint:funlen
 		return nil, nil //nolint:nilnil
 	}
 
-	if rs.MaxAttempts < 0 {
+	if rs.MaxAttempts < 0 || rs.MaxAttemptsOnError < 0 || rs.MaxAttemptsOnStatus < 0 {
 		errs = append(errs, errRetryPolicyTimesPositive)
 	}
 
@@ -159,17 +182,99 @@ func (rs HTTPRetryConfig) ToRetryPolicy() ( //nolint:funlen
 	}
 
 	builder = builder.
-		HandleIf(retryHandleFunc(rs.HTTPStatus)).
+		HandleIf(retryHandleFunc(rs)).
 		AbortOnErrors(context.Canceled, context.DeadlineExceeded).
-		WithDelayFunc(failsafehttp.DelayFunc)
+		WithDelayFunc(retryAfterDelayFunc(time.Duration(maxDelay) * time.Millisecond))
 
 	return builder.Build(), nil
 }
 
-func retryHandleFunc(httpStatus []int) func(resp *http.Response, err error) bool {
+// retryAfterDelayFunc creates a delay function which waits according to the Retry-After header of 429 and 503 responses.
+// The delay is capped at maxDelay if it is positive. Returns -1 to fall back to the configured backoff delay.
+func retryAfterDelayFunc(maxDelay time.Duration) failsafe.DelayFunc[*http.Response] {
+	return func(exec failsafe.ExecutionAttempt[*http.Response]) time.Duration {
+		return getRetryAfterDelay(exec.LastResult(), maxDelay, time.Now())
+	}
+}
+
+func getRetryAfterDelay(resp *http.Response, maxDelay time.Duration, now time.Time) time.Duration {
+	if resp == nil ||
+		(resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return -1
+	}
+
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if !ok {
+		return -1
+	}
+
+	if maxDelay > 0 {
+		delay = min(delay, maxDelay)
+	}
+
+	return delay
+}
+
+// parseRetryAfter parses the Retry-After header value in either delay-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(date.Sub(now), 0), true
+}
+
+// networkErrorKind represents the class of a transport error which can be retried separately.
+type networkErrorKind int
+
+const (
+	networkErrorOther networkErrorKind = iota
+	networkErrorTimeout
+	networkErrorConnection
+)
+
+// classifyNetworkError classifies the transport error by its type rather than the error message.
+func classifyNetworkError(err error) networkErrorKind {
+	var netErr net.Error
+
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return networkErrorTimeout
+	}
+
+	var opErr *net.OpError
+
+	if errors.As(err, &opErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) {
+		return networkErrorConnection
+	}
+
+	return networkErrorOther
+}
+
+func retryHandleFunc(rs HTTPRetryConfig) func(resp *http.Response, err error) bool {
+	retryOnTimeout := rs.RetryOnTimeout == nil || *rs.RetryOnTimeout
+	retryOnConnectionError := rs.RetryOnConnectionError == nil || *rs.RetryOnConnectionError
+
 	return func(resp *http.Response, err error) bool {
-		// Handle errors
-		if err != nil {
+		// Handle errors. The error of a response status is handled by the status below.
+		if _, isHTTPError := errors.AsType[*gohttpc.HTTPError](err); err != nil && !isHTTPError {
 			errorMsg := err.Error()
 			// Do not retry unsupported protocol scheme error
 			// This will be a url.Error when using an http.Client, and an errorString when using a RoundTripper
@@ -188,13 +293,21 @@ func retryHandleFunc(httpStatus []int) func(resp *http.Response, err error) bool
 					return false
 				}
 			}
-			// Retry on all other url errors
-			return true
+
+			switch classifyNetworkError(err) {
+			case networkErrorTimeout:
+				return retryOnTimeout
+			case networkErrorConnection:
+				return retryOnConnectionError
+			default:
+				// Retry on all other url errors
+				return true
+			}
 		}
 
 		// Handle response
 		if resp != nil {
-			if len(httpStatus) > 0 && slices.Contains(httpStatus, resp.StatusCode) {
+			if len(rs.HTTPStatus) > 0 && slices.Contains(rs.HTTPStatus, resp.StatusCode) {
 				return true
 			}
 
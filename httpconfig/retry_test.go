@@ -15,9 +15,21 @@
 package httpconfig
 
 import (
+	"context"
 	"errors"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go"
+	"github.com/relychan/gohttpc"
 )
 
 func TestHTTPRetryConfig_IsZero(t *testing.T) {
@@ -320,6 +332,18 @@ func TestHTTPRetryConfig_ToRetryPolicy(t *testing.T) {
 		}
 	})
 
+	t.Run("returns error when MaxAttemptsOnError is negative", func(t *testing.T) {
+		config := HTTPRetryConfig{
+			MaxAttempts:        3,
+			MaxAttemptsOnError: -1,
+		}
+
+		_, err := config.ToRetryPolicy()
+		if !errors.Is(err, errRetryPolicyTimesPositive) {
+			t.Errorf("expected errRetryPolicyTimesPositive, got: %v", err)
+		}
+	})
+
 	t.Run("returns error when MaxAttempts is negative", func(t *testing.T) {
 		config := HTTPRetryConfig{
 			MaxAttempts: -1,
@@ -470,7 +494,7 @@ func TestHTTPRetryConfig_ToRetryPolicy(t *testing.T) {
 
 func TestRetryHandleFunc(t *testing.T) {
 	t.Run("retries on 429 Too Many Requests", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		resp := &http.Response{
 			StatusCode: http.StatusTooManyRequests,
@@ -482,7 +506,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("retries on 5xx errors except 501", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		testCases := []struct {
 			StatusCode  int
@@ -512,7 +536,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("retries on custom HTTP status codes", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{408, 503})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{HTTPStatus: []int{408, 503}})
 
 		resp := &http.Response{
 			StatusCode: 408,
@@ -524,7 +548,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("does not retry on unsupported protocol scheme error", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		err := errors.New("unsupported protocol scheme")
 
@@ -534,7 +558,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("does not retry on certificate not trusted error", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		err := errors.New("certificate is not trusted")
 
@@ -544,7 +568,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("does not retry on stopped after redirects error", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		err := errors.New("stopped after 10 redirects")
 
@@ -554,7 +578,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("retries on other errors", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		err := errors.New("connection refused")
 
@@ -564,7 +588,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("does not retry when response is nil and error is nil", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		if handleFunc(nil, nil) {
 			t.Error("expected not to retry when both response and error are nil")
@@ -572,7 +596,7 @@ func TestRetryHandleFunc(t *testing.T) {
 	})
 
 	t.Run("does not retry on 2xx status codes", func(t *testing.T) {
-		handleFunc := retryHandleFunc([]int{})
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
 
 		resp := &http.Response{
 			StatusCode: http.StatusOK,
@@ -582,4 +606,385 @@ func TestRetryHandleFunc(t *testing.T) {
 			t.Error("expected not to retry on 200 OK")
 		}
 	})
+
+	t.Run("handles the HTTP error by the response status", func(t *testing.T) {
+		handleFunc := retryHandleFunc(HTTPRetryConfig{})
+
+		notFound := &http.Response{StatusCode: http.StatusNotFound}
+		if handleFunc(notFound, gohttpc.NewHTTPError(http.StatusNotFound)) {
+			t.Error("expected not to retry on 404 Not Found")
+		}
+
+		unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+		if !handleFunc(unavailable, gohttpc.NewHTTPError(http.StatusServiceUnavailable)) {
+			t.Error("expected to retry on 503 Service Unavailable")
+		}
+	})
+}
+
+// timeoutError is a synthetic [net.Error] which reports a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryHandleFunc_NetworkErrors(t *testing.T) {
+	disabled := false
+
+	urlError := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://localhost", Err: err}
+	}
+
+	testCases := []struct {
+		Name                   string
+		Err                    error
+		Config                 HTTPRetryConfig
+		ExpectedKind           networkErrorKind
+		ShouldRetry            bool
+		ShouldRetryWhenDisable bool
+	}{
+		{
+			Name:         "timeout",
+			Err:          urlError(&net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}),
+			Config:       HTTPRetryConfig{RetryOnTimeout: &disabled},
+			ExpectedKind: networkErrorTimeout,
+			ShouldRetry:  true,
+		},
+		{
+			Name:         "net_error_timeout",
+			Err:          urlError(timeoutError{}),
+			Config:       HTTPRetryConfig{RetryOnTimeout: &disabled},
+			ExpectedKind: networkErrorTimeout,
+			ShouldRetry:  true,
+		},
+		{
+			Name:         "op_error",
+			Err:          urlError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}),
+			Config:       HTTPRetryConfig{RetryOnConnectionError: &disabled},
+			ExpectedKind: networkErrorConnection,
+			ShouldRetry:  true,
+		},
+		{
+			Name:         "unexpected_eof",
+			Err:          urlError(io.ErrUnexpectedEOF),
+			Config:       HTTPRetryConfig{RetryOnConnectionError: &disabled},
+			ExpectedKind: networkErrorConnection,
+			ShouldRetry:  true,
+		},
+		{
+			Name:         "connection_reset",
+			Err:          urlError(os.NewSyscallError("read", syscall.ECONNRESET)),
+			Config:       HTTPRetryConfig{RetryOnConnectionError: &disabled},
+			ExpectedKind: networkErrorConnection,
+			ShouldRetry:  true,
+		},
+		{
+			Name:                   "other_error_ignores_flags",
+			Err:                    urlError(errors.New("malformed HTTP response")),
+			Config:                 HTTPRetryConfig{RetryOnTimeout: &disabled, RetryOnConnectionError: &disabled},
+			ExpectedKind:           networkErrorOther,
+			ShouldRetry:            true,
+			ShouldRetryWhenDisable: true,
+		},
+		{
+			Name:                   "timeout_ignores_connection_flag",
+			Err:                    urlError(timeoutError{}),
+			Config:                 HTTPRetryConfig{RetryOnConnectionError: &disabled},
+			ExpectedKind:           networkErrorTimeout,
+			ShouldRetry:            true,
+			ShouldRetryWhenDisable: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if kind := classifyNetworkError(tc.Err); kind != tc.ExpectedKind {
+				t.Errorf("expected error kind %d, got: %d", tc.ExpectedKind, kind)
+			}
+
+			if retryHandleFunc(HTTPRetryConfig{})(nil, tc.Err) != tc.ShouldRetry {
+				t.Errorf("expected retry by default to be %t", tc.ShouldRetry)
+			}
+
+			if retryHandleFunc(tc.Config)(nil, tc.Err) != tc.ShouldRetryWhenDisable {
+				t.Errorf("expected retry with the config to be %t", tc.ShouldRetryWhenDisable)
+			}
+		})
+	}
+}
+
+func TestGetRetryAfterDelay(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		Name       string
+		StatusCode int
+		RetryAfter string
+		MaxDelay   time.Duration
+		Expected   time.Duration
+	}{
+		{
+			Name:       "numeric",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: "3",
+			Expected:   3 * time.Second,
+		},
+		{
+			Name:       "http_date",
+			StatusCode: http.StatusServiceUnavailable,
+			RetryAfter: now.Add(5 * time.Second).Format(http.TimeFormat),
+			Expected:   5 * time.Second,
+		},
+		{
+			Name:       "past_http_date",
+			StatusCode: http.StatusServiceUnavailable,
+			RetryAfter: now.Add(-5 * time.Second).Format(http.TimeFormat),
+			Expected:   0,
+		},
+		{
+			Name:       "capped_by_max_delay",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: "120",
+			MaxDelay:   2 * time.Second,
+			Expected:   2 * time.Second,
+		},
+		{
+			Name:       "capped_http_date",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: now.Add(time.Minute).Format(http.TimeFormat),
+			MaxDelay:   time.Second,
+			Expected:   time.Second,
+		},
+		{
+			Name:       "invalid_value",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: "soon",
+			Expected:   -1,
+		},
+		{
+			Name:       "negative_value",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: "-1",
+			Expected:   -1,
+		},
+		{
+			Name:       "missing_header",
+			StatusCode: http.StatusTooManyRequests,
+			Expected:   -1,
+		},
+		{
+			Name:       "ignored_status",
+			StatusCode: http.StatusInternalServerError,
+			RetryAfter: "3",
+			Expected:   -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tc.StatusCode,
+				Header:     http.Header{},
+			}
+
+			if tc.RetryAfter != "" {
+				resp.Header.Set("Retry-After", tc.RetryAfter)
+			}
+
+			result := getRetryAfterDelay(resp, tc.MaxDelay, now)
+			if result != tc.Expected {
+				t.Errorf("expected delay %s, got: %s", tc.Expected, result)
+			}
+		})
+	}
+
+	t.Run("nil_response", func(t *testing.T) {
+		if result := getRetryAfterDelay(nil, 0, now); result != -1 {
+			t.Errorf("expected delay -1, got: %s", result)
+		}
+	})
+}
+
+func TestHTTPRetryConfig_RetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", r.URL.Query().Get("retry_after"))
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name       string
+		RetryAfter string
+		MaxDelay   *int64
+		MinElapsed time.Duration
+		MaxElapsed time.Duration
+	}{
+		{
+			Name:       "waits_for_retry_after",
+			RetryAfter: "1",
+			MinElapsed: time.Second,
+			MaxElapsed: 3 * time.Second,
+		},
+		{
+			Name:       "caps_at_max_delay",
+			RetryAfter: "30",
+			MaxDelay:   new(int64(50)),
+			MaxElapsed: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			attempts.Store(0)
+
+			delay := int64(1)
+
+			policy, err := HTTPRetryConfig{
+				MaxAttempts: 2,
+				Delay:       &delay,
+				MaxDelay:    tc.MaxDelay,
+			}.ToRetryPolicy() //nolint:bodyclose
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+
+			resp, err := failsafe.With(policy).Get(func() (*http.Response, error) {
+				return http.Get(server.URL + "?retry_after=" + tc.RetryAfter) //nolint:noctx
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_ = resp.Body.Close()
+
+			elapsed := time.Since(start)
+
+			if attempts.Load() != 2 {
+				t.Errorf("expected 2 attempts, got: %d", attempts.Load())
+			}
+
+			if elapsed < tc.MinElapsed || elapsed > tc.MaxElapsed {
+				t.Errorf("expected elapsed time in [%s, %s], got: %s", tc.MinElapsed, tc.MaxElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestHTTPRetryConfig_AttemptBudgets(t *testing.T) {
+	var statusAttempts, errorAttempts atomic.Int32
+
+	statusServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusAttempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer statusServer.Close()
+
+	errorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		errorAttempts.Add(1)
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}))
+	defer errorServer.Close()
+
+	delay := int64(1)
+
+	client, err := NewClientFromConfig(&HTTPClientConfig{
+		Retry: &HTTPRetryConfig{
+			MaxAttempts:         5,
+			MaxAttemptsOnError:  3,
+			MaxAttemptsOnStatus: 2,
+			Delay:               &delay,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("status", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, statusServer.URL).Execute(context.Background())
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected the last 503 response, got: %v", resp)
+		}
+
+		if statusAttempts.Load() != 2 {
+			t.Errorf("expected 2 attempts, got: %d", statusAttempts.Load())
+		}
+	})
+
+	t.Run("ignores statuses which are not retried", func(t *testing.T) {
+		var giveUps atomic.Int32
+
+		notFoundServer := httptest.NewServer(http.NotFoundHandler())
+		defer notFoundServer.Close()
+
+		client, err := NewClientFromConfig(
+			&HTTPClientConfig{
+				Retry: &HTTPRetryConfig{
+					MaxAttempts:         5,
+					MaxAttemptsOnStatus: 1,
+					Delay:               &delay,
+				},
+			},
+			gohttpc.WithOnRetryGiveUp(func(*gohttpc.Request, *http.Response, error) {
+				giveUps.Add(1)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.R(http.MethodGet, notFoundServer.URL).Execute(context.Background())
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if !errors.Is(err, gohttpc.ErrNotFound) {
+			t.Errorf("expected the 404 error, got: %v", err)
+		}
+
+		if errors.Is(err, gohttpc.ErrTooManyRetries) {
+			t.Errorf("expected no retry error for a status which isn't retried, got: %v", err)
+		}
+
+		if giveUps.Load() != 0 {
+			t.Errorf("expected no give up callback, got: %d", giveUps.Load())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, errorServer.URL).Execute(context.Background())
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if errorAttempts.Load() != 3 {
+			t.Errorf("expected 3 attempts, got: %d", errorAttempts.Load())
+		}
+	})
 }
@@ -15,6 +15,8 @@
 package httpconfig
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
@@ -23,8 +25,10 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
 	"github.com/relychan/goutils"
 )
 
@@ -57,6 +61,13 @@ var (
 	errUnsupportedTLSVersion  = errors.New("unsupported TLS version")
 	errUnsupportedCipherSuite = errors.New("invalid TLS cipher suite")
 	errTLSPEMAndFileEmpty     = errors.New("both PEM and file are empty")
+	errInvalidPinnedSHA256    = errors.New(
+		"pinned public key must be a base64-encoded SHA-256 hash",
+	)
+	errTLSReloadIntervalNegative = errors.New("reloadInterval must not be negative")
+	errCertificatePinMismatch    = errors.New(
+		"none of the server certificates match the pinned public keys",
+	)
 )
 
 // TLSClientCertificate represents a cert and key pair certificate.
@@ -136,6 +147,29 @@ type TLSConfig struct {
 	// This sets the ServerName in the TLSConfig. Please refer to
 	// https://godoc.org/crypto/tls#Config for more information. (optional)
 	ServerName *goenvconf.EnvString `json:"serverName,omitempty" yaml:"serverName,omitempty"`
+	// PinnedSHA256 contains base64-encoded SHA-256 hashes of the subject public key info (SPKI) of trusted server certificates.
+	// If set, the connection is rejected unless a certificate in the server chain matches one of the hashes.
+	PinnedSHA256 []string `json:"pinnedSha256,omitempty" yaml:"pinnedSha256,omitempty"`
+	// ReloadInterval enables reloading client certificate and root CA files when they change on disk.
+	// Files are checked on TLS handshakes at most once per interval. Disabled if empty or zero.
+	ReloadInterval *goutils.Duration `json:"reloadInterval,omitempty" jsonschema:"oneof_ref=#/$defs/Duration,oneof_type=null" yaml:"reloadInterval,omitempty"`
+}
+
+// IsZero checks if the TLS configuration is empty.
+func (tc *TLSConfig) IsZero() bool {
+	return len(tc.RootCAFile) == 0 &&
+		len(tc.RootCAPem) == 0 &&
+		len(tc.CAFile) == 0 &&
+		len(tc.CAPem) == 0 &&
+		len(tc.Certificates) == 0 &&
+		tc.InsecureSkipVerify == nil &&
+		tc.IncludeSystemCACertsPool == nil &&
+		tc.MinVersion == "" &&
+		tc.MaxVersion == "" &&
+		len(tc.CipherSuites) == 0 &&
+		tc.ServerName == nil &&
+		len(tc.PinnedSHA256) == 0 &&
+		tc.ReloadInterval == nil
 }
 
 // Equal checks if this instance equals the target.
@@ -150,7 +184,9 @@ func (tc TLSConfig) Equal(target TLSConfig) bool {
 		goutils.EqualSlice(tc.RootCAPem, target.RootCAPem, true) &&
 		goutils.EqualSlice(tc.CAFile, target.CAFile, true) &&
 		goutils.EqualSlice(tc.CAPem, target.CAPem, true) &&
-		goutils.EqualSlice(tc.Certificates, target.Certificates, true)
+		goutils.EqualSlice(tc.Certificates, target.Certificates, true) &&
+		goutils.EqualSliceSorted(tc.PinnedSHA256, target.PinnedSHA256) &&
+		goutils.EqualComparablePtr(tc.ReloadInterval, target.ReloadInterval)
 }
 
 // Validate if the current instance is valid.
@@ -188,7 +224,13 @@ func (tc TLSConfig) Validate() error {
 		}
 	}
 
-	return nil
+	if tc.ReloadInterval != nil && *tc.ReloadInterval < 0 {
+		return errTLSReloadIntervalNegative
+	}
+
+	_, err = decodePinnedSHA256(tc.PinnedSHA256)
+
+	return err
 }
 
 // GetMinVersion parses the minx TLS version from string.
@@ -253,9 +295,43 @@ func (tc TLSConfig) convertTLSVersion(v string, defaultVersion uint16) (uint16,
 	return val, nil
 }
 
+// chainVerifyPeerCertificate runs the peer certificate verifiers in order and stops at the first error.
+func chainVerifyPeerCertificate(
+	verifiers ...gohttpc.VerifyPeerCertificateFunc,
+) gohttpc.VerifyPeerCertificateFunc {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		for _, verify := range verifiers {
+			if verify == nil {
+				continue
+			}
+
+			err := verify(rawCerts, verifiedChains)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
 // loadTLSConfig loads TLS certificates and returns a tls.Config.
 // This will set the RootCAs and Certificates of a tls.Config.
-func loadTLSConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
+func loadTLSConfig(
+	tlsConfig *TLSConfig,
+	verifiers ...gohttpc.VerifyPeerCertificateFunc,
+) (*tls.Config, error) {
+	result, _, err := loadTLSClientConfig(tlsConfig, verifiers...)
+
+	return result, err
+}
+
+// loadTLSClientConfig loads TLS certificates and returns a tls.Config with the certificate reloader if enabled.
+// Custom peer certificate verifiers run after the certificate pins.
+func loadTLSClientConfig(
+	tlsConfig *TLSConfig,
+	verifiers ...gohttpc.VerifyPeerCertificateFunc,
+) (*tls.Config, *tlsCertificateReloader, error) {
 	var (
 		insecureSkipVerify bool
 		err                error
@@ -264,28 +340,28 @@ func loadTLSConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
 	if tlsConfig.InsecureSkipVerify != nil {
 		insecureSkipVerify, err = tlsConfig.InsecureSkipVerify.GetOrDefault(false)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse insecureSkipVerify: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse insecureSkipVerify: %w", err)
 		}
 	}
 
 	certPool, err := loadSystemCACertPool(tlsConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	minTLS, err := tlsConfig.GetMinVersion()
 	if err != nil {
-		return nil, fmt.Errorf("minVersion: %w", err)
+		return nil, nil, fmt.Errorf("minVersion: %w", err)
 	}
 
 	maxTLS, err := tlsConfig.GetMaxVersion()
 	if err != nil {
-		return nil, fmt.Errorf("maxVersion: %w", err)
+		return nil, nil, fmt.Errorf("maxVersion: %w", err)
 	}
 
 	cipherSuites, err := convertCipherSuites(tlsConfig.CipherSuites)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var serverName string
@@ -293,7 +369,7 @@ func loadTLSConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
 	if tlsConfig.ServerName != nil {
 		serverName, err = tlsConfig.ServerName.GetOrDefault("")
 		if err != nil {
-			return nil, fmt.Errorf("failed to get TLS server name: %w", err)
+			return nil, nil, fmt.Errorf("failed to get TLS server name: %w", err)
 		}
 	}
 
@@ -306,7 +382,102 @@ func loadTLSConfig(tlsConfig *TLSConfig) (*tls.Config, error) {
 		InsecureSkipVerify: insecureSkipVerify, //nolint:gosec
 	}
 
-	return result, addTLSCertificates(result, tlsConfig)
+	if len(tlsConfig.PinnedSHA256) > 0 {
+		pins, err := decodePinnedSHA256(tlsConfig.PinnedSHA256)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result.VerifyPeerCertificate = newPinnedCertificateVerifier(pins)
+	}
+
+	if len(verifiers) > 0 {
+		result.VerifyPeerCertificate = chainVerifyPeerCertificate(
+			append([]gohttpc.VerifyPeerCertificateFunc{result.VerifyPeerCertificate}, verifiers...)...,
+		)
+	}
+
+	err = addTLSCertificates(result, tlsConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reloader *tlsCertificateReloader
+
+	if tlsConfig.ReloadInterval != nil && *tlsConfig.ReloadInterval > 0 {
+		reloader, err = newTLSCertificateReloader(tlsConfig, result, time.Duration(*tlsConfig.ReloadInterval))
+		if err != nil {
+			return nil, nil, fmt.Errorf("reloadInterval: %w", err)
+		}
+	}
+
+	return result, reloader, nil
+}
+
+func decodePinnedSHA256(values []string) ([][]byte, error) {
+	pins := make([][]byte, 0, len(values))
+
+	for i, value := range values {
+		pin, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(pin) != sha256.Size {
+			return nil, fmt.Errorf("pinnedSha256[%d]: %w", i, errInvalidPinnedSHA256)
+		}
+
+		pins = append(pins, pin)
+	}
+
+	return pins, nil
+}
+
+// newPinnedCertificateVerifier creates a callback which accepts the connection
+// if the SPKI hash of any certificate in the verified chains matches a pinned hash.
+// The callback runs after the standard chain verification. The raw certificates are sent by the server
+// and may contain any public certificate, so only the leaf is checked if the chain isn't verified,
+// e.g. when InsecureSkipVerify is enabled.
+func newPinnedCertificateVerifier(
+	pins [][]byte,
+) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(verifiedChains) == 0 {
+			if len(rawCerts) == 0 {
+				return errCertificatePinMismatch
+			}
+
+			leaf, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return err
+			}
+
+			if isPinnedCertificate(leaf, pins) {
+				return nil
+			}
+
+			return errCertificatePinMismatch
+		}
+
+		for _, chain := range verifiedChains {
+			for _, cert := range chain {
+				if isPinnedCertificate(cert, pins) {
+					return nil
+				}
+			}
+		}
+
+		return errCertificatePinMismatch
+	}
+}
+
+// isPinnedCertificate checks if the SPKI hash of the certificate matches any pinned hash.
+func isPinnedCertificate(cert *x509.Certificate, pins [][]byte) bool {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	for _, pin := range pins {
+		if subtle.ConstantTimeCompare(hash[:], pin) == 1 {
+			return true
+		}
+	}
+
+	return false
 }
 
 func loadSystemCACertPool(tlsConfig *TLSConfig) (*x509.CertPool, error) {
@@ -0,0 +1,381 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/relychan/gohttpc"
+)
+
+var (
+	errNoPeerCertificates    = errors.New("tls: the server did not present any certificate")
+	errTLSServerNameRequired = errors.New(
+		"tls: the server name is required to verify the server certificate",
+	)
+)
+
+// tlsFileState stores the modification state of a watched file.
+type tlsFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// tlsCertificateReloader reloads client certificates and root CAs from files when they change.
+// Files are polled lazily on TLS handshakes at most once per interval, so no background goroutine is required.
+type tlsCertificateReloader struct {
+	config       *TLSConfig
+	interval     time.Duration
+	verifyRoots  bool
+	serverName   string
+	lastCheck    atomic.Int64
+	certificates atomic.Pointer[[]tls.Certificate]
+	rootCAs      atomic.Pointer[x509.CertPool]
+
+	// verifyPeerCertificate runs after the server certificate chain is verified against the latest root CAs.
+	verifyPeerCertificate gohttpc.VerifyPeerCertificateFunc
+
+	lock  sync.Mutex
+	files map[string]tlsFileState
+}
+
+// newTLSCertificateReloader creates a reloader and installs its callbacks to the TLS config.
+func newTLSCertificateReloader(
+	config *TLSConfig,
+	tlsConfig *tls.Config,
+	interval time.Duration,
+) (*tlsCertificateReloader, error) {
+	reloader := &tlsCertificateReloader{
+		config:   config,
+		interval: interval,
+		// Root CAs are verified manually to use the latest pool.
+		verifyRoots: len(config.RootCAFile) > 0 && !tlsConfig.InsecureSkipVerify,
+		serverName:  tlsConfig.ServerName,
+	}
+
+	files, err := reloader.statFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	reloader.files = files
+	reloader.lastCheck.Store(time.Now().UnixNano())
+	certificates := tlsConfig.Certificates
+
+	reloader.certificates.Store(&certificates)
+	reloader.rootCAs.Store(tlsConfig.RootCAs)
+
+	tlsConfig.Certificates = nil
+	tlsConfig.GetClientCertificate = reloader.getClientCertificate
+
+	if reloader.verifyRoots {
+		// Custom verifiers receive the verified chains after the manual verification.
+		reloader.verifyPeerCertificate = tlsConfig.VerifyPeerCertificate
+
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec
+		tlsConfig.VerifyPeerCertificate = nil
+		tlsConfig.VerifyConnection = reloader.verifyConnection
+	}
+
+	return reloader, nil
+}
+
+// getClientCertificate returns the latest client certificate which is supported by the server.
+func (tr *tlsCertificateReloader) getClientCertificate(
+	info *tls.CertificateRequestInfo,
+) (*tls.Certificate, error) {
+	tr.reloadIfChanged()
+
+	certificates := *tr.certificates.Load()
+
+	for i := range certificates {
+		if info.SupportsCertificate(&certificates[i]) == nil {
+			return &certificates[i], nil
+		}
+	}
+
+	if len(certificates) > 0 {
+		return &certificates[0], nil
+	}
+
+	// No certificate is sent to the server.
+	return &tls.Certificate{}, nil
+}
+
+// verifyConnection verifies the server certificate chain against the latest root CAs.
+// The connection state doesn't contain the server name of IP hosts,
+// so connections dialed by dialTLSContext verify with the dial host instead.
+func (tr *tlsCertificateReloader) verifyConnection(state tls.ConnectionState) error {
+	serverName := tr.serverName
+	if serverName == "" {
+		serverName = state.ServerName
+	}
+
+	return tr.verify(state, serverName)
+}
+
+// verify verifies the server certificate chain against the latest root CAs and the server name,
+// then runs custom verifiers with the verified chains.
+func (tr *tlsCertificateReloader) verify(state tls.ConnectionState, serverName string) error {
+	tr.reloadIfChanged()
+
+	if len(state.PeerCertificates) == 0 {
+		return errNoPeerCertificates
+	}
+
+	if serverName == "" {
+		return errTLSServerNameRequired
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	verifiedChains, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         tr.rootCAs.Load(),
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return err
+	}
+
+	if tr.verifyPeerCertificate == nil {
+		return nil
+	}
+
+	rawCerts := make([][]byte, len(state.PeerCertificates))
+
+	for i, cert := range state.PeerCertificates {
+		rawCerts[i] = cert.Raw
+	}
+
+	return tr.verifyPeerCertificate(rawCerts, verifiedChains)
+}
+
+// dialTLSContext returns a TLS dialer of the transport which verifies the server certificate
+// with the configured server name, or the dial host if it is empty.
+func (tr *tlsCertificateReloader) dialTLSContext(
+	transport *http.Transport,
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		config := transport.TLSClientConfig.Clone()
+
+		// Transports cloned to override the server name share this dialer.
+		if serverName := gohttpc.ServerNameFromContext(ctx); serverName != "" {
+			config.ServerName = serverName
+		}
+
+		if config.ServerName == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+
+			config.ServerName = host
+		}
+
+		serverName := config.ServerName
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return tr.verify(state, serverName)
+		}
+
+		if transport.TLSHandshakeTimeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, transport.TLSHandshakeTimeout)
+			defer cancel()
+		}
+
+		tlsConn := tls.Client(conn, config)
+
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		return tlsConn, nil
+	}
+}
+
+// reloadIfChanged reloads certificates if any watched file was changed since the last check.
+// The previous certificates are kept if the reload fails.
+func (tr *tlsCertificateReloader) reloadIfChanged() {
+	now := time.Now()
+
+	if now.UnixNano()-tr.lastCheck.Load() < tr.interval.Nanoseconds() {
+		return
+	}
+
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+
+	// Another handshake may have checked files while waiting for the lock.
+	if now.UnixNano()-tr.lastCheck.Load() < tr.interval.Nanoseconds() {
+		return
+	}
+
+	tr.lastCheck.Store(now.UnixNano())
+
+	files, err := tr.statFiles()
+	if err != nil {
+		slog.Warn("failed to check TLS certificate files: " + err.Error())
+
+		return
+	}
+
+	if !tr.isChanged(files) {
+		return
+	}
+
+	err = tr.reload()
+	if err != nil {
+		slog.Warn("failed to reload TLS certificates: " + err.Error())
+
+		return
+	}
+
+	tr.files = files
+}
+
+func (tr *tlsCertificateReloader) reload() error {
+	tlsConfig := &tls.Config{} //nolint:gosec
+
+	if tr.verifyRoots {
+		certPool, err := loadSystemCACertPool(tr.config)
+		if err != nil {
+			return err
+		}
+
+		err = addTLSCertPoolCAs(certPool, tr.config.RootCAPem, tr.config.RootCAFile)
+		if err != nil {
+			return fmt.Errorf("RootCAs: %w", err)
+		}
+
+		tlsConfig.RootCAs = certPool
+	}
+
+	err := addTLSClientCertificates(tlsConfig, tr.config.Certificates)
+	if err != nil {
+		return err
+	}
+
+	certificates := tlsConfig.Certificates
+
+	tr.certificates.Store(&certificates)
+
+	if tlsConfig.RootCAs != nil {
+		tr.rootCAs.Store(tlsConfig.RootCAs)
+	}
+
+	return nil
+}
+
+func (tr *tlsCertificateReloader) isChanged(files map[string]tlsFileState) bool {
+	if len(files) != len(tr.files) {
+		return true
+	}
+
+	for name, state := range files {
+		previous, ok := tr.files[name]
+		if !ok || previous.size != state.size || !previous.modTime.Equal(state.modTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statFiles gets the state of certificate, key and root CA files.
+func (tr *tlsCertificateReloader) statFiles() (map[string]tlsFileState, error) {
+	var paths []string
+
+	for _, cert := range tr.config.Certificates {
+		if cert.CertFile != nil {
+			certFile, err := cert.CertFile.GetOrDefault("")
+			if err != nil {
+				return nil, err
+			}
+
+			paths = append(paths, certFile)
+		}
+
+		if cert.KeyFile != nil {
+			keyFile, err := cert.KeyFile.GetOrDefault("")
+			if err != nil {
+				return nil, err
+			}
+
+			paths = append(paths, keyFile)
+		}
+	}
+
+	if tr.verifyRoots {
+		for _, fileEnv := range tr.config.RootCAFile {
+			caFile, err := fileEnv.GetOrDefault("")
+			if err != nil {
+				return nil, err
+			}
+
+			paths = append(paths, caFile)
+		}
+	}
+
+	files := make(map[string]tlsFileState, len(paths))
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+
+		files[path] = tlsFileState{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,383 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+// writeClientCertificate writes a self-signed client certificate and key pair to files.
+func writeClientCertificate(t *testing.T, certFile string, keyFile string, commonName string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newReloadingTransport creates a transport which verifies server certificates with the certificate reloader.
+func newReloadingTransport(t *testing.T, config *TLSConfig) *http.Transport {
+	t.Helper()
+
+	tlsConfig, reloader, err := loadTLSClientConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	transport.DialTLSContext = reloader.dialTLSContext(transport)
+
+	return transport
+}
+
+// newHostCertificate creates a self-signed server certificate which is valid for the DNS name only.
+func newHostCertificate(t *testing.T, dnsName string) (tls.Certificate, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: dnsName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{dnsName},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+	}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+func TestTLSConfig_ReloadInterval(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	rootCAFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+
+	err := os.WriteFile(
+		rootCAFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		0o600,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeClientCertificate(t, certFile, keyFile, "client-1")
+
+	reloadInterval := goutils.Duration(time.Millisecond)
+	certFileEnv := goenvconf.NewEnvStringValue(certFile)
+	keyFileEnv := goenvconf.NewEnvStringValue(keyFile)
+
+	transport := newReloadingTransport(t, &TLSConfig{
+		RootCAFile: []goenvconf.EnvString{goenvconf.NewEnvStringValue(rootCAFile)},
+		Certificates: []TLSClientCertificate{
+			{
+				CertFile: &certFileEnv,
+				KeyFile:  &keyFileEnv,
+			},
+		},
+		ReloadInterval: &reloadInterval,
+	})
+	transport.DisableKeepAlives = true
+
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	assertPresentedCertificate := func(expected string) {
+		t.Helper()
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+
+		if string(body[:n]) != expected {
+			t.Errorf("expected the client certificate %s, got: %s", expected, body[:n])
+		}
+	}
+
+	assertPresentedCertificate("client-1")
+
+	writeClientCertificate(t, certFile, keyFile, "client-2")
+
+	// Make sure the modification time changes on file systems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+
+	for _, file := range []string{certFile, keyFile} {
+		err = os.Chtimes(file, future, future)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	assertPresentedCertificate("client-2")
+
+	t.Run("keeps the previous certificate if the reload fails", func(t *testing.T) {
+		err := os.WriteFile(certFile, []byte("invalid"), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		assertPresentedCertificate("client-2")
+	})
+
+	t.Run("rejects untrusted servers", func(t *testing.T) {
+		untrustedCAFile := filepath.Join(dir, "untrusted.pem")
+
+		writeClientCertificate(t, untrustedCAFile, filepath.Join(dir, "untrusted.key"), "untrusted")
+
+		untrustedClient := &http.Client{
+			Transport: newReloadingTransport(t, &TLSConfig{
+				RootCAFile:     []goenvconf.EnvString{goenvconf.NewEnvStringValue(untrustedCAFile)},
+				ReloadInterval: &reloadInterval,
+			}),
+		}
+
+		resp, err := untrustedClient.Get(server.URL)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		var unknownAuthorityErr x509.UnknownAuthorityError
+
+		if !errors.As(err, &unknownAuthorityErr) {
+			t.Errorf("expected unknown authority error, got: %v", err)
+		}
+	})
+}
+
+func TestTLSConfig_ReloadIntervalVerifyServer(t *testing.T) {
+	ipCert, ipPem := newServerCertificate(t)
+	hostCert, hostPem := newHostCertificate(t, "example.com")
+	reloadInterval := goutils.Duration(time.Minute)
+
+	var verifiedChains atomic.Int32
+
+	verifyPeerCertificate := func(_ [][]byte, chains [][]*x509.Certificate) error {
+		if len(chains) == 0 {
+			return errors.New("expected verified chains")
+		}
+
+		verifiedChains.Add(1)
+
+		return nil
+	}
+
+	testCases := []struct {
+		Name          string
+		Certificate   tls.Certificate
+		RootCAPem     []byte
+		ServerName    string
+		SNI           string
+		ExpectedCalls int32
+		Validate      func(t *testing.T, err error)
+	}{
+		{
+			Name:          "ip_host",
+			Certificate:   ipCert,
+			RootCAPem:     ipPem,
+			ExpectedCalls: 1,
+			Validate: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got: %s", err)
+				}
+			},
+		},
+		{
+			Name:        "ip_host_mismatch",
+			Certificate: hostCert,
+			RootCAPem:   hostPem,
+			Validate: func(t *testing.T, err error) {
+				var hostnameErr x509.HostnameError
+				if !errors.As(err, &hostnameErr) {
+					t.Errorf("expected x509.HostnameError, got: %v", err)
+				}
+			},
+		},
+		{
+			Name:          "request_server_name",
+			Certificate:   hostCert,
+			RootCAPem:     hostPem,
+			SNI:           "example.com",
+			ExpectedCalls: 1,
+			Validate: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got: %s", err)
+				}
+			},
+		},
+		{
+			Name:          "configured_server_name",
+			Certificate:   hostCert,
+			RootCAPem:     hostPem,
+			ServerName:    "example.com",
+			ExpectedCalls: 1,
+			Validate: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got: %s", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			verifiedChains.Store(0)
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{tc.Certificate}}
+			server.StartTLS()
+			defer server.Close()
+
+			rootCAFile := filepath.Join(t.TempDir(), "ca.pem")
+
+			err := os.WriteFile(rootCAFile, tc.RootCAPem, 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tlsConfig := &TLSConfig{
+				RootCAFile:     []goenvconf.EnvString{goenvconf.NewEnvStringValue(rootCAFile)},
+				ReloadInterval: &reloadInterval,
+			}
+
+			if tc.ServerName != "" {
+				serverName := goenvconf.NewEnvStringValue(tc.ServerName)
+				tlsConfig.ServerName = &serverName
+			}
+
+			client, err := NewHTTPClientFromConfig(
+				&HTTPClientConfig{
+					TLS: tlsConfig,
+				},
+				gohttpc.NewClientOptions(gohttpc.WithVerifyPeerCertificate(verifyPeerCertificate)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.CloseIdleConnections()
+
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var resp *http.Response
+
+			if tc.SNI != "" {
+				resp, err = gohttpc.DoWithServerName(client, req, tc.SNI)
+			} else {
+				resp, err = client.Do(req)
+			}
+
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			tc.Validate(t, err)
+
+			if verifiedChains.Load() != tc.ExpectedCalls {
+				t.Errorf("expected %d verifier calls, got: %d", tc.ExpectedCalls, verifiedChains.Load())
+			}
+		})
+	}
+}
@@ -15,14 +15,30 @@
 package httpconfig
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
+	"encoding/pem"
 	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
 )
 
 func TestTLSClientCertificate_IsZero(t *testing.T) {
@@ -355,6 +371,144 @@ func TestTLSConfig_Validate(t *testing.T) {
 			t.Errorf("expected errCertificateRequireEitherFileOrPEM, got %v", err)
 		}
 	})
+
+	t.Run("returns error when pinned hash is invalid", func(t *testing.T) {
+		for _, pin := range []string{"not-base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+			config := TLSConfig{
+				PinnedSHA256: []string{pin},
+			}
+
+			err := config.Validate()
+			if !errors.Is(err, errInvalidPinnedSHA256) {
+				t.Errorf("expected errInvalidPinnedSHA256 for %q, got %v", pin, err)
+			}
+		}
+	})
+}
+
+func TestTLSConfig_ValidateReloadInterval(t *testing.T) {
+	reloadInterval := goutils.Duration(-time.Second)
+	config := TLSConfig{
+		ReloadInterval: &reloadInterval,
+	}
+
+	err := config.Validate()
+	if !errors.Is(err, errTLSReloadIntervalNegative) {
+		t.Errorf("expected errTLSReloadIntervalNegative, got %v", err)
+	}
+}
+
+func TestTLSConfig_IsZero(t *testing.T) {
+	if !(&TLSConfig{}).IsZero() {
+		t.Error("expected IsZero to return true")
+	}
+
+	if (&TLSConfig{PinnedSHA256: []string{"pin"}}).IsZero() {
+		t.Error("expected IsZero to return false")
+	}
+}
+
+func TestTLSConfig_PinnedSHA256(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverCert := server.Certificate()
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Raw})
+	spkiHash := sha256.Sum256(serverCert.RawSubjectPublicKeyInfo)
+	otherHash := sha256.Sum256([]byte("other public key"))
+
+	testCases := []struct {
+		Name      string
+		Pins      []string
+		ExpectErr bool
+	}{
+		{
+			Name: "matched_pin",
+			Pins: []string{
+				base64.StdEncoding.EncodeToString(otherHash[:]),
+				base64.StdEncoding.EncodeToString(spkiHash[:]),
+			},
+		},
+		{
+			Name:      "unmatched_pin",
+			Pins:      []string{base64.StdEncoding.EncodeToString(otherHash[:])},
+			ExpectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tlsConfig, err := loadTLSConfig(&TLSConfig{
+				RootCAPem:    []goenvconf.EnvString{goenvconf.NewEnvStringValue(base64.StdEncoding.EncodeToString(certPem))},
+				PinnedSHA256: tc.Pins,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: tlsConfig,
+				},
+			}
+			defer client.CloseIdleConnections()
+
+			resp, err := client.Get(server.URL)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.ExpectErr {
+				if err != nil {
+					t.Fatalf("expected no error, got: %s", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, errCertificatePinMismatch) {
+				t.Errorf("expected errCertificatePinMismatch, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig_RootCAPemFromEnv(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	t.Setenv("TEST_TLS_ROOT_CA_PEM", base64.StdEncoding.EncodeToString(certPem))
+
+	tlsConfig, err := loadTLSConfig(&TLSConfig{
+		RootCAPem: []goenvconf.EnvString{goenvconf.NewEnvStringVariable("TEST_TLS_ROOT_CA_PEM")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = server.Certificate().Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs})
+	if err != nil {
+		t.Fatalf("expected the root CA pool to trust the server certificate, got: %s", err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got: %s", err)
+	}
+
+	_ = resp.Body.Close()
 }
 
 func TestLoadCertificateString(t *testing.T) {
@@ -724,4 +878,229 @@ func TestTLSConfig_Equal(t *testing.T) {
 			t.Error("expected Equal to return false")
 		}
 	})
+
+	t.Run("returns false for different PinnedSHA256", func(t *testing.T) {
+		config1 := TLSConfig{
+			PinnedSHA256: []string{"pin1"},
+		}
+		config2 := TLSConfig{
+			PinnedSHA256: []string{"pin2"},
+		}
+
+		if config1.Equal(config2) {
+			t.Error("expected Equal to return false for different PinnedSHA256")
+		}
+	})
+}
+
+func TestTLSConfig_PinnedSHA256_UntrustedChainCertificate(t *testing.T) {
+	pinnedCert, _ := newServerCertificate(t)
+	attackerCert, attackerPem := newServerCertificate(t)
+
+	pinnedLeaf, err := x509.ParseCertificate(pinnedCert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinHash := sha256.Sum256(pinnedLeaf.RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(pinHash[:])
+
+	// The attacker appends the public pinned certificate to its own chain.
+	attackerCert.Certificate = append(attackerCert.Certificate, pinnedCert.Certificate[0])
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{attackerCert}} //nolint:gosec
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		Name   string
+		Config *TLSConfig
+	}{
+		{
+			Name: "verified_chain",
+			Config: &TLSConfig{
+				RootCAPem:    []goenvconf.EnvString{goenvconf.NewEnvStringValue(base64.StdEncoding.EncodeToString(attackerPem))},
+				PinnedSHA256: []string{pin},
+			},
+		},
+		{
+			Name: "insecure_skip_verify",
+			Config: &TLSConfig{
+				InsecureSkipVerify: new(goenvconf.NewEnvBoolValue(true)),
+				PinnedSHA256:       []string{pin},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tlsConfig, err := loadTLSConfig(tc.Config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: tlsConfig,
+				},
+			}
+			defer client.CloseIdleConnections()
+
+			resp, err := client.Get(server.URL)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			if !errors.Is(err, errCertificatePinMismatch) {
+				t.Errorf("expected errCertificatePinMismatch, got: %v", err)
+			}
+		})
+	}
+}
+
+// newServerCertificate creates a self-signed server certificate for 127.0.0.1 with extra extensions.
+func newServerCertificate(t *testing.T, extensions ...pkix.Extension) (tls.Certificate, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		ExtraExtensions:       extensions,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+	}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+func TestWithVerifyPeerCertificate(t *testing.T) {
+	requiredOID := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+	errMissingExtension := errors.New("missing required extension")
+
+	var hookCalls atomic.Int32
+
+	verifyExtension := func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		hookCalls.Add(1)
+
+		if len(verifiedChains) == 0 {
+			return errors.New("expected verified chains")
+		}
+
+		for _, ext := range verifiedChains[0][0].Extensions {
+			if ext.Id.Equal(requiredOID) {
+				return nil
+			}
+		}
+
+		return errMissingExtension
+	}
+
+	trustedCert, trustedPem := newServerCertificate(t, pkix.Extension{
+		Id:    requiredOID,
+		Value: []byte{0x05, 0x00},
+	})
+	missingExtCert, missingExtPem := newServerCertificate(t)
+	_, untrustedPem := newServerCertificate(t)
+
+	testCases := []struct {
+		Name          string
+		Certificate   tls.Certificate
+		RootCAPem     []byte
+		ExpectedCalls int32
+		Validate      func(t *testing.T, err error)
+	}{
+		{
+			Name:          "with_extension",
+			Certificate:   trustedCert,
+			RootCAPem:     trustedPem,
+			ExpectedCalls: 1,
+			Validate: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got: %s", err)
+				}
+			},
+		},
+		{
+			Name:          "missing_extension",
+			Certificate:   missingExtCert,
+			RootCAPem:     missingExtPem,
+			ExpectedCalls: 1,
+			Validate: func(t *testing.T, err error) {
+				if !errors.Is(err, errMissingExtension) {
+					t.Errorf("expected errMissingExtension, got: %v", err)
+				}
+			},
+		},
+		{
+			Name:        "untrusted_certificate",
+			Certificate: trustedCert,
+			RootCAPem:   untrustedPem,
+			Validate: func(t *testing.T, err error) {
+				var unknownAuthorityErr x509.UnknownAuthorityError
+				if !errors.As(err, &unknownAuthorityErr) {
+					t.Errorf("expected x509.UnknownAuthorityError, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hookCalls.Store(0)
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{Certificates: []tls.Certificate{tc.Certificate}}
+			server.StartTLS()
+			defer server.Close()
+
+			client, err := NewHTTPClientFromConfig(
+				&HTTPClientConfig{
+					TLS: &TLSConfig{
+						RootCAPem: []goenvconf.EnvString{
+							goenvconf.NewEnvStringValue(base64.StdEncoding.EncodeToString(tc.RootCAPem)),
+						},
+					},
+				},
+				gohttpc.NewClientOptions(gohttpc.WithVerifyPeerCertificate(verifyExtension)),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.CloseIdleConnections()
+
+			resp, err := client.Get(server.URL)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			tc.Validate(t, err)
+
+			if hookCalls.Load() != tc.ExpectedCalls {
+				t.Errorf("expected %d hook calls, got: %d", tc.ExpectedCalls, hookCalls.Load())
+			}
+		})
+	}
 }
@@ -21,7 +21,9 @@ import (
 	"path/filepath"
 
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"github.com/relychan/gohttpc/authc/awssigv4"
 	"github.com/relychan/gohttpc/authc/basicauth"
+	"github.com/relychan/gohttpc/authc/digestauth"
 	"github.com/relychan/gohttpc/authc/httpauth"
 	"github.com/relychan/gohttpc/authc/oauth2scheme"
 	"github.com/relychan/gohttpc/httpconfig"
@@ -60,7 +62,10 @@ func jsonSchemaConfiguration() error { //nolint:funlen
 		httpauth.HTTPAuthConfig{},
 		authscheme.TokenLocation{},
 		oauth2scheme.OAuth2Config{},
+		awssigv4.AWSSigV4Config{},
+		digestauth.DigestAuthConfig{},
 		loadbalancer.HTTPHealthCheckConfig{},
+		httpconfig.LoadBalancerConfig{},
 	} {
 		externalSchema := r.Reflect(externalType)
 
@@ -92,6 +97,14 @@ func jsonSchemaConfiguration() error { //nolint:funlen
 				Description: "Configuration for the OAuth2 authentication",
 				Ref:         "#/$defs/OAuth2Config",
 			},
+			{
+				Description: "Configuration for the AWS Signature Version 4 authentication",
+				Ref:         "#/$defs/AWSSigV4Config",
+			},
+			{
+				Description: "Configuration for the HTTP Digest authentication",
+				Ref:         "#/$defs/DigestAuthConfig",
+			},
 		},
 	}
 
@@ -0,0 +1,135 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package composite implements a load balancer which dispatches requests
+// to sub load balancers by the balancer hint of the request.
+package composite
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/relychan/gohttpc/loadbalancer"
+)
+
+// Composite represents the load balancer which selects the load-balancing strategy
+// for each request by the balancer hint, e.g. cache-affinity for reads and round-robin for writes.
+// Requests without a hint, or with an unknown hint, are dispatched to the default load balancer.
+type Composite struct {
+	defaultBalancer loadbalancer.LoadBalancer
+	balancers       map[string]loadbalancer.LoadBalancer
+}
+
+var _ loadbalancer.HintedLoadBalancer = (*Composite)(nil)
+
+// NewComposite creates a new composite load balancer with the default load balancer
+// and sub load balancers keyed by the balancer hint. Sub load balancers may share hosts.
+func NewComposite(
+	defaultBalancer loadbalancer.LoadBalancer,
+	balancers map[string]loadbalancer.LoadBalancer,
+) *Composite {
+	return &Composite{
+		defaultBalancer: defaultBalancer,
+		balancers:       balancers,
+	}
+}
+
+// ForHint returns the load balancer for the hint, or the default load balancer if the hint is unknown.
+func (c *Composite) ForHint(hint string) loadbalancer.LoadBalancer {
+	if balancer, ok := c.balancers[hint]; ok && balancer != nil {
+		return balancer
+	}
+
+	return c.defaultBalancer
+}
+
+// Next returns the next host of the default load balancer.
+func (c *Composite) Next() (*loadbalancer.Host, error) {
+	if c.defaultBalancer == nil {
+		return nil, loadbalancer.ErrNoActiveHost
+	}
+
+	return c.defaultBalancer.Next()
+}
+
+// Hosts return the unique list of hosts of all load balancers.
+func (c *Composite) Hosts() []*loadbalancer.Host {
+	var hosts []*loadbalancer.Host
+
+	seen := map[*loadbalancer.Host]bool{}
+
+	for _, balancer := range c.uniqueBalancers() {
+		for _, host := range balancer.Hosts() {
+			if seen[host] {
+				continue
+			}
+
+			seen[host] = true
+
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// StartHealthCheck starts health checking of all load balancers and blocks until the context is done.
+func (c *Composite) StartHealthCheck(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, balancer := range c.uniqueBalancers() {
+		wg.Go(func() {
+			balancer.StartHealthCheck(ctx)
+		})
+	}
+
+	wg.Wait()
+}
+
+// Close closes all load balancers.
+func (c *Composite) Close() error {
+	var errs []error
+
+	for _, balancer := range c.uniqueBalancers() {
+		err := balancer.Close()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// uniqueBalancers returns the default and sub load balancers without duplicates.
+func (c *Composite) uniqueBalancers() []loadbalancer.LoadBalancer {
+	results := make([]loadbalancer.LoadBalancer, 0, len(c.balancers)+1)
+
+	if c.defaultBalancer != nil {
+		results = append(results, c.defaultBalancer)
+	}
+
+	for _, hint := range slices.Sorted(maps.Keys(c.balancers)) {
+		balancer := c.balancers[hint]
+		if balancer == nil || slices.Contains(results, balancer) {
+			continue
+		}
+
+		results = append(results, balancer)
+	}
+
+	return results
+}
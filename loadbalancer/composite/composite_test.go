@@ -0,0 +1,138 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/gohttpc/loadbalancer/consistenthash"
+	"github.com/relychan/gohttpc/loadbalancer/roundrobin"
+	"github.com/relychan/goutils"
+)
+
+func newTestHosts(t *testing.T, count int) []*loadbalancer.Host {
+	t.Helper()
+
+	hosts := make([]*loadbalancer.Host, count)
+
+	for i := range count {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Host", fmt.Sprint(i))
+			_, _ = w.Write([]byte("ok"))
+		}))
+		t.Cleanup(server.Close)
+
+		host, err := loadbalancer.NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
+	}
+
+	return hosts
+}
+
+func TestComposite(t *testing.T) {
+	hosts := newTestHosts(t, 3)
+
+	affinity, err := consistenthash.NewConsistentHash(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roundRobin, err := roundrobin.NewWeightedRoundRobin(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	balancer := NewComposite(roundRobin, map[string]loadbalancer.LoadBalancer{
+		"read":  affinity,
+		"write": roundRobin,
+	})
+
+	if len(balancer.Hosts()) != len(hosts) {
+		t.Fatalf("expected %d unique hosts, got: %d", len(hosts), len(balancer.Hosts()))
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(balancer)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	send := func(t *testing.T, ctx context.Context, method string, hint string) string {
+		t.Helper()
+
+		req := client.R(method, "/resource")
+		req.SetBalancerHint(hint)
+
+		resp, err := req.Execute(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		return resp.Header.Get("X-Host")
+	}
+
+	t.Run("reads_use_affinity", func(t *testing.T) {
+		expected, err := affinity.NextWithKey("user-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedIndex := fmt.Sprint(slices.Index(hosts, expected))
+		ctx := loadbalancer.WithAffinityKey(context.Background(), "user-1")
+
+		for range 6 {
+			result := send(t, ctx, http.MethodGet, "read")
+			if result != expectedIndex {
+				t.Errorf("expected reads to be routed to host %s, got: %s", expectedIndex, result)
+			}
+		}
+	})
+
+	t.Run("writes_use_round_robin", func(t *testing.T) {
+		ctx := loadbalancer.WithAffinityKey(context.Background(), "user-1")
+		selected := map[string]int{}
+
+		for range 6 {
+			selected[send(t, ctx, http.MethodPost, "write")]++
+		}
+
+		for i := range hosts {
+			if selected[fmt.Sprint(i)] != 2 {
+				t.Errorf("expected writes to be distributed evenly, got: %v", selected)
+			}
+		}
+	})
+
+	t.Run("fallback_to_default", func(t *testing.T) {
+		selected := map[string]int{}
+
+		for _, hint := range []string{"", "unknown", "", "unknown", "", "unknown"} {
+			selected[send(t, context.Background(), http.MethodGet, hint)]++
+		}
+
+		if len(selected) != len(hosts) {
+			t.Errorf("expected requests without hint to use the default balancer, got: %v", selected)
+		}
+	})
+}
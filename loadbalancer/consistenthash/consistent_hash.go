@@ -0,0 +1,303 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package consistenthash implements the consistent hashing load balancing algorithm
+// with soft affinity and spillover to the next hosts on the ring when the preferred host is busy.
+package consistenthash
+
+import (
+	"cmp"
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/goutils"
+)
+
+// DefaultReplicas is the default number of virtual nodes of each host on the hash ring.
+const DefaultReplicas = 100
+
+// ConsistentHash represents the load balancer which maps affinity keys to hosts on a consistent hash ring.
+// Requests with the same key prefer the same host. If the in-flight requests of the preferred host
+// reach the spillover threshold, the request falls to the next host on the ring.
+type ConsistentHash struct {
+	consistentHashOptions
+
+	lock  sync.Mutex
+	hosts []*loadbalancer.Host
+	ring  []ringNode
+	tick  *time.Ticker
+	// closed is true if hosts were closed.
+	closed bool
+}
+
+// ringNode is a virtual node of a host on the hash ring.
+type ringNode struct {
+	hash uint64
+	host *loadbalancer.Host
+}
+
+var _ loadbalancer.KeyedLoadBalancer = (*ConsistentHash)(nil)
+
+// NewConsistentHash creates a new Consistent Hash
+// load balancer instance with the given hosts slice and optional configuration.
+func NewConsistentHash(
+	hosts []*loadbalancer.Host,
+	options ...ConsistentHashOption,
+) (*ConsistentHash, error) {
+	ch := &ConsistentHash{
+		consistentHashOptions: consistentHashOptions{
+			replicas: DefaultReplicas,
+		},
+	}
+
+	for _, opt := range options {
+		opt(&ch.consistentHashOptions)
+	}
+
+	err := ch.Refresh(hosts)
+
+	return ch, err
+}
+
+// Next returns the host for the empty affinity key.
+func (ch *ConsistentHash) Next() (*loadbalancer.Host, error) {
+	return ch.NextWithKey("")
+}
+
+// NextWithKey returns the host for the affinity key.
+// The first available host on the ring, starting from the key position, whose in-flight requests
+// are below the spillover threshold is selected. If all hosts are busy, the least loaded host is selected.
+func (ch *ConsistentHash) NextWithKey(key string) (*loadbalancer.Host, error) {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	switch len(ch.hosts) {
+	case 0:
+		return nil, loadbalancer.ErrNoActiveHost
+	case 1:
+		// Return the only host directly.
+		return ch.hosts[0], nil
+	default:
+		return ch.nextHost(key), nil
+	}
+}
+
+// Refresh resets the existing values with the given [Host] slice to refresh it.
+func (ch *ConsistentHash) Refresh(hosts []*loadbalancer.Host) error {
+	if hosts == nil {
+		return nil
+	}
+
+	ring := make([]ringNode, 0, len(hosts)*ch.replicas)
+
+	for _, host := range hosts {
+		replicas := ch.replicas * max(host.Weight(), 1)
+
+		for i := range replicas {
+			ring = append(ring, ringNode{
+				hash: hashKey(host.Name() + "|" + host.URL() + "#" + strconv.Itoa(i)),
+				host: host,
+			})
+		}
+	}
+
+	slices.SortFunc(ring, func(a, b ringNode) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	ch.hosts = hosts
+	ch.ring = ring
+
+	return nil
+}
+
+// Close method does the cleanup by stopping the [time.Ticker] on the load balancer and closing hosts.
+// It is safe to call Close multiple times.
+func (ch *ConsistentHash) Close() error {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	if ch.tick != nil {
+		ch.tick.Stop()
+		ch.tick = nil
+	}
+
+	if ch.closed {
+		return nil
+	}
+
+	ch.closed = true
+
+	for _, host := range ch.hosts {
+		host.Close()
+	}
+
+	return nil
+}
+
+// Hosts return the list of hosts of the load balancer.
+func (ch *ConsistentHash) Hosts() []*loadbalancer.Host {
+	ch.lock.Lock()
+	defer ch.lock.Unlock()
+
+	return ch.hosts
+}
+
+// StartHealthCheck starts a ticker to run health checking for servers in the background.
+func (ch *ConsistentHash) StartHealthCheck(ctx context.Context) {
+	if ch.healthCheckInterval <= 0 {
+		return
+	}
+
+	newTicker := time.NewTicker(ch.healthCheckInterval)
+
+	ch.lock.Lock()
+
+	if ch.tick != nil {
+		// Stop the ticker of the previous health check.
+		ch.tick.Stop()
+	}
+
+	ch.tick = newTicker
+	ch.lock.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			goutils.CatchWarnErrorFunc(ch.Close)
+
+			return
+		case <-newTicker.C:
+			for _, host := range ch.Hosts() {
+				host.CheckHealth(ctx)
+			}
+		}
+	}
+}
+
+// nextHost walks the ring clockwise from the position of the key and returns the first available host which is not busy.
+func (ch *ConsistentHash) nextHost(key string) *loadbalancer.Host {
+	hash := hashKey(key)
+	start, _ := slices.BinarySearchFunc(ch.ring, hash, func(node ringNode, target uint64) int {
+		return cmp.Compare(node.hash, target)
+	})
+
+	var leastLoaded, fallbackHost *loadbalancer.Host
+
+	visited := make(map[*loadbalancer.Host]bool, len(ch.hosts))
+
+	for i := range ch.ring {
+		if len(visited) == len(ch.hosts) {
+			break
+		}
+
+		h := ch.ring[(start+i)%len(ch.ring)].host
+		if visited[h] {
+			continue
+		}
+
+		visited[h] = true
+
+		policy := h.HealthCheckPolicy()
+		if policy != nil && policy.State() == circuitbreaker.OpenState {
+			// checks if the open state is expired.
+			if !policy.TryAcquirePermit() {
+				_, isOutage := h.GetLastHTTPErrorStatus()
+				if !isOutage && fallbackHost == nil {
+					fallbackHost = h
+				}
+
+				continue
+			}
+		}
+
+		if ch.spilloverThreshold <= 0 || h.ActiveRequests() < ch.spilloverThreshold {
+			return h
+		}
+
+		if leastLoaded == nil || h.ActiveRequests() < leastLoaded.ActiveRequests() {
+			leastLoaded = h
+		}
+	}
+
+	if leastLoaded != nil {
+		return leastLoaded
+	}
+
+	if fallbackHost == nil {
+		fallbackHost = ch.ring[start%len(ch.ring)].host
+	}
+
+	return fallbackHost
+}
+
+// hashKey hashes the key with FNV-1a and mixes the result with the SplitMix64 finalizer
+// because FNV-1a alone distributes keys which only differ in trailing bytes poorly.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	value := h.Sum64()
+	value ^= value >> 30
+	value *= 0xbf58476d1ce4e5b9
+	value ^= value >> 27
+	value *= 0x94d049bb133111eb
+	value ^= value >> 31
+
+	return value
+}
+
+type consistentHashOptions struct {
+	healthCheckInterval time.Duration
+	replicas            int
+	spilloverThreshold  int64
+}
+
+// ConsistentHashOption represents a function to modify the Consistent Hash options.
+type ConsistentHashOption func(*consistentHashOptions)
+
+// WithHealthCheckInterval sets the health check interval for the load balancer.
+func WithHealthCheckInterval(duration time.Duration) ConsistentHashOption {
+	return func(o *consistentHashOptions) {
+		o.healthCheckInterval = max(duration, 0)
+	}
+}
+
+// WithReplicas sets the number of virtual nodes of each host on the hash ring.
+// The number is multiplied by the weight of the host. Defaults to [DefaultReplicas].
+func WithReplicas(replicas int) ConsistentHashOption {
+	return func(o *consistentHashOptions) {
+		if replicas > 0 {
+			o.replicas = replicas
+		}
+	}
+}
+
+// WithSpilloverThreshold sets the maximum number of in-flight requests of the preferred host.
+// When the threshold is reached, requests spill over to the next host on the ring.
+// Zero disables the spillover so requests strictly stick to the preferred host.
+func WithSpilloverThreshold(threshold int64) ConsistentHashOption {
+	return func(o *consistentHashOptions) {
+		o.spilloverThreshold = max(threshold, 0)
+	}
+}
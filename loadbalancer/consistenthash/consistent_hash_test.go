@@ -0,0 +1,337 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistenthash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/goutils"
+)
+
+func newTestHosts(t *testing.T, count int) []*loadbalancer.Host {
+	t.Helper()
+
+	hosts := make([]*loadbalancer.Host, count)
+
+	for i := range count {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Host", fmt.Sprint(i))
+			_, _ = w.Write([]byte("ok"))
+		}))
+		t.Cleanup(server.Close)
+
+		host, err := loadbalancer.NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
+	}
+
+	return hosts
+}
+
+// occupy sends a request to the host without closing the response body to keep it in flight.
+func occupy(t *testing.T, host *loadbalancer.Host) io.Closer {
+	t.Helper()
+
+	req, err := host.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := host.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.Body
+}
+
+func TestConsistentHash_NextWithKey(t *testing.T) {
+	t.Run("returns error when there is no host", func(t *testing.T) {
+		ch, err := NewConsistentHash([]*loadbalancer.Host{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ch.NextWithKey("key")
+		if !errors.Is(err, loadbalancer.ErrNoActiveHost) {
+			t.Errorf("expected ErrNoActiveHost, got: %v", err)
+		}
+	})
+
+	t.Run("keeps affinity under low load", func(t *testing.T) {
+		hosts := newTestHosts(t, 3)
+
+		ch, err := NewConsistentHash(hosts, WithSpilloverThreshold(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		selected := map[*loadbalancer.Host]bool{}
+
+		for i := range 20 {
+			key := fmt.Sprintf("key-%d", i)
+
+			first, err := ch.NextWithKey(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selected[first] = true
+
+			for range 5 {
+				host, err := ch.NextWithKey(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if host != first {
+					t.Fatalf("expected key %s to stick to %s, got: %s", key, first.URL(), host.URL())
+				}
+			}
+		}
+
+		if len(selected) < 2 {
+			t.Errorf("expected keys to be distributed across hosts, got %d host(s)", len(selected))
+		}
+	})
+
+	t.Run("spills over to the next host when the preferred host is busy", func(t *testing.T) {
+		hosts := newTestHosts(t, 3)
+
+		ch, err := NewConsistentHash(hosts, WithSpilloverThreshold(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		preferred, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := occupy(t, preferred)
+
+		spilled, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if spilled == preferred {
+			t.Fatalf("expected the request to spill over from %s", preferred.URL())
+		}
+
+		// The spillover target is stable while the preferred host stays busy.
+		again, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if again != spilled {
+			t.Errorf("expected the same spillover host %s, got: %s", spilled.URL(), again.URL())
+		}
+
+		_ = body.Close()
+
+		host, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != preferred {
+			t.Errorf("expected the key to return to %s, got: %s", preferred.URL(), host.URL())
+		}
+	})
+
+	t.Run("sticks to the preferred host without threshold", func(t *testing.T) {
+		hosts := newTestHosts(t, 3)
+
+		ch, err := NewConsistentHash(hosts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		preferred, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := occupy(t, preferred)
+		defer goutils.CatchWarnErrorFunc(body.Close)
+
+		host, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != preferred {
+			t.Errorf("expected %s, got: %s", preferred.URL(), host.URL())
+		}
+	})
+
+	t.Run("selects the least loaded host when all hosts are busy", func(t *testing.T) {
+		hosts := newTestHosts(t, 2)
+
+		ch, err := NewConsistentHash(hosts, WithSpilloverThreshold(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		preferred, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		other := hosts[0]
+		if other == preferred {
+			other = hosts[1]
+		}
+
+		for _, body := range []io.Closer{occupy(t, preferred), occupy(t, preferred), occupy(t, other)} {
+			defer goutils.CatchWarnErrorFunc(body.Close)
+		}
+
+		host, err := ch.NextWithKey("key")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != other {
+			t.Errorf("expected the least loaded host %s, got: %s", other.URL(), host.URL())
+		}
+	})
+}
+
+func TestConsistentHash_Client(t *testing.T) {
+	hosts := newTestHosts(t, 3)
+
+	ch, err := NewConsistentHash(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(ch)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	for _, key := range []string{"alpha", "beta", "gamma"} {
+		expected, err := ch.NextWithKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := loadbalancer.WithAffinityKey(context.Background(), key)
+
+		for range 3 {
+			resp, err := client.R(http.MethodGet, "/resource").Execute(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			expectedIndex := fmt.Sprint(slices.Index(hosts, expected))
+			if resp.Header.Get("X-Host") != expectedIndex {
+				t.Errorf("expected key %s to be routed to host %s, got: %s", key, expectedIndex, resp.Header.Get("X-Host"))
+			}
+		}
+	}
+}
+
+func TestConsistentHash_RequestCost(t *testing.T) {
+	hosts := newTestHosts(t, 2)
+
+	ch, err := NewConsistentHash(hosts, WithSpilloverThreshold(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(ch)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	preferred, err := ch.NextWithKey("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preferredIndex := fmt.Sprint(slices.Index(hosts, preferred))
+	ctx := loadbalancer.WithAffinityKey(context.Background(), "key")
+
+	send := func(cost int) *http.Response {
+		req := client.R(http.MethodGet, "/resource")
+		req.SetCost(cost)
+
+		resp, err := req.Execute(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return resp
+	}
+
+	// Low-cost requests stay below the spillover threshold of the preferred host.
+	lowCostResponses := []*http.Response{send(1), send(1), send(1)}
+
+	for _, resp := range lowCostResponses {
+		if resp.Header.Get("X-Host") != preferredIndex {
+			t.Errorf("expected low-cost requests to be routed to host %s, got: %s", preferredIndex, resp.Header.Get("X-Host"))
+		}
+	}
+
+	if preferred.ActiveRequests() != 3 {
+		t.Errorf("expected 3 in-flight requests, got: %d", preferred.ActiveRequests())
+	}
+
+	for _, resp := range lowCostResponses {
+		goutils.CloseResponse(resp)
+	}
+
+	// A single high-cost request reserves the capacity of the preferred host.
+	heavyResp := send(4)
+	if heavyResp.Header.Get("X-Host") != preferredIndex {
+		t.Errorf("expected the high-cost request to be routed to host %s, got: %s", preferredIndex, heavyResp.Header.Get("X-Host"))
+	}
+
+	if preferred.ActiveRequests() != 4 {
+		t.Errorf("expected the in-flight cost of 4, got: %d", preferred.ActiveRequests())
+	}
+
+	resp := send(1)
+	if resp.Header.Get("X-Host") == preferredIndex {
+		t.Errorf("expected the next request to spill over from host %s", preferredIndex)
+	}
+
+	goutils.CloseResponse(resp)
+	goutils.CloseResponse(heavyResp)
+
+	if preferred.ActiveRequests() != 0 {
+		t.Errorf("expected no in-flight requests, got: %d", preferred.ActiveRequests())
+	}
+
+	resp = send(1)
+	if resp.Header.Get("X-Host") != preferredIndex {
+		t.Errorf("expected the key to return to host %s, got: %s", preferredIndex, resp.Header.Get("X-Host"))
+	}
+
+	goutils.CloseResponse(resp)
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/failsafe-go/failsafe-go/circuitbreaker"
@@ -45,12 +46,43 @@ var (
 	ErrInvalidHealthCheckFailureThreshold = errors.New(
 		"failure threshold of HTTP health check must be positive",
 	)
+	// ErrInvalidHealthCheckFailureStatus occurs when a failure status of the health check config is invalid.
+	ErrInvalidHealthCheckFailureStatus = errors.New(
+		"invalid failure status of HTTP health check. Expects a status in range 100-599",
+	)
+	// ErrInvalidHealthCheckProtocol occurs when the protocol of the health check config is invalid.
+	ErrInvalidHealthCheckProtocol = fmt.Errorf(
+		"invalid health check protocol. Expects one of %v",
+		enumValueHealthCheckProtocols,
+	)
+)
+
+// HealthCheckProtocol represents the protocol of the health check probe.
+type HealthCheckProtocol string
+
+const (
+	// HealthCheckProtocolHTTP probes the health of the host with an HTTP request.
+	HealthCheckProtocolHTTP HealthCheckProtocol = "http"
+	// HealthCheckProtocolGRPC probes the health of the host with the standard gRPC health checking protocol.
+	HealthCheckProtocolGRPC HealthCheckProtocol = "grpc"
+	// HealthCheckProtocolTCP probes the health of the host by dialing a TCP connection to the host.
+	HealthCheckProtocolTCP HealthCheckProtocol = "tcp"
 )
 
+var enumValueHealthCheckProtocols = []HealthCheckProtocol{
+	HealthCheckProtocolHTTP,
+	HealthCheckProtocolGRPC,
+	HealthCheckProtocolTCP,
+}
+
 // HTTPHealthCheckConfig holds configurations for health checking the server and recovery.
 type HTTPHealthCheckConfig struct {
-	// Health check path, e.g, /healthz.
+	// Protocol of the health check probe. Default to http.
+	Protocol HealthCheckProtocol `json:"protocol,omitempty" yaml:"protocol,omitempty" jsonschema:"default=http,enum=http,enum=grpc,enum=tcp"`
+	// Health check path, e.g, /healthz. Ignored by the gRPC and TCP probes.
 	Path string `json:"path" yaml:"path"`
+	// The service name of the gRPC health check. If empty, the overall health of the server is checked.
+	Service string `json:"service,omitempty" yaml:"service,omitempty"`
 	// Health check method. Default to GET
 	Method string `json:"method,omitempty" yaml:"method,omitempty" jsonschema:"default=GET,enum=GET,enum=POST"`
 	// Request body is used if the method is POST.
@@ -67,12 +99,25 @@ type HTTPHealthCheckConfig struct {
 	SuccessThreshold *int `json:"successThreshold,omitempty" yaml:"successThreshold,omitempty" jsonschema:"default=1,min=1"`
 	// Failure threshold. After a probe fails threshold times in a row, the HTTP client considers that the overall check has failed. Default to 5. Minimum value is 1
 	FailureThreshold *int `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty" jsonschema:"default=3,min=1"`
+	// FailureStatuses is the list of response statuses which are recorded as failures of the circuit breaker.
+	// Transport errors are always failures. If empty, any status >= 500 is a failure.
+	FailureStatuses []int `json:"failureStatuses,omitempty" yaml:"failureStatuses,omitempty" jsonschema:"minimum=100,maximum=599"`
 }
 
 // ToPolicyBuilder validates the health check config and create the policy builder.
 func (hc HTTPHealthCheckConfig) ToPolicyBuilder() (*HTTPHealthCheckPolicyBuilder, error) { //nolint:funlen
 	builder := NewHTTPHealthCheckPolicyBuilder()
 
+	if hc.Protocol != "" {
+		if !slices.Contains(enumValueHealthCheckProtocols, hc.Protocol) {
+			return nil, fmt.Errorf("%w, got: %s", ErrInvalidHealthCheckProtocol, hc.Protocol)
+		}
+
+		builder.protocol = hc.Protocol
+	}
+
+	builder.service = hc.Service
+
 	if hc.SuccessStatus != nil {
 		builder.successStatus = *hc.SuccessStatus
 
@@ -94,6 +139,16 @@ func (hc HTTPHealthCheckConfig) ToPolicyBuilder() (*HTTPHealthCheckPolicyBuilder
 		builder.failureThreshold = uint(*hc.FailureThreshold)
 	}
 
+	for _, status := range hc.FailureStatuses {
+		if status < 100 || status > 599 {
+			return nil, fmt.Errorf("%w, got: %d", ErrInvalidHealthCheckFailureStatus, status)
+		}
+	}
+
+	if len(hc.FailureStatuses) > 0 {
+		builder.failureStatuses = slices.Clone(hc.FailureStatuses)
+	}
+
 	// If no health check interval is set, the circuit breaker still runs with runtime HTTP requests.
 	if hc.Interval != nil && *hc.Interval > 0 {
 		builder.interval = time.Duration(*hc.Interval) * time.Second
@@ -161,11 +216,40 @@ func (hc HTTPHealthCheckConfig) ToPolicy(endpoint *url.URL) (*HTTPHealthCheckPol
 type HTTPHealthCheckPolicy struct {
 	circuitbreaker.CircuitBreaker[int]
 
-	path    string
-	method  string
-	headers map[string]string
-	body    []byte
-	timeout time.Duration
+	protocol        HealthCheckProtocol
+	service         string
+	path            string
+	method          string
+	headers         map[string]string
+	body            []byte
+	timeout         time.Duration
+	failureStatuses []int
+	// successStatus is the expected status of HTTP health check responses.
+	successStatus int
+}
+
+// Protocol returns the protocol of the health check probe.
+func (hcp *HTTPHealthCheckPolicy) Protocol() HealthCheckProtocol {
+	return hcp.protocol
+}
+
+// SetProtocol sets the protocol of the health check probe.
+func (hcp *HTTPHealthCheckPolicy) SetProtocol(value HealthCheckProtocol) *HTTPHealthCheckPolicy {
+	hcp.protocol = value
+
+	return hcp
+}
+
+// Service returns the service name of the gRPC health check.
+func (hcp *HTTPHealthCheckPolicy) Service() string {
+	return hcp.service
+}
+
+// SetService sets the service name of the gRPC health check.
+func (hcp *HTTPHealthCheckPolicy) SetService(value string) *HTTPHealthCheckPolicy {
+	hcp.service = value
+
+	return hcp
 }
 
 // Path returns the health check path.
@@ -228,6 +312,52 @@ func (hcp *HTTPHealthCheckPolicy) SetTimeout(value time.Duration) *HTTPHealthChe
 	return hcp
 }
 
+// FailureStatuses returns the response statuses which are recorded as failures.
+func (hcp *HTTPHealthCheckPolicy) FailureStatuses() []int {
+	return hcp.failureStatuses
+}
+
+// SetFailureStatuses sets the response statuses which are recorded as failures.
+// If empty, any status >= 500 is a failure.
+func (hcp *HTTPHealthCheckPolicy) SetFailureStatuses(statuses []int) *HTTPHealthCheckPolicy {
+	hcp.failureStatuses = statuses
+
+	return hcp
+}
+
+// inheritCircuitBreakerState copies the state and the recorded counts of the previous circuit breaker.
+func (hcp *HTTPHealthCheckPolicy) inheritCircuitBreakerState(previous *HTTPHealthCheckPolicy) {
+	switch previous.State() {
+	case circuitbreaker.OpenState:
+		hcp.Open()
+
+		return
+	case circuitbreaker.HalfOpenState:
+		hcp.HalfOpen()
+	case circuitbreaker.ClosedState:
+	}
+
+	// Replay the counts of the previous state. The new thresholds apply, so the state may transition.
+	metrics := previous.Metrics()
+
+	for range metrics.Successes() {
+		hcp.RecordSuccess()
+	}
+
+	for range metrics.Failures() {
+		hcp.RecordFailure()
+	}
+}
+
+// IsFailureStatus checks if the response status is recorded as a failure of the circuit breaker.
+func (hcp *HTTPHealthCheckPolicy) IsFailureStatus(status int) bool {
+	if len(hcp.failureStatuses) > 0 {
+		return slices.Contains(hcp.failureStatuses, status)
+	}
+
+	return status >= http.StatusInternalServerError
+}
+
 // HTTPHealthCheckPolicyBuilder represents an HTTP health check policy builder.
 type HTTPHealthCheckPolicyBuilder struct {
 	*HTTPHealthCheckPolicy
@@ -236,15 +366,17 @@ type HTTPHealthCheckPolicyBuilder struct {
 	successThreshold uint
 	failureThreshold uint
 	interval         time.Duration
+	metrics          *gohttpc.HTTPClientMetrics
 }
 
 // NewHTTPHealthCheckPolicyBuilder creates an HTTP health check policy builder.
 func NewHTTPHealthCheckPolicyBuilder() *HTTPHealthCheckPolicyBuilder {
 	return &HTTPHealthCheckPolicyBuilder{
 		HTTPHealthCheckPolicy: &HTTPHealthCheckPolicy{
-			method:  http.MethodGet,
-			path:    "/",
-			timeout: 5 * time.Second,
+			protocol: HealthCheckProtocolHTTP,
+			method:   http.MethodGet,
+			path:     "/",
+			timeout:  5 * time.Second,
 		},
 		successStatus:    http.StatusOK,
 		successThreshold: 1,
@@ -309,9 +441,32 @@ func (hb *HTTPHealthCheckPolicyBuilder) WithFailureThreshold(
 	return hb
 }
 
+// WithFailureStatuses sets the response statuses which are recorded as failures of the circuit breaker.
+// It overrides the default rule that any status >= 500 is a failure.
+func (hb *HTTPHealthCheckPolicyBuilder) WithFailureStatuses(
+	statuses ...int,
+) *HTTPHealthCheckPolicyBuilder {
+	hb.failureStatuses = statuses
+
+	return hb
+}
+
+// WithMetrics sets the metrics which record the server state of the health check.
+// The global metrics of [gohttpc.GetHTTPClientMetrics] are used if not set.
+func (hb *HTTPHealthCheckPolicyBuilder) WithMetrics(
+	metrics *gohttpc.HTTPClientMetrics,
+) *HTTPHealthCheckPolicyBuilder {
+	hb.metrics = metrics
+
+	return hb
+}
+
 // Build builds the [HTTPHealthCheckPolicy].
 func (hb *HTTPHealthCheckPolicyBuilder) Build(endpoint *url.URL) *HTTPHealthCheckPolicy {
-	metrics := gohttpc.GetHTTPClientMetrics()
+	metrics := hb.metrics
+	if metrics == nil {
+		metrics = gohttpc.GetHTTPClientMetrics()
+	}
 	urlScheme := "http"
 
 	if endpoint.Scheme != "" {
@@ -338,6 +493,7 @@ func (hb *HTTPHealthCheckPolicyBuilder) Build(endpoint *url.URL) *HTTPHealthChec
 
 	policy := *hb.HTTPHealthCheckPolicy
 	policy.CircuitBreaker = builder.Build()
+	policy.successStatus = hb.successStatus
 
 	// Record initial metrics for the closed state.
 	metrics.ServerState.Record(
@@ -0,0 +1,102 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/relychan/goutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkGRPCHealth calls the grpc.health.v1.Health/Check method of the host.
+// The probe succeeds if the serving status is SERVING. Returns true if the probe succeeded.
+func (s *Host) checkGRPCHealth(ctx context.Context) bool {
+	timeout := s.healthCheckPolicy.timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := s.newGRPCHealthClientConn()
+	if err != nil {
+		s.healthCheckPolicy.RecordError(err)
+
+		return false
+	}
+
+	defer goutils.CatchWarnErrorFunc(conn.Close)
+
+	requestContext, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if len(s.healthCheckPolicy.headers) > 0 {
+		requestContext = metadata.NewOutgoingContext(
+			requestContext,
+			metadata.New(s.healthCheckPolicy.headers),
+		)
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(requestContext, &healthpb.HealthCheckRequest{
+		Service: s.healthCheckPolicy.service,
+	})
+	if err != nil {
+		s.healthCheckPolicy.RecordError(err)
+
+		return false
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		s.healthCheckPolicy.RecordFailure()
+
+		return false
+	}
+
+	s.healthCheckPolicy.RecordSuccess()
+
+	return true
+}
+
+// newGRPCHealthClientConn creates a gRPC client connection to the host.
+// The TLS config of the HTTP transport is reused if the scheme of the host is https.
+func (s *Host) newGRPCHealthClientConn() (*grpc.ClientConn, error) {
+	endpoint, target, err := s.dialAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+
+	if endpoint.Scheme == "https" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		if s.httpClient != nil {
+			if transport, ok := s.httpClient.Transport.(*http.Transport); ok &&
+				transport.TLSClientConfig != nil {
+				tlsConfig = transport.TLSClientConfig.Clone()
+			}
+		}
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	return grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHost_CheckHealth_GRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healthServer := health.NewServer()
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	defer grpcServer.Stop()
+
+	threshold := 1
+
+	builder, err := HTTPHealthCheckConfig{
+		Protocol:         HealthCheckProtocolGRPC,
+		Service:          "echo",
+		SuccessThreshold: &threshold,
+		FailureThreshold: &threshold,
+	}.ToPolicyBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := NewHost(
+		&http.Client{},
+		"http://"+listener.Addr().String(),
+		WithHTTPHealthCheckPolicyBuilder(builder.WithInterval(10*time.Millisecond)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer host.Close()
+
+	healthServer.SetServingStatus("echo", healthpb.HealthCheckResponse_SERVING)
+	host.CheckHealth(context.Background())
+
+	if host.State() != circuitbreaker.ClosedState {
+		t.Fatalf("expected the circuit breaker to be closed, got %v", host.State())
+	}
+
+	healthServer.SetServingStatus("echo", healthpb.HealthCheckResponse_NOT_SERVING)
+	host.CheckHealth(context.Background())
+
+	if host.State() != circuitbreaker.OpenState {
+		t.Fatalf("expected the circuit breaker to be open, got %v", host.State())
+	}
+
+	// Wait for the circuit breaker to be half-open.
+	time.Sleep(20 * time.Millisecond)
+
+	if !host.healthCheckPolicy.TryAcquirePermit() {
+		t.Fatal("expected the circuit breaker to permit the probe after the delay")
+	}
+
+	healthServer.SetServingStatus("echo", healthpb.HealthCheckResponse_SERVING)
+	host.CheckHealth(context.Background())
+
+	if host.State() != circuitbreaker.ClosedState {
+		t.Fatalf("expected the circuit breaker to be closed after recovery, got %v", host.State())
+	}
+
+	t.Run("unknown_service", func(t *testing.T) {
+		host.healthCheckPolicy.SetService("unknown")
+		host.CheckHealth(context.Background())
+
+		if host.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the circuit breaker to be open, got %v", host.State())
+		}
+	})
+}
+
+func TestHTTPHealthCheckConfig_ToPolicyBuilder_Protocol(t *testing.T) {
+	builder, err := HTTPHealthCheckConfig{}.ToPolicyBuilder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if builder.Protocol() != HealthCheckProtocolHTTP {
+		t.Errorf("expected the default protocol to be http, got %s", builder.Protocol())
+	}
+
+	_, err = HTTPHealthCheckConfig{Protocol: "udp"}.ToPolicyBuilder()
+	if !errors.Is(err, ErrInvalidHealthCheckProtocol) {
+		t.Errorf("expected ErrInvalidHealthCheckProtocol, got %v", err)
+	}
+}
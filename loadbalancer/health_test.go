@@ -15,11 +15,18 @@
 package loadbalancer
 
 import (
+	"errors"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestHTTPHealthCheckConfig_ToPolicyBuilder_Headers(t *testing.T) {
@@ -217,3 +224,76 @@ func TestHTTPHealthCheckConfig_ToPolicyBuilder_Headers(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPHealthCheckConfig_ToPolicyBuilder_FailureStatuses(t *testing.T) {
+	t.Run("valid failure statuses", func(t *testing.T) {
+		config := HTTPHealthCheckConfig{
+			Path:            "/healthz",
+			FailureStatuses: []int{503, 599},
+		}
+
+		builder, err := config.ToPolicyBuilder()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal(builder.FailureStatuses(), []int{503, 599}) {
+			t.Errorf("unexpected failure statuses: %v", builder.FailureStatuses())
+		}
+
+		if builder.IsFailureStatus(http.StatusInternalServerError) || !builder.IsFailureStatus(599) {
+			t.Error("expected only listed statuses to be failures")
+		}
+	})
+
+	t.Run("invalid failure status", func(t *testing.T) {
+		config := HTTPHealthCheckConfig{
+			Path:            "/healthz",
+			FailureStatuses: []int{600},
+		}
+
+		_, err := config.ToPolicyBuilder()
+		if !errors.Is(err, ErrInvalidHealthCheckFailureStatus) {
+			t.Errorf("expected ErrInvalidHealthCheckFailureStatus, got: %v", err)
+		}
+	})
+}
+
+func TestHTTPHealthCheckPolicyBuilder_WithMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint, _ := url.Parse("http://localhost:8080")
+
+	NewHTTPHealthCheckPolicyBuilder().WithMetrics(clientMetrics).Build(endpoint)
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == "http.client.server_state" {
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected the server state to be recorded to the metrics of the builder")
+	}
+}
@@ -17,9 +17,13 @@ package loadbalancer
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -28,7 +32,9 @@ import (
 	"github.com/relychan/gohttpc"
 	"github.com/relychan/gohttpc/authc/authscheme"
 	"github.com/relychan/goutils"
-	"github.com/relychan/goutils/httperror"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"golang.org/x/time/rate"
 )
 
 // Host represents the host information and its weight to load balance the requests.
@@ -39,10 +45,14 @@ type Host struct {
 	url string
 	// Defines custom headers to be injected to incoming requests.
 	headers map[string]string
+	// Defines response header names to be renamed, keyed by the original name.
+	responseHeaderRewrite map[string]string
 	// Defines the weight of the server endpoint for load balancing.
 	weight int
 	// The HTTP client is used for this server.
 	httpClient *http.Client
+	// Caches round-trippers of requests with overridden TLS server names.
+	serverNameTransports gohttpc.ServerNameTransports
 	// The custom authenticator for the current server.
 	authenticator authscheme.HTTPClientAuthenticator
 	// The health check policy.
@@ -51,9 +61,31 @@ type Host struct {
 	currentWeight int
 	// Cache the last HTTP Error status of the host.
 	lastHTTPErrorStatus atomic.Int32
+	// The total cost of requests which are being executed on the host.
+	activeRequests atomic.Int64
+	// The exponentially weighted moving average of the request latency in nanoseconds.
+	avgLatency atomic.Int64
+	// The optional client-side rate limiter of the host.
+	rateLimiter *rate.Limiter
+	// The response header name which advertises the load of the host.
+	loadHeader atomic.Pointer[string]
+	// The last load in range [0, 1] which was reported by the load header, stored as float64 bits.
+	load atomic.Uint64
+	// closed is true if the host was closed.
+	closed atomic.Bool
 }
 
+// loadWeightScale scales the weight of hosts whose load is advertised by the load header,
+// so the weight adjusted by the load keeps its precision.
+const loadWeightScale = 100
+
+// latencyEWMAFactor is the smoothing factor of the latency moving average.
+// A higher value discounts older observations faster.
+const latencyEWMAFactor = 0.2
+
 var _ gohttpc.HTTPClient = (*Host)(nil)
+var _ gohttpc.ServerNameClient = (*Host)(nil)
+var _ gohttpc.AuthenticatorClient = (*Host)(nil)
 
 // NewHost creates an [Host] with a client base URL.
 func NewHost(
@@ -70,8 +102,11 @@ func NewHost(
 	}
 
 	host := &Host{
-		httpClient: client,
-		weight:     opts.weight,
+		httpClient:            client,
+		weight:                opts.weight,
+		rateLimiter:           opts.rateLimiter,
+		responseHeaderRewrite: opts.responseHeaderRewrite,
+		authenticator:         opts.authenticator,
 	}
 
 	u, err := host.SetURL(baseURL)
@@ -134,12 +169,25 @@ func (s *Host) SetHeaders(headers map[string]string) *Host {
 	return s
 }
 
+// ResponseHeaderRewrite returns response header names to be renamed of this host, keyed by the original name.
+func (s *Host) ResponseHeaderRewrite() map[string]string {
+	return s.responseHeaderRewrite
+}
+
+// SetResponseHeaderRewrite sets response header names to be renamed of this host, keyed by the original name.
+func (s *Host) SetResponseHeaderRewrite(rewrite map[string]string) *Host {
+	s.responseHeaderRewrite = rewrite
+
+	return s
+}
+
 // Authenticator returns the custom authenticator for this host.
 func (s *Host) Authenticator() authscheme.HTTPClientAuthenticator {
 	return s.authenticator
 }
 
 // SetAuthenticator sets the authenticator for this host.
+// It takes precedence over the default authenticator of the client, but not the authenticator of the request.
 func (s *Host) SetAuthenticator(authenticator authscheme.HTTPClientAuthenticator) *Host {
 	s.authenticator = authenticator
 
@@ -158,9 +206,67 @@ func (s *Host) SetWeight(weight int) *Host {
 	return s
 }
 
-// AddCurrentWeight adds the weight to the current weight.
+// LoadHeader returns the response header name which advertises the load of this host.
+func (s *Host) LoadHeader() string {
+	name := s.loadHeader.Load()
+	if name == nil {
+		return ""
+	}
+
+	return *name
+}
+
+// SetLoadHeader sets the response header name which advertises the load of this host, e.g. X-Load.
+// The load is a number in range [0, 1] which reduces the effective weight of the host.
+func (s *Host) SetLoadHeader(name string) *Host {
+	if name == "" {
+		s.loadHeader.Store(nil)
+	} else {
+		s.loadHeader.Store(&name)
+	}
+
+	return s
+}
+
+// Load returns the last load in range [0, 1] which was reported by the load header of this host.
+func (s *Host) Load() float64 {
+	return math.Float64frombits(s.load.Load())
+}
+
+// EffectiveWeight returns the weight of this host adjusted by the reported load.
+// It equals the weight if the load header isn't set. Otherwise, the weight is scaled
+// and reduced in proportion to the load, but never drops below 1.
+func (s *Host) EffectiveWeight() int {
+	if s.loadHeader.Load() == nil {
+		return s.weight
+	}
+
+	return max(int(math.Round(float64(s.weight*loadWeightScale)*(1-s.Load()))), 1)
+}
+
+// recordLoad parses the load of the host from the response header.
+func (s *Host) recordLoad(header http.Header) {
+	name := s.loadHeader.Load()
+	if name == nil {
+		return
+	}
+
+	rawValue := header.Get(*name)
+	if rawValue == "" {
+		return
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+	if err != nil || math.IsNaN(value) {
+		return
+	}
+
+	s.load.Store(math.Float64bits(min(max(value, 0), 1)))
+}
+
+// AddCurrentWeight adds the effective weight to the current weight.
 func (s *Host) AddCurrentWeight() {
-	s.currentWeight += s.weight
+	s.currentWeight += s.EffectiveWeight()
 }
 
 // ResetCurrentWeight resets the current weight.
@@ -168,11 +274,47 @@ func (s *Host) ResetCurrentWeight(totalWeight int) {
 	s.currentWeight -= totalWeight
 }
 
+// AdjustCurrentWeight adds the delta to the current weight.
+func (s *Host) AdjustCurrentWeight(delta int) {
+	s.currentWeight += delta
+}
+
 // CurrentWeight adds the weight to the current weight.
 func (s *Host) CurrentWeight() int {
 	return s.currentWeight
 }
 
+// InheritState carries over the circuit breaker state, the current weight
+// and the last HTTP error status from the previous instance of this host.
+// The host keeps its own health check policy, only the state and counts of the circuit breaker are copied.
+func (s *Host) InheritState(previous *Host) *Host {
+	if previous == nil || previous == s {
+		return s
+	}
+
+	if previous.healthCheckPolicy != nil && s.healthCheckPolicy != nil {
+		s.healthCheckPolicy.inheritCircuitBreakerState(previous.healthCheckPolicy)
+	}
+
+	s.currentWeight = previous.currentWeight
+	s.lastHTTPErrorStatus.Store(previous.lastHTTPErrorStatus.Load())
+	s.load.Store(previous.load.Load())
+
+	return s
+}
+
+// RateLimiter returns the client-side rate limiter of this host.
+func (s *Host) RateLimiter() *rate.Limiter {
+	return s.rateLimiter
+}
+
+// SetRateLimiter sets the client-side rate limiter of this host.
+func (s *Host) SetRateLimiter(limiter *rate.Limiter) *Host {
+	s.rateLimiter = limiter
+
+	return s
+}
+
 // HTTPClient returns the HTTP client of this host.
 func (s *Host) HTTPClient() *http.Client {
 	return s.httpClient
@@ -181,6 +323,7 @@ func (s *Host) HTTPClient() *http.Client {
 // SetHTTPClient sets the HTTP client of this host.
 func (s *Host) SetHTTPClient(client *http.Client) *Host {
 	s.httpClient = client
+	s.serverNameTransports.Reset()
 
 	return s
 }
@@ -206,12 +349,32 @@ func (s *Host) State() circuitbreaker.State {
 	return s.healthCheckPolicy.State()
 }
 
-// CheckHealth runs an HTTP request to checking the health of the host.
+// CheckHealth probes the health of the host with the protocol of the health check policy.
+// The HTTP health check request is sent by default.
 func (s *Host) CheckHealth(ctx context.Context) {
+	s.checkHealth(ctx)
+}
+
+// checkHealth probes the health of the host and returns true if the probe succeeded.
+// Hosts without health check policies are always healthy.
+func (s *Host) checkHealth(ctx context.Context) bool {
 	if s.healthCheckPolicy == nil {
-		return
+		return true
 	}
 
+	switch s.healthCheckPolicy.protocol {
+	case HealthCheckProtocolGRPC:
+		return s.checkGRPCHealth(ctx)
+	case HealthCheckProtocolTCP:
+		return s.checkTCPHealth(ctx)
+	default:
+		return s.checkHTTPHealth(ctx)
+	}
+}
+
+// checkHTTPHealth sends the health check request to the host and records the response status.
+// Returns true if the response status is the success status of the policy.
+func (s *Host) checkHTTPHealth(ctx context.Context) bool {
 	healthURL := s.url + s.healthCheckPolicy.path
 
 	timeout := s.healthCheckPolicy.timeout
@@ -237,7 +400,7 @@ func (s *Host) CheckHealth(ctx context.Context) {
 	if err != nil {
 		s.healthCheckPolicy.RecordError(err)
 
-		return
+		return false
 	}
 
 	for key, header := range s.healthCheckPolicy.headers {
@@ -248,23 +411,102 @@ func (s *Host) CheckHealth(ctx context.Context) {
 	if resp == nil {
 		s.healthCheckPolicy.RecordError(err)
 
-		return
+		return false
 	}
 
 	goutils.CloseResponse(resp)
 
 	s.healthCheckPolicy.RecordResult(resp.StatusCode)
+
+	return resp.StatusCode == s.healthCheckPolicy.successStatus
+}
+
+// checkTCPHealth dials a TCP connection to the host:port of the host within the timeout.
+// Returns true if the connection is established.
+func (s *Host) checkTCPHealth(ctx context.Context) bool {
+	timeout := s.healthCheckPolicy.timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	_, address, err := s.dialAddress()
+	if err != nil {
+		s.healthCheckPolicy.RecordError(err)
+
+		return false
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		s.healthCheckPolicy.RecordError(err)
+
+		return false
+	}
+
+	goutils.CatchWarnErrorFunc(conn.Close)
+
+	s.healthCheckPolicy.RecordSuccess()
+
+	return true
+}
+
+// dialAddress parses the URL of the host and returns the host:port address to dial.
+// The port defaults to the well-known port of the scheme.
+func (s *Host) dialAddress() (*url.URL, string, error) {
+	endpoint, err := url.Parse(s.url)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse the host URL: %w", err)
+	}
+
+	port := endpoint.Port()
+	if port == "" {
+		port = "80"
+
+		if endpoint.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	return endpoint, net.JoinHostPort(endpoint.Hostname(), port), nil
 }
 
 // GetLastHTTPErrorStatus returns the last HTTP error status,
 // and the flag to determine if it is the server outage status.
 func (s *Host) GetLastHTTPErrorStatus() (int32, bool) {
 	lastHTTPErrorStatus := s.lastHTTPErrorStatus.Load()
-	// The gateway timeout status may be caused by the slow backend. It may not be server outage.
-	isServerOutage := lastHTTPErrorStatus >= http.StatusBadGateway &&
-		lastHTTPErrorStatus != http.StatusGatewayTimeout
 
-	return lastHTTPErrorStatus, isServerOutage
+	return lastHTTPErrorStatus, gohttpc.IsServerOutageStatus(int(lastHTTPErrorStatus))
+}
+
+// ActiveRequests returns the number of in-flight requests on this host, weighted by the request cost.
+// A request is in flight from the time it is sent until its response body is closed.
+// See [gohttpc.Request.SetCost].
+func (s *Host) ActiveRequests() int64 {
+	return s.activeRequests.Load()
+}
+
+// AvgLatency returns the exponentially weighted moving average of the round trip latency of this host.
+// Returns zero if no request has completed yet.
+func (s *Host) AvgLatency() time.Duration {
+	return time.Duration(s.avgLatency.Load())
+}
+
+// recordLatency updates the moving average of the round trip latency.
+func (s *Host) recordLatency(latency time.Duration) {
+	for {
+		current := s.avgLatency.Load()
+		next := int64(latency)
+
+		if current > 0 {
+			next = current + int64(latencyEWMAFactor*float64(next-current))
+		}
+
+		if s.avgLatency.CompareAndSwap(current, next) {
+			return
+		}
+	}
 }
 
 // NewRequest returns a new http.Request given a method, URL, and optional body.
@@ -278,7 +520,7 @@ func (s *Host) NewRequest(
 		lastHTTPErrorStatus, isOutage := s.GetLastHTTPErrorStatus()
 		if isOutage {
 			// Returns error directly if HTTP status >= 502, except 504.
-			return nil, httperror.NewHTTPError(int(lastHTTPErrorStatus), "")
+			return nil, gohttpc.NewHTTPError(int(lastHTTPErrorStatus))
 		}
 	}
 
@@ -288,14 +530,70 @@ func (s *Host) NewRequest(
 // Do sends an HTTP request and returns an HTTP response, following policy
 // (such as redirects, cookies, auth) as configured on the client.
 func (s *Host) Do(req *http.Request) (*http.Response, error) {
-	resp, err := s.httpClient.Do(req) //nolint:gosec
+	return s.do(req, s.httpClient.Do)
+}
+
+// DoWithServerName sends an HTTP request with the TLS server name overridden for this request only.
+func (s *Host) DoWithServerName(req *http.Request, serverName string) (*http.Response, error) {
+	return s.do(req, func(req *http.Request) (*http.Response, error) {
+		return s.serverNameTransports.Do(s.httpClient, req, serverName)
+	})
+}
+
+func (s *Host) do(
+	req *http.Request,
+	send func(req *http.Request) (*http.Response, error),
+) (*http.Response, error) {
+	if s.rateLimiter != nil {
+		err := gohttpc.WaitRateLimiter(
+			req.Context(),
+			s.rateLimiter,
+			metric.WithAttributes(semconv.ServerAddress(req.URL.Hostname())),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cost := int64(gohttpc.GetRequestCost(req.Context()))
+	s.activeRequests.Add(cost)
+
+	startTime := time.Now()
+	resp, err := send(req)
+
+	// Fast failures such as refused connections or gateway errors would otherwise
+	// lower the average and attract more traffic to an unhealthy host.
+	if err == nil && resp != nil && resp.StatusCode < http.StatusBadGateway {
+		s.recordLatency(time.Since(startTime))
+	}
+
+	if resp != nil && len(s.responseHeaderRewrite) > 0 {
+		rewriteHeaderNames(resp.Header, s.responseHeaderRewrite)
+	}
+
+	if resp != nil {
+		s.recordLoad(resp.Header)
+	}
+
+	// The upgraded connection of 101 responses isn't wrapped to keep it writable.
+	// The upgrade has completed at this point so the load is released immediately.
+	if resp != nil && resp.Body != nil && resp.Body != http.NoBody &&
+		resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body = &hostResponseBody{
+			ReadCloser: resp.Body,
+			host:       s,
+			cost:       cost,
+		}
+	} else {
+		s.activeRequests.Add(-cost)
+	}
 
 	if s.healthCheckPolicy == nil {
 		return resp, err
 	}
 
 	if resp != nil {
-		if resp.StatusCode >= http.StatusInternalServerError {
+		if s.healthCheckPolicy.IsFailureStatus(resp.StatusCode) {
 			s.lastHTTPErrorStatus.Store(int32(resp.StatusCode))
 			s.healthCheckPolicy.RecordFailure()
 		} else {
@@ -308,12 +606,35 @@ func (s *Host) Do(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
-// Close terminates internal processes.
+// rewriteHeaderNames renames header fields. Values of the original name are appended to the new name.
+func rewriteHeaderNames(header http.Header, rewrite map[string]string) {
+	for from, to := range rewrite {
+		from = http.CanonicalHeaderKey(from)
+		to = http.CanonicalHeaderKey(to)
+
+		values, ok := header[from]
+		if !ok || from == to {
+			continue
+		}
+
+		delete(header, from)
+
+		header[to] = append(header[to], values...)
+	}
+}
+
+// Close terminates internal processes. It is safe to call Close multiple times.
 func (s *Host) Close() {
+	if !s.closed.CompareAndSwap(false, true) {
+		return
+	}
+
 	if s.httpClient != nil {
 		s.httpClient.CloseIdleConnections()
 	}
 
+	s.serverNameTransports.CloseIdleConnections()
+
 	if s.healthCheckPolicy != nil {
 		s.healthCheckPolicy.Close()
 	}
@@ -325,22 +646,7 @@ func (s *Host) newRequest(
 	url string,
 	body io.Reader,
 ) (*http.Request, error) {
-	reqURL := url
-
-	switch {
-	case url == "" || url == "/":
-		reqURL = s.url
-	case !goutils.HasStringPrefixFold(url, "http"):
-		if url[0] == '/' {
-			reqURL = s.url + url
-		} else {
-			reqURL = s.url + "/" + url
-		}
-
-		reqURL = strings.TrimRight(reqURL, "/")
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	req, err := http.NewRequestWithContext(ctx, method, gohttpc.JoinURL(s.url, url), body)
 	if err != nil {
 		return nil, err
 	}
@@ -349,7 +655,7 @@ func (s *Host) newRequest(
 		req.Header.Set(key, header)
 	}
 
-	if s.authenticator != nil {
+	if s.authenticator != nil && !gohttpc.IsAuthDisabled(ctx) {
 		err := s.authenticator.Authenticate(req)
 		if err != nil {
 			return req, err
@@ -359,6 +665,26 @@ func (s *Host) newRequest(
 	return req, nil
 }
 
+// hostResponseBody wraps the response body to release the in-flight counter of the host when closed.
+type hostResponseBody struct {
+	io.ReadCloser
+
+	host   *Host
+	cost   int64
+	closed atomic.Bool
+}
+
+// Close closes the body reader and decreases the in-flight counter once.
+func (rb *hostResponseBody) Close() error {
+	err := rb.ReadCloser.Close()
+
+	if rb.closed.CompareAndSwap(false, true) {
+		rb.host.activeRequests.Add(-rb.cost)
+	}
+
+	return err
+}
+
 // ServerMetrics represents the metrics data of a server.
 type ServerMetrics struct {
 	// Executions returns the number of executions recorded in the current state when the state is ClosedState or
@@ -397,6 +723,9 @@ type ServerMetrics struct {
 type hostOptions struct {
 	weight                   int
 	healthCheckPolicyBuilder *HTTPHealthCheckPolicyBuilder
+	rateLimiter              *rate.Limiter
+	responseHeaderRewrite    map[string]string
+	authenticator            authscheme.HTTPClientAuthenticator
 }
 
 // HostOption represents a function to modify host options.
@@ -412,6 +741,30 @@ func WithWeight(weight int) HostOption {
 	}
 }
 
+// WithHostRateLimiter sets the client-side rate limiter for the host,
+// so requests to the host are throttled independently of other hosts.
+func WithHostRateLimiter(limiter *rate.Limiter) HostOption {
+	return func(ho *hostOptions) {
+		ho.rateLimiter = limiter
+	}
+}
+
+// WithHostResponseHeaderRewrite sets response header names to be renamed for responses from the host,
+// keyed by the original name, e.g. to normalize non-standard header names of a legacy host.
+func WithHostResponseHeaderRewrite(rewrite map[string]string) HostOption {
+	return func(ho *hostOptions) {
+		ho.responseHeaderRewrite = rewrite
+	}
+}
+
+// WithHostAuthenticator sets the authenticator for the host, so each host of the pool can use distinct credentials.
+// It takes precedence over the default authenticator of the client, but not the authenticator of the request.
+func WithHostAuthenticator(authenticator authscheme.HTTPClientAuthenticator) HostOption {
+	return func(ho *hostOptions) {
+		ho.authenticator = authenticator
+	}
+}
+
 // WithHTTPHealthCheckPolicyBuilder sets the http health check builder for the host.
 func WithHTTPHealthCheckPolicyBuilder(builder *HTTPHealthCheckPolicyBuilder) HostOption {
 	return func(ho *hostOptions) {
@@ -16,12 +16,17 @@ package loadbalancer
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"golang.org/x/time/rate"
 )
 
 func TestHost_GetLastHTTPErrorStatus(t *testing.T) {
@@ -461,3 +466,679 @@ func TestHost_NewRequest_CircuitBreakerIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestHost_ActiveRequestsAndAvgLatency(t *testing.T) {
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		if r.URL.Path == "/stream" {
+			<-release
+		}
+
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	host, err := NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to create host: %v", err)
+	}
+
+	if host.ActiveRequests() != 0 {
+		t.Errorf("expected 0 active requests, got %d", host.ActiveRequests())
+	}
+
+	if host.AvgLatency() != 0 {
+		t.Errorf("expected zero latency before any request, got %s", host.AvgLatency())
+	}
+
+	t.Run("counts in-flight requests until the body is closed", func(t *testing.T) {
+		req, err := host.NewRequest(context.Background(), http.MethodGet, "/stream", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if host.ActiveRequests() != 1 {
+			t.Errorf("expected 1 active request, got %d", host.ActiveRequests())
+		}
+
+		close(release)
+
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		// Closing twice must not decrease the counter again.
+		_ = resp.Body.Close()
+
+		if host.ActiveRequests() != 0 {
+			t.Errorf("expected 0 active requests, got %d", host.ActiveRequests())
+		}
+	})
+
+	t.Run("tracks the moving average of latency", func(t *testing.T) {
+		for range 3 {
+			req, err := host.NewRequest(context.Background(), http.MethodGet, "/slow", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			resp, err := host.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_ = resp.Body.Close()
+		}
+
+		if host.AvgLatency() <= 0 {
+			t.Errorf("expected positive latency, got %s", host.AvgLatency())
+		}
+	})
+
+	t.Run("releases the counter on transport errors", func(t *testing.T) {
+		failedHost, err := NewHost(&http.Client{}, "http://127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to create host: %v", err)
+		}
+
+		req, err := failedHost.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = failedHost.Do(req) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected transport error")
+		}
+
+		if failedHost.ActiveRequests() != 0 {
+			t.Errorf("expected 0 active requests, got %d", failedHost.ActiveRequests())
+		}
+
+		if failedHost.AvgLatency() != 0 {
+			t.Errorf("expected no latency recorded on transport errors, got %s", failedHost.AvgLatency())
+		}
+	})
+
+	t.Run("skips the latency of outage statuses", func(t *testing.T) {
+		outageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer outageServer.Close()
+
+		outageHost, err := NewHost(outageServer.Client(), outageServer.URL)
+		if err != nil {
+			t.Fatalf("failed to create host: %v", err)
+		}
+
+		req, err := outageHost.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := outageHost.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_ = resp.Body.Close()
+
+		if outageHost.AvgLatency() != 0 {
+			t.Errorf("expected no latency recorded on outage statuses, got %s", outageHost.AvgLatency())
+		}
+	})
+}
+
+func TestHost_SwitchingProtocolsKeepsBodyWritable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		_ = rw.Flush()
+
+		line, _ := rw.ReadString('\n')
+		_, _ = rw.WriteString(line)
+		_ = rw.Flush()
+	}))
+	defer server.Close()
+
+	host, err := NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to create host: %v", err)
+	}
+
+	req, err := host.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "echo")
+
+	resp, err := host.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status 101, got %d", resp.StatusCode)
+	}
+
+	if host.ActiveRequests() != 0 {
+		t.Errorf("expected the load to be released after the upgrade, got %d", host.ActiveRequests())
+	}
+
+	writer, ok := resp.Body.(io.Writer)
+	if !ok {
+		t.Fatal("expected the upgraded body to be writable")
+	}
+
+	_, err = writer.Write([]byte("ping\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	buf := make([]byte, 5)
+
+	_, err = io.ReadFull(resp.Body, buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(buf) != "ping\n" {
+		t.Errorf("expected the echoed message, got %q", buf)
+	}
+}
+
+func TestHost_InheritState(t *testing.T) {
+	newHost := func(t *testing.T, rawURL string, failureThreshold uint, failureStatuses ...int) *Host {
+		t.Helper()
+
+		host, err := NewHost(
+			&http.Client{},
+			rawURL,
+			WithHTTPHealthCheckPolicyBuilder(NewHTTPHealthCheckPolicyBuilder().
+				WithFailureThreshold(failureThreshold).
+				WithFailureStatuses(failureStatuses...)),
+		)
+		if err != nil {
+			t.Fatalf("failed to create host: %v", err)
+		}
+
+		return host
+	}
+
+	t.Run("copies the counts into the own policy of the new host", func(t *testing.T) {
+		previous := newHost(t, "https://example1.com", 5)
+		previous.HealthCheckPolicy().RecordFailure()
+		previous.HealthCheckPolicy().RecordFailure()
+
+		host := newHost(t, "https://example2.com", 3, http.StatusTooManyRequests)
+		host.InheritState(previous)
+
+		if host.HealthCheckPolicy() == previous.HealthCheckPolicy() {
+			t.Fatal("expected the new host to keep its own health check policy")
+		}
+
+		if !host.HealthCheckPolicy().IsFailureStatus(http.StatusTooManyRequests) ||
+			host.HealthCheckPolicy().IsFailureStatus(http.StatusInternalServerError) {
+			t.Errorf("expected the failure statuses of the new host, got: %v", host.HealthCheckPolicy().FailureStatuses())
+		}
+
+		if failures := host.HealthCheckPolicy().Metrics().Failures(); failures != 2 {
+			t.Errorf("expected 2 inherited failures, got: %d", failures)
+		}
+
+		// The third failure reaches the threshold of the new host only.
+		host.HealthCheckPolicy().RecordFailure()
+
+		if host.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the new host to be open, got: %v", host.State())
+		}
+
+		if previous.State() != circuitbreaker.ClosedState {
+			t.Errorf("expected the previous host to stay closed, got: %v", previous.State())
+		}
+	})
+
+	t.Run("copies the open state", func(t *testing.T) {
+		previous := newHost(t, "https://example1.com", 1)
+		previous.HealthCheckPolicy().Open()
+
+		host := newHost(t, "https://example2.com", 1)
+		host.InheritState(previous)
+
+		if host.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the open state, got: %v", host.State())
+		}
+
+		previous.HealthCheckPolicy().Close()
+
+		if host.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the breakers not to be shared, got: %v", host.State())
+		}
+	})
+}
+
+func TestHost_RecordLatency(t *testing.T) {
+	host := &Host{}
+
+	host.recordLatency(100 * time.Millisecond)
+
+	if host.AvgLatency() != 100*time.Millisecond {
+		t.Fatalf("expected the first sample to seed the average, got %s", host.AvgLatency())
+	}
+
+	host.recordLatency(200 * time.Millisecond)
+
+	if host.AvgLatency() != 120*time.Millisecond {
+		t.Errorf("expected 120ms, got %s", host.AvgLatency())
+	}
+}
+
+func TestHost_Close(t *testing.T) {
+	host, err := NewHost(nil, "http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host.healthCheckPolicy.Open()
+	host.Close()
+
+	if host.State() != circuitbreaker.ClosedState {
+		t.Fatalf("expected the circuit breaker to be closed, got: %s", host.State())
+	}
+
+	// The second close is a no-op.
+	host.healthCheckPolicy.Open()
+	host.Close()
+
+	if host.State() != circuitbreaker.OpenState {
+		t.Errorf("expected the circuit breaker to stay open, got: %s", host.State())
+	}
+}
+
+func TestHost_RateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limitedHost, err := NewHost(
+		server.Client(),
+		server.URL,
+		WithHostRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherHost, err := NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doRequest := func(t *testing.T, host *Host, timeout time.Duration) error {
+		t.Helper()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := host.NewRequest(ctx, http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host.Do(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		return err
+	}
+
+	if err := doRequest(t, limitedHost, time.Second); err != nil {
+		t.Fatalf("expected the first request to be permitted, got: %v", err)
+	}
+
+	if err := doRequest(t, limitedHost, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the second request to be throttled, got: %v", err)
+	}
+
+	if limitedHost.ActiveRequests() != 0 {
+		t.Errorf("expected no active requests, got: %d", limitedHost.ActiveRequests())
+	}
+
+	// Other hosts are not throttled.
+	for range 3 {
+		if err := doRequest(t, otherHost, time.Second); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+	}
+}
+
+func TestHost_DoWithServerName(t *testing.T) {
+	serverNames := make(chan string, 1)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverNames <- hello.ServerName
+
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	host, err := NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatalf("failed to create host: %v", err)
+	}
+
+	req, err := host.NewRequest(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := host.DoWithServerName(req, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if host.ActiveRequests() != 1 {
+		t.Errorf("expected 1 active request, got %d", host.ActiveRequests())
+	}
+
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if host.ActiveRequests() != 0 {
+		t.Errorf("expected 0 active requests, got %d", host.ActiveRequests())
+	}
+
+	if serverName := <-serverNames; serverName != "example.com" {
+		t.Errorf("expected SNI example.com, got: %q", serverName)
+	}
+}
+
+func TestHost_Do_FailureStatuses(t *testing.T) {
+	failureThreshold := 1
+
+	testCases := []struct {
+		name            string
+		status          int
+		failureStatuses []int
+		expectedState   circuitbreaker.State
+	}{
+		{
+			name:          "opens on status >= 500 by default",
+			status:        http.StatusInternalServerError,
+			expectedState: circuitbreaker.OpenState,
+		},
+		{
+			name:            "stays closed on excluded status 500",
+			status:          http.StatusInternalServerError,
+			failureStatuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			expectedState:   circuitbreaker.ClosedState,
+		},
+		{
+			name:            "opens on custom status 599",
+			status:          599,
+			failureStatuses: []int{599},
+			expectedState:   circuitbreaker.OpenState,
+		},
+		{
+			name:            "opens on included status < 500",
+			status:          http.StatusTooManyRequests,
+			failureStatuses: []int{http.StatusTooManyRequests},
+			expectedState:   circuitbreaker.OpenState,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			builder, err := HTTPHealthCheckConfig{
+				Path:             "/",
+				FailureThreshold: &failureThreshold,
+				FailureStatuses:  tc.failureStatuses,
+			}.ToPolicyBuilder()
+			if err != nil {
+				t.Fatalf("failed to create policy builder: %v", err)
+			}
+
+			host, err := NewHost(&http.Client{}, server.URL, WithHTTPHealthCheckPolicyBuilder(builder))
+			if err != nil {
+				t.Fatalf("failed to create host: %v", err)
+			}
+
+			defer host.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			resp, err := host.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_ = resp.Body.Close()
+
+			if state := host.State(); state != tc.expectedState {
+				t.Errorf("expected state %v, got %v", tc.expectedState, state)
+			}
+		})
+	}
+
+	t.Run("opens on transport errors", func(t *testing.T) {
+		builder := NewHTTPHealthCheckPolicyBuilder().
+			WithFailureThreshold(1).
+			WithFailureStatuses(599)
+
+		host, err := NewHost(&http.Client{}, "http://127.0.0.1:1", WithHTTPHealthCheckPolicyBuilder(builder))
+		if err != nil {
+			t.Fatalf("failed to create host: %v", err)
+		}
+
+		defer host.Close()
+
+		req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:1", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		_, err = host.Do(req) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected transport error, got nil")
+		}
+
+		if state := host.State(); state != circuitbreaker.OpenState {
+			t.Errorf("expected state %v, got %v", circuitbreaker.OpenState, state)
+		}
+	})
+}
+
+func TestHost_ResponseHeaderRewrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Legacy-Request-Id", "abc")
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	legacyHost, err := NewHost(
+		server.Client(),
+		server.URL,
+		WithHostResponseHeaderRewrite(map[string]string{
+			"x-legacy-request-id": "X-Request-Id",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherHost, err := NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doRequest := func(t *testing.T, host *Host) http.Header {
+		t.Helper()
+
+		req, err := host.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = resp.Body.Close()
+
+		return resp.Header
+	}
+
+	header := doRequest(t, legacyHost)
+	if header.Get("X-Request-Id") != "abc" {
+		t.Errorf("expected the renamed header X-Request-Id to be abc, got: %s", header.Get("X-Request-Id"))
+	}
+
+	if _, ok := header["X-Legacy-Request-Id"]; ok {
+		t.Error("expected the original header to be removed")
+	}
+
+	if header.Get("Content-Type") != "text/plain" {
+		t.Errorf("expected other headers to be untouched, got: %s", header.Get("Content-Type"))
+	}
+
+	header = doRequest(t, otherHost)
+	if header.Get("X-Legacy-Request-Id") != "abc" || header.Get("X-Request-Id") != "" {
+		t.Errorf("expected headers of other hosts to be untouched, got: %v", header)
+	}
+}
+
+func TestHost_NewRequest_JoinURL(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		BaseURL  string
+		Ref      string
+		Expected string
+	}{
+		{Name: "empty_ref", BaseURL: "https://example.com/api/", Ref: "", Expected: "https://example.com/api"},
+		{Name: "root_ref", BaseURL: "https://example.com/api", Ref: "/", Expected: "https://example.com/api"},
+		{Name: "leading_slash", BaseURL: "https://example.com/api", Ref: "/users", Expected: "https://example.com/api/users"},
+		{Name: "no_slash", BaseURL: "https://example.com/api/", Ref: "users", Expected: "https://example.com/api/users"},
+		{Name: "absolute", BaseURL: "https://example.com/api", Ref: "http://other.com/x", Expected: "http://other.com/x"},
+		{Name: "http_prefixed_path", BaseURL: "https://example.com", Ref: "http-status", Expected: "https://example.com/http-status"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			host, err := NewHost(&http.Client{}, tc.BaseURL)
+			if err != nil {
+				t.Fatalf("failed to create host: %v", err)
+			}
+
+			req, err := host.NewRequest(context.Background(), http.MethodGet, tc.Ref, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if req.URL.String() != tc.Expected {
+				t.Errorf("expected %s, got: %s", tc.Expected, req.URL.String())
+			}
+		})
+	}
+}
+
+func TestHost_CheckHealth_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer listener.Close()
+
+	// Reserve a port and close it so the dial is refused.
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closedAddress := closedListener.Addr().String()
+	_ = closedListener.Close()
+
+	threshold := 1
+	timeout := 1
+
+	newHost := func(t *testing.T, address string) *Host {
+		t.Helper()
+
+		builder, err := HTTPHealthCheckConfig{
+			Protocol:         HealthCheckProtocolTCP,
+			Timeout:          &timeout,
+			FailureThreshold: &threshold,
+		}.ToPolicyBuilder()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		host, err := NewHost(
+			&http.Client{},
+			"http://"+address,
+			WithHTTPHealthCheckPolicyBuilder(builder),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(host.Close)
+
+		return host
+	}
+
+	t.Run("listening_port", func(t *testing.T) {
+		host := newHost(t, listener.Addr().String())
+		host.CheckHealth(context.Background())
+
+		if host.State() != circuitbreaker.ClosedState {
+			t.Errorf("expected the circuit breaker to be closed, got %v", host.State())
+		}
+	})
+
+	t.Run("closed_port", func(t *testing.T) {
+		host := newHost(t, closedAddress)
+		host.CheckHealth(context.Background())
+
+		if host.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the circuit breaker to be open, got %v", host.State())
+		}
+	})
+}
@@ -0,0 +1,269 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leastresponsetime implements the least response time load balancing algorithm.
+package leastresponsetime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/goutils"
+)
+
+// DefaultProbeInterval is the default duration after which a host that hasn't been selected is probed again.
+const DefaultProbeInterval = 10 * time.Second
+
+// LeastResponseTime represents the load balancer which selects the host
+// with the lowest average response time multiplied by the in-flight load, weighted by the host weight.
+// Hosts that haven't been selected for the probe interval are probed again to refresh their average.
+type LeastResponseTime struct {
+	leastResponseTimeOptions
+
+	lock  sync.Mutex
+	hosts []*loadbalancer.Host
+	// lastSelected stores the last time each host was selected.
+	lastSelected map[*loadbalancer.Host]time.Time
+	tick         *time.Ticker
+	// closed is true if hosts were closed.
+	closed bool
+}
+
+var _ loadbalancer.LoadBalancer = (*LeastResponseTime)(nil)
+
+// NewLeastResponseTime creates a new Least Response Time
+// load balancer instance with the given hosts slice and optional configuration.
+func NewLeastResponseTime(
+	hosts []*loadbalancer.Host,
+	options ...LeastResponseTimeOption,
+) (*LeastResponseTime, error) {
+	lrt := &LeastResponseTime{
+		leastResponseTimeOptions: leastResponseTimeOptions{
+			probeInterval: DefaultProbeInterval,
+		},
+	}
+
+	for _, opt := range options {
+		opt(&lrt.leastResponseTimeOptions)
+	}
+
+	err := lrt.Refresh(hosts)
+
+	return lrt, err
+}
+
+// Next returns the healthy host with the lowest score, see [responseTimeScore].
+// Ties are broken by the number of in-flight requests. A host that hasn't been selected
+// for the probe interval is returned first so its average response time is measured again.
+func (lrt *LeastResponseTime) Next() (*loadbalancer.Host, error) {
+	lrt.lock.Lock()
+	defer lrt.lock.Unlock()
+
+	switch len(lrt.hosts) {
+	case 0:
+		return nil, loadbalancer.ErrNoActiveHost
+	case 1:
+		// Return the only host directly.
+		return lrt.hosts[0], nil
+	default:
+		return lrt.nextLeastResponseTime(), nil
+	}
+}
+
+// Refresh resets the existing values with the given [Host] slice to refresh it.
+func (lrt *LeastResponseTime) Refresh(hosts []*loadbalancer.Host) error {
+	if hosts == nil {
+		return nil
+	}
+
+	lrt.lock.Lock()
+	defer lrt.lock.Unlock()
+
+	now := time.Now()
+	lastSelected := make(map[*loadbalancer.Host]time.Time, len(hosts))
+
+	for _, host := range hosts {
+		selectedAt, ok := lrt.lastSelected[host]
+		if !ok {
+			selectedAt = now
+		}
+
+		lastSelected[host] = selectedAt
+	}
+
+	lrt.hosts = hosts
+	lrt.lastSelected = lastSelected
+
+	return nil
+}
+
+// Close method does the cleanup by stopping the [time.Ticker] on the load balancer and closing hosts.
+// It is safe to call Close multiple times.
+func (lrt *LeastResponseTime) Close() error {
+	lrt.lock.Lock()
+	defer lrt.lock.Unlock()
+
+	if lrt.tick != nil {
+		lrt.tick.Stop()
+		lrt.tick = nil
+	}
+
+	if lrt.closed {
+		return nil
+	}
+
+	lrt.closed = true
+
+	for _, host := range lrt.hosts {
+		host.Close()
+	}
+
+	return nil
+}
+
+// Hosts return the list of hosts of the load balancer.
+func (lrt *LeastResponseTime) Hosts() []*loadbalancer.Host {
+	lrt.lock.Lock()
+	defer lrt.lock.Unlock()
+
+	return lrt.hosts
+}
+
+// StartHealthCheck starts a ticker to run health checking for servers in the background.
+func (lrt *LeastResponseTime) StartHealthCheck(ctx context.Context) {
+	if lrt.healthCheckInterval <= 0 {
+		return
+	}
+
+	newTicker := time.NewTicker(lrt.healthCheckInterval)
+
+	lrt.lock.Lock()
+
+	if lrt.tick != nil {
+		// Stop the ticker of the previous health check.
+		lrt.tick.Stop()
+	}
+
+	lrt.tick = newTicker
+	lrt.lock.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			goutils.CatchWarnErrorFunc(lrt.Close)
+
+			return
+		case <-newTicker.C:
+			for _, host := range lrt.Hosts() {
+				host.CheckHealth(ctx)
+			}
+		}
+	}
+}
+
+// nextLeastResponseTime returns the host with the lowest score among available hosts.
+func (lrt *LeastResponseTime) nextLeastResponseTime() *loadbalancer.Host {
+	var best, fallbackHost, probeHost *loadbalancer.Host
+
+	var bestScore float64
+
+	now := time.Now()
+	probeSelectedAt := now
+
+	for _, h := range lrt.hosts {
+		policy := h.HealthCheckPolicy()
+		if policy != nil && policy.State() == circuitbreaker.OpenState {
+			// checks if the open state is expired.
+			if !policy.TryAcquirePermit() {
+				_, isOutage := h.GetLastHTTPErrorStatus()
+				if !isOutage {
+					fallbackHost = h
+				}
+
+				continue
+			}
+		}
+
+		if lrt.probeInterval > 0 && h.AvgLatency() > 0 {
+			// Probes the host which has been idle for the longest time.
+			selectedAt := lrt.lastSelected[h]
+			if now.Sub(selectedAt) >= lrt.probeInterval && selectedAt.Before(probeSelectedAt) {
+				probeHost = h
+				probeSelectedAt = selectedAt
+			}
+		}
+
+		score := responseTimeScore(h)
+
+		if best == nil || score < bestScore ||
+			(score == bestScore && h.ActiveRequests() < best.ActiveRequests()) {
+			best = h
+			bestScore = score
+		}
+	}
+
+	if probeHost != nil {
+		best = probeHost
+	}
+
+	if best != nil {
+		lrt.lastSelected[best] = now
+
+		return best
+	}
+
+	if fallbackHost == nil {
+		fallbackHost = lrt.hosts[0]
+	}
+
+	return fallbackHost
+}
+
+// responseTimeScore returns the average latency of the host multiplied by the in-flight load
+// including the new request, divided by its weight. The in-flight load spreads a burst of
+// concurrent requests across hosts instead of sending all of them to the fastest one.
+// Hosts without any latency observation have the score of zero so they are probed first.
+func responseTimeScore(host *loadbalancer.Host) float64 {
+	weight := max(host.Weight(), 1)
+	load := max(host.ActiveRequests(), 0) + 1
+
+	return float64(host.AvgLatency()) * float64(load) / float64(weight)
+}
+
+type leastResponseTimeOptions struct {
+	healthCheckInterval time.Duration
+	probeInterval       time.Duration
+}
+
+// LeastResponseTimeOption represents a function to modify the Least Response Time options.
+type LeastResponseTimeOption func(*leastResponseTimeOptions)
+
+// WithHealthCheckInterval sets the health check interval for the load balancer.
+func WithHealthCheckInterval(duration time.Duration) LeastResponseTimeOption {
+	return func(o *leastResponseTimeOptions) {
+		o.healthCheckInterval = max(duration, 0)
+	}
+}
+
+// WithProbeInterval sets the duration after which a host that hasn't been selected is probed again,
+// so a host that was slow once can recover its share of traffic. Defaults to [DefaultProbeInterval].
+// Zero disables probing.
+func WithProbeInterval(duration time.Duration) LeastResponseTimeOption {
+	return func(o *leastResponseTimeOptions) {
+		o.probeInterval = max(duration, 0)
+	}
+}
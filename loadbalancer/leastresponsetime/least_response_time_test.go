@@ -0,0 +1,407 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leastresponsetime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
+	"github.com/relychan/gohttpc/loadbalancer"
+	"github.com/relychan/goutils"
+)
+
+func TestLeastResponseTime_Next(t *testing.T) {
+	t.Run("returns error when there is no host", func(t *testing.T) {
+		lrt, err := NewLeastResponseTime([]*loadbalancer.Host{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = lrt.Next()
+		if !errors.Is(err, loadbalancer.ErrNoActiveHost) {
+			t.Errorf("expected ErrNoActiveHost, got: %v", err)
+		}
+	})
+
+	t.Run("skips hosts with open circuit and outage status", func(t *testing.T) {
+		builder := loadbalancer.NewHTTPHealthCheckPolicyBuilder().
+			WithFailureThreshold(1).
+			WithInterval(time.Minute)
+
+		host1, err := loadbalancer.NewHost(
+			nil,
+			"https://example1.com",
+			loadbalancer.WithHTTPHealthCheckPolicyBuilder(builder),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		host2, err := loadbalancer.NewHost(nil, "https://example2.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		host1.SetHTTPClient(server.Client())
+		_, _ = host1.SetURL(server.URL)
+
+		req, err := host1.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host1.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if host1.State() != circuitbreaker.OpenState {
+			t.Fatalf("expected the circuit breaker to be open, got: %v", host1.State())
+		}
+
+		lrt, err := NewLeastResponseTime([]*loadbalancer.Host{host1, host2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for range 5 {
+			host, err := lrt.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if host != host2 {
+				t.Fatalf("expected host %s, got: %s", host2.URL(), host.URL())
+			}
+		}
+	})
+
+	t.Run("prefers hosts without latency observations", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		host1, err := loadbalancer.NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		host2, err := loadbalancer.NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lrt, err := NewLeastResponseTime([]*loadbalancer.Host{host1, host2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, err := host1.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host1.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if host1.AvgLatency() <= 0 {
+			t.Fatalf("expected a positive average latency, got: %s", host1.AvgLatency())
+		}
+
+		host, err := lrt.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != host2 {
+			t.Errorf("expected the unmeasured host to be selected, got: %s", host.URL())
+		}
+	})
+}
+
+func TestLeastResponseTime_Integration(t *testing.T) {
+	var fastCount, slowCount atomic.Int32
+
+	fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fastCount.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fastServer.Close()
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowCount.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	slowHost, err := loadbalancer.NewHost(slowServer.Client(), slowServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fastHost, err := loadbalancer.NewHost(fastServer.Client(), fastServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lrt, err := NewLeastResponseTime([]*loadbalancer.Host{slowHost, fastHost})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(lrt)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	total := 30
+
+	for range total {
+		resp, err := client.R(http.MethodGet, "/").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	if fastCount.Load()+slowCount.Load() != int32(total) {
+		t.Fatalf("expected %d requests, got: %d", total, fastCount.Load()+slowCount.Load())
+	}
+
+	if fastCount.Load() <= int32(total)/2 {
+		t.Errorf(
+			"expected the fast host to receive the majority of traffic, got fast: %d, slow: %d",
+			fastCount.Load(),
+			slowCount.Load(),
+		)
+	}
+}
+
+func TestLeastResponseTime_DoubleCloseKeepsLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	host, err := loadbalancer.NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lrt, err := NewLeastResponseTime([]*loadbalancer.Host{host})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(lrt)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, "/").Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if host.ActiveRequests() != 1 {
+		t.Errorf("expected 1 active request, got: %d", host.ActiveRequests())
+	}
+
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+
+	if host.ActiveRequests() != 0 {
+		t.Errorf("expected the load to be released once, got: %d", host.ActiveRequests())
+	}
+}
+
+func TestLeastResponseTime_ConcurrentBurst(t *testing.T) {
+	release := make(chan struct{})
+
+	// Both paths take the same time so held requests keep the average latency of hosts comparable.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		if r.URL.Path == "/hold" {
+			<-release
+		}
+	}))
+	defer server.Close()
+
+	host1, err := loadbalancer.NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host2, err := loadbalancer.NewHost(server.Client(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, host := range []*loadbalancer.Host{host1, host2} {
+		req, err := host.NewRequest(context.Background(), http.MethodGet, "/warm", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	lrt, err := NewLeastResponseTime([]*loadbalancer.Host{host1, host2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[*loadbalancer.Host]int{}
+	responses := []*http.Response{}
+
+	defer func() {
+		close(release)
+
+		for _, resp := range responses {
+			goutils.CloseResponse(resp)
+		}
+	}()
+
+	for range 10 {
+		host, err := lrt.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		counts[host]++
+
+		req, err := host.NewRequest(context.Background(), http.MethodGet, "/hold", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		responses = append(responses, resp)
+	}
+
+	if counts[host1] < 2 || counts[host2] < 2 {
+		t.Errorf(
+			"expected the burst to be spread across hosts, got host1: %d, host2: %d",
+			counts[host1],
+			counts[host2],
+		)
+	}
+}
+
+func TestLeastResponseTime_ProbesRecoveredHost(t *testing.T) {
+	var recovered atomic.Bool
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !recovered.Load() {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fastServer.Close()
+
+	slowHost, err := loadbalancer.NewHost(slowServer.Client(), slowServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fastHost, err := loadbalancer.NewHost(fastServer.Client(), fastServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probeInterval := 30 * time.Millisecond
+
+	lrt, err := NewLeastResponseTime(
+		[]*loadbalancer.Host{slowHost, fastHost},
+		WithProbeInterval(probeInterval),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := loadbalancer.NewLoadBalancerClient(lrt)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	execute := func() {
+		t.Helper()
+
+		resp, err := client.R(http.MethodGet, "/").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	// Measures both hosts.
+	execute()
+	execute()
+
+	slowLatency := slowHost.AvgLatency()
+	if slowLatency < 50*time.Millisecond {
+		t.Fatalf("expected the slow host to be measured, got: %s", slowLatency)
+	}
+
+	recovered.Store(true)
+
+	// Idle hosts are probed after the interval, so the recovered host is measured again.
+	for range 20 {
+		time.Sleep(probeInterval)
+		execute()
+	}
+
+	if slowHost.AvgLatency() >= slowLatency/2 {
+		t.Errorf(
+			"expected the average latency of the recovered host to decrease, got: %s, before: %s",
+			slowHost.AvgLatency(),
+			slowLatency,
+		)
+	}
+}
@@ -17,12 +17,26 @@ package loadbalancer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/authc/authscheme"
 )
 
-// ErrNoActiveHost occurs when all hosts are inactive on the load balancer.
-var ErrNoActiveHost = errors.New("no active host")
+var (
+	// ErrNoActiveHost occurs when all hosts are inactive on the load balancer.
+	ErrNoActiveHost = errors.New("no active host")
+	// ErrNotEnoughHealthyHosts occurs when the load balancer doesn't have the minimum number of healthy hosts.
+	ErrNotEnoughHealthyHosts = errors.New("not enough healthy hosts")
+)
+
+// waitHealthyInterval is the interval between health check rounds of [LoadBalancerClient.WaitHealthy].
+const waitHealthyInterval = 200 * time.Millisecond
 
 // LoadBalancer is the interface that wraps the HTTP client load-balancing
 // algorithm that returns the appropriate host for the request to target.
@@ -34,6 +48,39 @@ type LoadBalancer interface {
 	Close() error
 }
 
+// KeyedLoadBalancer is the extended [LoadBalancer] interface which selects the host by an affinity key,
+// so requests with the same key prefer the same host.
+type KeyedLoadBalancer interface {
+	LoadBalancer
+
+	// NextWithKey returns the host for the affinity key.
+	NextWithKey(key string) (*Host, error)
+}
+
+// HintedLoadBalancer is the extended [LoadBalancer] interface which dispatches requests
+// to sub load balancers by the balancer hint of the request. See [gohttpc.Request.SetBalancerHint].
+type HintedLoadBalancer interface {
+	LoadBalancer
+
+	// ForHint returns the load balancer for the hint.
+	ForHint(hint string) LoadBalancer
+}
+
+type affinityKeyContextKey struct{}
+
+// WithAffinityKey returns a copy of the context with the affinity key
+// which is used by [KeyedLoadBalancer] to select the host for the request.
+func WithAffinityKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, affinityKeyContextKey{}, key)
+}
+
+// GetAffinityKey gets the affinity key from the context.
+func GetAffinityKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(affinityKeyContextKey{}).(string)
+
+	return key, ok
+}
+
 // LoadBalancerClient represents an HTTP client that accepts a list of hosts
 // and load balance requests to each host.
 type LoadBalancerClient struct {
@@ -73,6 +120,11 @@ func (lbc *LoadBalancerClient) R(method string, url string) *gohttpc.RequestWith
 
 // HTTPClient returns the current or inner HTTP client for load balancing.
 func (lbc *LoadBalancerClient) HTTPClient() (gohttpc.HTTPClient, error) {
+	switch lbc.loadBalancer.(type) {
+	case KeyedLoadBalancer, HintedLoadBalancer:
+		return &affinityClient{loadBalancer: lbc.loadBalancer}, nil
+	}
+
 	return lbc.loadBalancer.Next()
 }
 
@@ -85,7 +137,86 @@ func (lbc *LoadBalancerClient) StartHealthCheck(ctx context.Context) {
 	lbc.loadBalancer.StartHealthCheck(ctx)
 }
 
-// ServerMetrics returns summary metrics of server hosts.
+// WaitHealthy runs health checks on all hosts immediately and repeats them until
+// at least minHealthy hosts pass their latest probe and are not in the open state, or the context is done.
+// It is useful to gate the readiness of the service at startup.
+func (lbc *LoadBalancerClient) WaitHealthy(ctx context.Context, minHealthy int) error {
+	var hosts []*Host
+
+	if lbc.loadBalancer != nil {
+		hosts = lbc.loadBalancer.Hosts()
+	}
+
+	minHealthy = max(minHealthy, 1)
+
+	if len(hosts) < minHealthy {
+		return fmt.Errorf(
+			"%w: requires %d healthy hosts, the load balancer has %d hosts",
+			ErrNotEnoughHealthyHosts,
+			minHealthy,
+			len(hosts),
+		)
+	}
+
+	ticker := time.NewTicker(waitHealthyInterval)
+	defer ticker.Stop()
+
+	for {
+		healthy := checkHostsHealth(ctx, hosts)
+		if healthy >= minHealthy {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf(
+				"%w: %d of %d required hosts are healthy: %w",
+				ErrNotEnoughHealthyHosts,
+				healthy,
+				minHealthy,
+				context.Cause(ctx),
+			)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHostsHealth runs health checks on hosts concurrently and returns the number of hosts
+// which passed the probe and are not open. A host whose circuit breaker hasn't opened yet
+// isn't healthy if the probe failed, e.g. it always returns 503 below the failure threshold.
+func checkHostsHealth(ctx context.Context, hosts []*Host) int {
+	var wg sync.WaitGroup
+
+	passed := make([]bool, len(hosts))
+
+	for i, host := range hosts {
+		wg.Go(func() {
+			policy := host.HealthCheckPolicy()
+			if policy != nil && policy.IsOpen() {
+				// Moves to the half-open state if the delay of the open state was expired
+				// so that the health check result can close the circuit breaker.
+				policy.TryAcquirePermit()
+			}
+
+			passed[i] = host.checkHealth(ctx)
+		})
+	}
+
+	wg.Wait()
+
+	var healthy int
+
+	for i, host := range hosts {
+		if passed[i] && host.State() != circuitbreaker.OpenState {
+			healthy++
+		}
+	}
+
+	return healthy
+}
+
+// ServerMetrics returns live circuit breaker metrics of server hosts, keyed by the host name.
+// The URL is used as the key if the host has no name. Hosts without health check policies are skipped.
 func (lbc *LoadBalancerClient) ServerMetrics() map[string]ServerMetrics {
 	result := make(map[string]ServerMetrics)
 
@@ -96,7 +227,12 @@ func (lbc *LoadBalancerClient) ServerMetrics() map[string]ServerMetrics {
 
 		metrics := server.healthCheckPolicy.Metrics()
 
-		result[server.url] = ServerMetrics{
+		key := server.name
+		if key == "" {
+			key = server.url
+		}
+
+		result[key] = ServerMetrics{
 			Executions:  metrics.Executions(),
 			Failures:    metrics.Failures(),
 			FailureRate: metrics.FailureRate(),
@@ -116,3 +252,79 @@ func (lbc *LoadBalancerClient) Close() error {
 
 	return lbc.loadBalancer.Close()
 }
+
+// affinityClient selects the host of the [KeyedLoadBalancer] or [HintedLoadBalancer] when the request is created.
+// The affinity key and the balancer hint are taken from the context. The affinity key defaults to the request URL.
+type affinityClient struct {
+	loadBalancer LoadBalancer
+	host         *Host
+}
+
+// NewRequest returns a new http.Request given a method, URL, and optional body.
+func (ac *affinityClient) NewRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	body io.Reader,
+) (*http.Request, error) {
+	host, err := nextHost(ctx, ac.loadBalancer, url)
+	if err != nil {
+		return nil, err
+	}
+
+	ac.host = host
+
+	return host.NewRequest(ctx, method, url, body)
+}
+
+// Do sends an HTTP request to the host which was selected when the request was created.
+func (ac *affinityClient) Do(req *http.Request) (*http.Response, error) {
+	if ac.host == nil {
+		return nil, ErrNoActiveHost
+	}
+
+	return ac.host.Do(req)
+}
+
+// Authenticator returns the authenticator of the host which was selected when the request was created.
+func (ac *affinityClient) Authenticator() authscheme.HTTPClientAuthenticator {
+	if ac.host == nil {
+		return nil
+	}
+
+	return ac.host.authenticator
+}
+
+// DoWithServerName sends an HTTP request with the TLS server name overridden
+// to the host which was selected when the request was created.
+func (ac *affinityClient) DoWithServerName(req *http.Request, serverName string) (*http.Response, error) {
+	if ac.host == nil {
+		return nil, ErrNoActiveHost
+	}
+
+	return ac.host.DoWithServerName(req, serverName)
+}
+
+// nextHost selects the host for the request. The sub load balancer of [HintedLoadBalancer] is resolved
+// by the balancer hint, and [KeyedLoadBalancer] selects the host by the affinity key of the context.
+func nextHost(ctx context.Context, loadBalancer LoadBalancer, url string) (*Host, error) {
+	if hinted, ok := loadBalancer.(HintedLoadBalancer); ok {
+		hint, _ := gohttpc.GetBalancerHint(ctx)
+
+		loadBalancer = hinted.ForHint(hint)
+		if loadBalancer == nil {
+			return nil, ErrNoActiveHost
+		}
+	}
+
+	if keyed, ok := loadBalancer.(KeyedLoadBalancer); ok {
+		key, ok := GetAffinityKey(ctx)
+		if !ok {
+			key = url
+		}
+
+		return keyed.NextWithKey(key)
+	}
+
+	return loadBalancer.Next()
+}
@@ -19,10 +19,14 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/authc/authscheme"
 )
 
 // mockLoadBalancer is a mock implementation of LoadBalancer for testing.
@@ -310,6 +314,141 @@ func TestLoadBalancerClient_Close(t *testing.T) {
 	})
 }
 
+func TestLoadBalancerClient_WaitHealthy(t *testing.T) {
+	// newHosts creates hosts whose health checks fail until the delay is elapsed.
+	newHosts := func(t *testing.T, delays ...time.Duration) []*Host {
+		t.Helper()
+
+		hosts := make([]*Host, len(delays))
+
+		for i, delay := range delays {
+			var ready atomic.Bool
+
+			time.AfterFunc(delay, func() {
+				ready.Store(true)
+			})
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if !ready.Load() {
+					w.WriteHeader(http.StatusServiceUnavailable)
+
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			builder := NewHTTPHealthCheckPolicyBuilder().
+				WithFailureThreshold(1).
+				WithInterval(50 * time.Millisecond)
+
+			host, err := NewHost(server.Client(), server.URL, WithHTTPHealthCheckPolicyBuilder(builder))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hosts[i] = host
+		}
+
+		return hosts
+	}
+
+	t.Run("returns once the threshold is met", func(t *testing.T) {
+		hosts := newHosts(t, 300*time.Millisecond, 400*time.Millisecond, time.Hour)
+		client := NewLoadBalancerClient(&mockLoadBalancer{hosts: hosts})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		err := client.WaitHealthy(ctx, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+			t.Errorf("expected to wait until 2 hosts are healthy, returned after %s", elapsed)
+		}
+
+		if hosts[2].State() != circuitbreaker.OpenState {
+			t.Errorf("expected the unhealthy host to be open, got: %s", hosts[2].State())
+		}
+	})
+
+	t.Run("returns immediately if hosts are healthy", func(t *testing.T) {
+		hosts := newHosts(t, 0, 0)
+		client := NewLoadBalancerClient(&mockLoadBalancer{hosts: hosts})
+
+		time.Sleep(10 * time.Millisecond)
+
+		start := time.Now()
+
+		err := client.WaitHealthy(context.Background(), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed >= waitHealthyInterval {
+			t.Errorf("expected to return immediately, returned after %s", elapsed)
+		}
+	})
+
+	t.Run("returns error when the context is done", func(t *testing.T) {
+		hosts := newHosts(t, time.Hour)
+		client := NewLoadBalancerClient(&mockLoadBalancer{hosts: hosts})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		err := client.WaitHealthy(ctx, 1)
+		if !errors.Is(err, ErrNotEnoughHealthyHosts) {
+			t.Errorf("expected ErrNotEnoughHealthyHosts, got %v", err)
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("requires a successful probe with the default failure threshold", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		host, err := NewHost(
+			server.Client(),
+			server.URL,
+			WithHTTPHealthCheckPolicyBuilder(NewHTTPHealthCheckPolicyBuilder()),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		client := NewLoadBalancerClient(&mockLoadBalancer{hosts: []*Host{host}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		err = client.WaitHealthy(ctx, 1)
+		if !errors.Is(err, ErrNotEnoughHealthyHosts) {
+			t.Errorf("expected ErrNotEnoughHealthyHosts, got %v", err)
+		}
+	})
+
+	t.Run("returns error when there are not enough hosts", func(t *testing.T) {
+		hosts := newHosts(t, 0)
+		client := NewLoadBalancerClient(&mockLoadBalancer{hosts: hosts})
+
+		err := client.WaitHealthy(context.Background(), 2)
+		if !errors.Is(err, ErrNotEnoughHealthyHosts) {
+			t.Errorf("expected ErrNotEnoughHealthyHosts, got %v", err)
+		}
+	})
+}
+
 func TestErrNoActiveHost(t *testing.T) {
 	t.Run("error message is correct", func(t *testing.T) {
 		expected := "no active host"
@@ -401,3 +540,270 @@ func TestLoadBalancerClient_Integration(t *testing.T) {
 		}
 	})
 }
+
+func TestLoadBalancerClient_ServerMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host, err := NewHost(
+		server.Client(),
+		server.URL,
+		WithHTTPHealthCheckPolicyBuilder(NewHTTPHealthCheckPolicyBuilder().WithFailureThreshold(5)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host.SetName("primary")
+
+	idleHost, err := NewHost(server.Client(), server.URL+"/idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idleHost.SetName("idle")
+
+	client := NewLoadBalancerClient(&mockLoadBalancer{hosts: []*Host{host, idleHost}})
+
+	for _, path := range []string{"/fail", "/fail", "/", "/", "/"} {
+		req, err := host.NewRequest(context.Background(), http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	metrics := client.ServerMetrics()
+
+	if len(metrics) != 2 {
+		t.Fatalf("expected metrics of 2 hosts, got: %v", metrics)
+	}
+
+	expected := ServerMetrics{
+		Executions:  5,
+		Failures:    2,
+		FailureRate: 0.4,
+		Successes:   3,
+		SuccessRate: 0.6,
+	}
+
+	if metrics["primary"] != expected {
+		t.Errorf("expected %+v, got: %+v", expected, metrics["primary"])
+	}
+
+	if metrics["idle"] != (ServerMetrics{}) {
+		t.Errorf("expected empty metrics of the idle host, got: %+v", metrics["idle"])
+	}
+}
+
+// staticAuthenticator sets a static bearer token to the Authorization header.
+type staticAuthenticator struct {
+	token string
+}
+
+func (sa staticAuthenticator) Authenticate(req *http.Request, _ ...authscheme.AuthenticateOption) error {
+	req.Header.Set("Authorization", "Bearer "+sa.token)
+
+	return nil
+}
+
+func (staticAuthenticator) Close() error {
+	return nil
+}
+
+func TestLoadBalancerClient_HostAuthenticator(t *testing.T) {
+	newServer := func(t *testing.T) (*httptest.Server, *atomic.Value) {
+		t.Helper()
+
+		var authorization atomic.Value
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization.Store(r.Header.Get("Authorization"))
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		return server, &authorization
+	}
+
+	server1, authorization1 := newServer(t)
+	server2, authorization2 := newServer(t)
+	server3, authorization3 := newServer(t)
+
+	host1, err := NewHost(server1.Client(), server1.URL, WithHostAuthenticator(staticAuthenticator{token: "host-1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host2, err := NewHost(server2.Client(), server2.URL, WithHostAuthenticator(staticAuthenticator{token: "host-2"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The host without authenticator uses the authenticator of the client.
+	host3, err := NewHost(server3.Client(), server3.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var next atomic.Int32
+
+	hosts := []*Host{host1, host2, host3}
+	lb := &mockLoadBalancer{
+		hosts: hosts,
+		nextFunc: func() (*Host, error) {
+			return hosts[int(next.Add(1)-1)%len(hosts)], nil
+		},
+	}
+
+	client := NewLoadBalancerClient(lb, gohttpc.WithAuthenticator(staticAuthenticator{token: "client"}))
+
+	for range hosts {
+		resp, err := client.R(http.MethodGet, "/").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	for i, tc := range []struct {
+		authorization *atomic.Value
+		expected      string
+	}{
+		{authorization1, "Bearer host-1"},
+		{authorization2, "Bearer host-2"},
+		{authorization3, "Bearer client"},
+	} {
+		if value := tc.authorization.Load(); value != tc.expected {
+			t.Errorf("host %d: expected %s, got: %v", i+1, tc.expected, value)
+		}
+	}
+
+	t.Run("request_authenticator_takes_precedence", func(t *testing.T) {
+		next.Store(0)
+
+		req := client.R(http.MethodGet, "/")
+		req.SetAuthenticator(staticAuthenticator{token: "request"})
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if value := authorization1.Load(); value != "Bearer request" {
+			t.Errorf("expected Bearer request, got: %v", value)
+		}
+	})
+
+	t.Run("disabled_auth_skips_the_host_authenticator", func(t *testing.T) {
+		next.Store(0)
+
+		req := client.R(http.MethodGet, "/")
+		req.DisableAuth()
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+
+		if value := authorization1.Load(); value != "" {
+			t.Errorf("expected no Authorization header, got: %v", value)
+		}
+	})
+}
+
+func TestLoadBalancerClient_CacheKeyFunc(t *testing.T) {
+	const etag = `"v1"`
+
+	var notModified atomic.Int32
+
+	newHost := func(t *testing.T, body string) *Host {
+		t.Helper()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+
+		host, err := NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return host
+	}
+
+	var next atomic.Int32
+
+	hosts := []*Host{newHost(t, "host-1"), newHost(t, "host-2")}
+	lb := &mockLoadBalancer{
+		hosts: hosts,
+		nextFunc: func() (*Host, error) {
+			return hosts[int(next.Add(1)-1)%len(hosts)], nil
+		},
+	}
+
+	cache := gohttpc.NewMemoryCache()
+	client := NewLoadBalancerClient(
+		lb,
+		gohttpc.WithResponseCache(cache),
+		gohttpc.WithCacheKeyFunc(func(req *gohttpc.Request) string {
+			return "todos:" + req.Method() + " " + req.URL()
+		}),
+	)
+
+	for i := range hosts {
+		resp, err := client.R(http.MethodGet, "/todos").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The second host revalidates the entry which was cached from the first host.
+		if string(body) != "host-1" {
+			t.Errorf("request %d: expected the body of host-1, got: %s", i, body)
+		}
+	}
+
+	if count := notModified.Load(); count != 1 {
+		t.Errorf("expected 1 not modified response, got: %d", count)
+	}
+
+	entry, err := cache.Get(context.Background(), "todos:GET /todos")
+	if err != nil || entry == nil {
+		t.Errorf("expected the entry of the custom key, got: %v, %v", entry, err)
+	}
+}
@@ -16,6 +16,8 @@ package roundrobin
 
 import (
 	"context"
+	"math/rand/v2"
+	"slices"
 	"sync"
 	"time"
 
@@ -34,6 +36,12 @@ type WeightedRoundRobin struct {
 	isSameWeight bool
 	totalWeight  int
 	tick         *time.Ticker
+	// closed is true if hosts were closed.
+	closed bool
+	// The number of requests which were served by each host since the last refresh.
+	served map[*loadbalancer.Host]int64
+	// The number of selections in the current weighted cycle.
+	cycleSelections int
 }
 
 var _ loadbalancer.LoadBalancer = (*WeightedRoundRobin)(nil)
@@ -71,11 +79,18 @@ func (wrr *WeightedRoundRobin) Next() (*loadbalancer.Host, error) {
 		// Return the only host directly.
 		return wrr.hosts[0], nil
 	default:
-		if wrr.isSameWeight {
-			return wrr.nextRoundRobin(), nil
+		filter := wrr.hostFilter
+		if filter != nil && !slices.ContainsFunc(wrr.hosts, filter) {
+			// Falls back to all hosts if every host is excluded by the filter.
+			filter = nil
 		}
 
-		return wrr.nextWeightRoundRobin(), nil
+		// Weights of hosts are adjusted by the reported load if the load header is set.
+		if wrr.isSameWeight && wrr.loadHeader == "" {
+			return wrr.nextRoundRobin(filter), nil
+		}
+
+		return wrr.nextWeightRoundRobin(filter), nil
 	}
 }
 
@@ -102,15 +117,23 @@ func (wrr *WeightedRoundRobin) Refresh(servers []*loadbalancer.Host) error {
 			isSameWeight = false
 		}
 
+		if wrr.loadHeader != "" {
+			h.SetLoadHeader(wrr.loadHeader)
+		}
+
 		hcPolicy := h.HealthCheckPolicy()
 		if hcPolicy == nil {
 			continue
 		}
 	}
 
+	wrr.inheritHostStates(servers)
+
 	// after processing, assign the updates
 	wrr.hosts = servers
 	wrr.isSameWeight = isSameWeight
+	wrr.served = make(map[*loadbalancer.Host]int64, len(servers))
+	wrr.cycleSelections = 0
 
 	if isSameWeight {
 		// Start the round robin algorithm since all weight are the same.
@@ -120,20 +143,29 @@ func (wrr *WeightedRoundRobin) Refresh(servers []*loadbalancer.Host) error {
 		wrr.totalWeight = newTotalWeight
 	}
 
+	if wrr.hostShuffle {
+		wrr.shuffleStart()
+	}
+
 	return nil
 }
 
-// Close method does the cleanup by stopping the [time.Ticker] on the load balancer.
+// Close method does the cleanup by stopping the [time.Ticker] on the load balancer and closing hosts.
+// It is safe to call Close multiple times.
 func (wrr *WeightedRoundRobin) Close() error {
 	wrr.lock.Lock()
 	defer wrr.lock.Unlock()
 
-	if wrr.tick == nil {
+	if wrr.tick != nil {
+		wrr.tick.Stop()
+		wrr.tick = nil
+	}
+
+	if wrr.closed {
 		return nil
 	}
 
-	wrr.tick.Stop()
-	wrr.tick = nil
+	wrr.closed = true
 
 	for _, host := range wrr.hosts {
 		host.Close()
@@ -156,13 +188,15 @@ func (wrr *WeightedRoundRobin) StartHealthCheck(ctx context.Context) {
 		return
 	}
 
-	if wrr.tick != nil {
-		goutils.CatchWarnErrorFunc(wrr.Close)
-	}
-
 	newTicker := time.NewTicker(wrr.healthCheckInterval)
 
 	wrr.lock.Lock()
+
+	if wrr.tick != nil {
+		// Stop the ticker of the previous health check.
+		wrr.tick.Stop()
+	}
+
 	wrr.tick = newTicker
 	wrr.lock.Unlock()
 
@@ -173,15 +207,72 @@ func (wrr *WeightedRoundRobin) StartHealthCheck(ctx context.Context) {
 
 			return
 		case <-newTicker.C:
-			for _, host := range wrr.Hosts() {
+			wrr.checkHealth(ctx)
+		}
+	}
+}
+
+// checkHealth runs health checks of hosts concurrently, bounded by the health check concurrency,
+// so a slow host doesn't delay probes of other hosts. If the jitter is set, each probe is delayed
+// by a random fraction of the interval so probes are spread across the interval.
+func (wrr *WeightedRoundRobin) checkHealth(ctx context.Context) {
+	hosts := wrr.Hosts()
+	maxDelay := time.Duration(wrr.healthCheckJitter * float64(wrr.healthCheckInterval))
+
+	concurrency := wrr.healthCheckConcurrency
+	if concurrency <= 0 || concurrency > len(hosts) {
+		concurrency = len(hosts)
+	}
+
+	workers := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		var delay time.Duration
+
+		if maxDelay > 0 {
+			delay = time.Duration(rand.Int64N(int64(maxDelay) + 1)) //nolint:gosec
+		}
+
+		wg.Go(func() {
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				defer timer.Stop()
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case workers <- struct{}{}:
+			}
+
+			defer func() {
+				<-workers
+			}()
+
+			wrr.lock.Lock()
+			closed := wrr.closed
+			wrr.lock.Unlock()
+
+			if !closed {
 				host.CheckHealth(ctx)
 			}
-		}
+		})
 	}
+
+	wg.Wait()
 }
 
 // Returns the next server based on the Round-Robin algorithm.
-func (rr *WeightedRoundRobin) nextRoundRobin() *loadbalancer.Host {
+// Hosts which are excluded by the filter are skipped.
+func (rr *WeightedRoundRobin) nextRoundRobin(filter func(*loadbalancer.Host) bool) *loadbalancer.Host {
 	totalServers := len(rr.hosts)
 
 	var fallbackHost *loadbalancer.Host
@@ -190,6 +281,10 @@ func (rr *WeightedRoundRobin) nextRoundRobin() *loadbalancer.Host {
 		currentIndex := (i + rr.totalWeight) % totalServers
 		server := rr.hosts[currentIndex]
 
+		if filter != nil && !filter(server) {
+			continue
+		}
+
 		policy := server.HealthCheckPolicy()
 		if policy != nil {
 			if policy.State() == circuitbreaker.OpenState {
@@ -212,6 +307,12 @@ func (rr *WeightedRoundRobin) nextRoundRobin() *loadbalancer.Host {
 
 	if fallbackHost == nil {
 		fallbackHost = rr.hosts[rr.totalWeight]
+
+		// Moves to the next host which isn't excluded by the filter.
+		for i := 0; filter != nil && i < totalServers && !filter(fallbackHost); i++ {
+			rr.totalWeight = (rr.totalWeight + 1) % totalServers
+			fallbackHost = rr.hosts[rr.totalWeight]
+		}
 	}
 
 	rr.totalWeight = (rr.totalWeight + 1) % totalServers
@@ -220,12 +321,17 @@ func (rr *WeightedRoundRobin) nextRoundRobin() *loadbalancer.Host {
 }
 
 // nextWeightRoundRobin returns the next server based on the Weighted Round-Robin algorithm.
-func (wrr *WeightedRoundRobin) nextWeightRoundRobin() *loadbalancer.Host {
+// Hosts which are excluded by the filter are skipped.
+func (wrr *WeightedRoundRobin) nextWeightRoundRobin(filter func(*loadbalancer.Host) bool) *loadbalancer.Host {
 	var best, fallbackHost *loadbalancer.Host
 
 	total := 0
 
 	for _, h := range wrr.hosts {
+		if filter != nil && !filter(h) {
+			continue
+		}
+
 		policy := h.HealthCheckPolicy()
 		if policy != nil {
 			if policy.State() == circuitbreaker.OpenState {
@@ -243,7 +349,7 @@ func (wrr *WeightedRoundRobin) nextWeightRoundRobin() *loadbalancer.Host {
 
 		h.AddCurrentWeight()
 
-		total += h.Weight()
+		total += h.EffectiveWeight()
 
 		if best == nil || h.CurrentWeight() > best.CurrentWeight() {
 			best = h
@@ -252,19 +358,145 @@ func (wrr *WeightedRoundRobin) nextWeightRoundRobin() *loadbalancer.Host {
 
 	if best != nil {
 		best.ResetCurrentWeight(total)
+		wrr.recordServed(best, filter)
 
 		return best
 	}
 
 	if fallbackHost == nil {
 		fallbackHost = wrr.hosts[0]
+
+		if filter != nil {
+			fallbackHost = wrr.hosts[slices.IndexFunc(wrr.hosts, filter)]
+		}
 	}
 
+	wrr.recordServed(fallbackHost, filter)
+
 	return fallbackHost
 }
 
+// recordServed counts the selected host and corrects current weights at the end of every weighted cycle.
+func (wrr *WeightedRoundRobin) recordServed(host *loadbalancer.Host, filter func(*loadbalancer.Host) bool) {
+	if wrr.loadHeader != "" {
+		// The configured ratio doesn't apply if weights are adjusted by the reported load.
+		return
+	}
+
+	wrr.served[host]++
+	wrr.cycleSelections++
+
+	if wrr.cycleSelections < wrr.totalWeight {
+		return
+	}
+
+	wrr.cycleSelections = 0
+	wrr.correctFairness(filter)
+}
+
+// correctFairness moves current weights of available hosts toward the configured ratio
+// so hosts which were skipped while their circuit breakers were open catch up with their shares.
+// The ratio is computed among available hosts only, so hosts which are open or excluded by the filter
+// don't build a deficit to be repaid in a burst when they become available again.
+// The correction of each host is capped at its weight per cycle, so a recovered host receives at most twice its share.
+// Served counts are halved after every correction, so old skips decay instead of being repaid indefinitely.
+func (wrr *WeightedRoundRobin) correctFairness(filter func(*loadbalancer.Host) bool) {
+	var totalServed, totalWeight int64
+
+	available := make([]*loadbalancer.Host, 0, len(wrr.hosts))
+
+	for _, h := range wrr.hosts {
+		if h.State() == circuitbreaker.OpenState || (filter != nil && !filter(h)) {
+			continue
+		}
+
+		available = append(available, h)
+		totalServed += wrr.served[h]
+		totalWeight += int64(h.Weight())
+	}
+
+	for _, h := range available {
+		weight := int64(h.Weight())
+		// Truncates toward zero, so rounding doesn't drift current weights of all hosts in one direction.
+		deficit := (totalServed*weight - wrr.served[h]*totalWeight) / totalWeight
+		deficit = min(max(deficit, -weight), weight)
+
+		h.AdjustCurrentWeight(int(deficit))
+	}
+
+	for h, served := range wrr.served {
+		wrr.served[h] = served / 2
+	}
+}
+
+// shuffleStart moves the starting position of the selection cycle to a random host.
+// Hosts are still selected in proportion to their weights over time.
+func (wrr *WeightedRoundRobin) shuffleStart() {
+	if len(wrr.hosts) <= 1 {
+		return
+	}
+
+	if wrr.isSameWeight {
+		wrr.totalWeight = rand.IntN(len(wrr.hosts)) //nolint:gosec
+
+		return
+	}
+
+	if wrr.totalWeight <= 0 {
+		return
+	}
+
+	// Advance the smooth weighted round-robin sequence by a random number of steps within a cycle.
+	for range rand.IntN(wrr.totalWeight) { //nolint:gosec
+		var best *loadbalancer.Host
+
+		total := 0
+
+		for _, h := range wrr.hosts {
+			h.AddCurrentWeight()
+
+			total += h.EffectiveWeight()
+
+			if best == nil || h.CurrentWeight() > best.CurrentWeight() {
+				best = h
+			}
+		}
+
+		best.ResetCurrentWeight(total)
+	}
+}
+
+// inheritHostStates carries over states of existing hosts which are equal to new hosts by the comparator.
+func (wrr *WeightedRoundRobin) inheritHostStates(servers []*loadbalancer.Host) {
+	comparator := wrr.hostComparator
+	if comparator == nil {
+		comparator = defaultHostComparator
+	}
+
+	for _, h := range servers {
+		for _, previous := range wrr.hosts {
+			if comparator(previous, h) {
+				h.InheritState(previous)
+
+				break
+			}
+		}
+	}
+}
+
+// defaultHostComparator checks if both hosts have the same name and URL.
+func defaultHostComparator(a, b *loadbalancer.Host) bool {
+	return a.Name() == b.Name() && a.URL() == b.URL()
+}
+
 type weightedRoundRobinOptions struct {
-	healthCheckInterval time.Duration
+	healthCheckInterval    time.Duration
+	hostComparator         func(a, b *loadbalancer.Host) bool
+	hostShuffle            bool
+	hostFilter             func(*loadbalancer.Host) bool
+	healthCheckJitter      float64
+	healthCheckConcurrency int
+	loadHeader             string
 }
 
 // WeightedRoundRobinOption represents a function to modify the Weighted Round-Robin options.
@@ -278,3 +510,57 @@ func WithHealthCheckInterval(duration time.Duration) WeightedRoundRobinOption {
 			duration, 0)
 	}
 }
+
+// WithHostComparator sets the function to check if an existing host equals a new host when refreshing hosts.
+// The circuit breaker state and the current weight of equal hosts are carried over.
+// By default, hosts are equal if they have the same name and URL.
+func WithHostComparator(comparator func(a, b *loadbalancer.Host) bool) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.hostComparator = comparator
+	}
+}
+
+// WithHostShuffle randomizes the starting host of the selection cycle when hosts are refreshed,
+// so a fleet of clients spreads initial requests across hosts instead of all starting at the first host.
+// The order is deterministic if disabled.
+func WithHostShuffle(enabled bool) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.hostShuffle = enabled
+	}
+}
+
+// WithHostFilter sets the function to exclude hosts at selection time by runtime criteria,
+// e.g. a maintenance flag which is set externally. Hosts are skipped if the filter returns false.
+// All hosts are considered if every host is excluded. The filter is called while the balancer is locked,
+// so it must be fast and must not call methods of the balancer.
+func WithHostFilter(filter func(*loadbalancer.Host) bool) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.hostFilter = filter
+	}
+}
+
+// WithHealthCheckJitter staggers the probe of each host by a random fraction of the health check interval,
+// up to the given fraction in range [0, 1], so probes don't hit backends simultaneously on every tick.
+// Probes start immediately on every tick if the fraction is 0.
+func WithHealthCheckJitter(fraction float64) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.healthCheckJitter = min(max(fraction, 0), 1)
+	}
+}
+
+// WithHealthCheckConcurrency sets the maximum number of hosts which are probed concurrently on every tick.
+// All hosts are probed concurrently if n is zero or negative. Each probe still respects its own timeout.
+func WithHealthCheckConcurrency(n int) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.healthCheckConcurrency = max(n, 0)
+	}
+}
+
+// WithLoadHeader sets the response header name which advertises the load of hosts, e.g. X-Load.
+// The load is a number in range [0, 1] which is parsed from responses of each host,
+// and reduces the effective weight of the host for subsequent selections.
+func WithLoadHeader(name string) WeightedRoundRobinOption {
+	return func(wrro *weightedRoundRobinOptions) {
+		wrro.loadHeader = name
+	}
+}
@@ -21,16 +21,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/hasura/goenvconf"
-	"github.com/relychan/gohttpc"
 	"github.com/relychan/gohttpc/authc/authscheme"
 	"github.com/relychan/gohttpc/authc/httpauth"
-	"github.com/relychan/gohttpc/httpconfig"
 	"github.com/relychan/gohttpc/loadbalancer"
 )
 
@@ -156,6 +155,511 @@ func TestWeightedRoundRobin(t *testing.T) {
 	})
 }
 
+func TestWeightedRoundRobin_HostShuffle(t *testing.T) {
+	newHosts := func(t *testing.T, weights []int) []*loadbalancer.Host {
+		t.Helper()
+
+		hosts := make([]*loadbalancer.Host, len(weights))
+
+		for i, weight := range weights {
+			host, err := loadbalancer.NewHost(
+				nil,
+				fmt.Sprintf("https://example%d.com", i+1),
+				loadbalancer.WithWeight(weight),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hosts[i] = host
+		}
+
+		return hosts
+	}
+
+	testCases := []struct {
+		Name    string
+		Weights []int
+	}{
+		{
+			Name:    "same_weight",
+			Weights: []int{1, 1, 1, 1},
+		},
+		{
+			Name:    "different_weights",
+			Weights: []int{5, 2, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			totalWeight := 0
+
+			for _, weight := range tc.Weights {
+				totalWeight += weight
+			}
+
+			firstHosts := map[string]bool{}
+
+			for range 50 {
+				wrr, err := NewWeightedRoundRobin(newHosts(t, tc.Weights), WithHostShuffle(true))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				counts := map[string]int{}
+
+				for i := range 10 * totalWeight {
+					host, err := wrr.Next()
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if i == 0 {
+						firstHosts[host.URL()] = true
+					}
+
+					counts[host.URL()]++
+				}
+
+				for i, weight := range tc.Weights {
+					hostURL := fmt.Sprintf("https://example%d.com", i+1)
+
+					if counts[hostURL] != 10*weight {
+						t.Errorf("expected %d requests to %s, got: %d", 10*weight, hostURL, counts[hostURL])
+					}
+				}
+
+				_ = wrr.Close()
+			}
+
+			if len(firstHosts) < 2 {
+				t.Errorf("expected shuffled load balancers to start at different hosts, got: %v", firstHosts)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		for range 10 {
+			wrr, err := NewWeightedRoundRobin(newHosts(t, []int{1, 1, 1}), WithHostShuffle(false))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := wrr.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if host.URL() != "https://example1.com" {
+				t.Errorf("expected the first host, got: %s", host.URL())
+			}
+
+			_ = wrr.Close()
+		}
+	})
+}
+
+func TestWeightedRoundRobin_HostFilter(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Weights []int
+	}{
+		{
+			Name:    "same_weight",
+			Weights: []int{1, 1, 1},
+		},
+		{
+			Name:    "different_weights",
+			Weights: []int{5, 2, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hosts := make([]*loadbalancer.Host, len(tc.Weights))
+
+			for i, weight := range tc.Weights {
+				host, err := loadbalancer.NewHost(
+					nil,
+					fmt.Sprintf("https://example%d.com", i+1),
+					loadbalancer.WithWeight(weight),
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				hosts[i] = host
+			}
+
+			var maintenance sync.Map
+
+			maintenance.Store(hosts[0].URL(), true)
+
+			wrr, err := NewWeightedRoundRobin(hosts, WithHostFilter(func(h *loadbalancer.Host) bool {
+				_, ok := maintenance.Load(h.URL())
+
+				return !ok
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer wrr.Close()
+
+			countSelections := func() map[string]int {
+				counts := map[string]int{}
+
+				for range 40 {
+					host, err := wrr.Next()
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					counts[host.URL()]++
+				}
+
+				return counts
+			}
+
+			counts := countSelections()
+			if counts[hosts[0].URL()] != 0 {
+				t.Errorf("expected the excluded host not to be selected, got: %d", counts[hosts[0].URL()])
+			}
+
+			if counts[hosts[1].URL()] == 0 || counts[hosts[2].URL()] == 0 {
+				t.Errorf("expected other hosts to be selected, got: %v", counts)
+			}
+
+			// All hosts are considered if every host is excluded.
+			maintenance.Store(hosts[1].URL(), true)
+			maintenance.Store(hosts[2].URL(), true)
+
+			counts = countSelections()
+			if counts[hosts[0].URL()] == 0 {
+				t.Errorf("expected to fall back to all hosts, got: %v", counts)
+			}
+
+			maintenance.Clear()
+
+			counts = countSelections()
+			for _, host := range hosts {
+				if counts[host.URL()] == 0 {
+					t.Errorf("expected %s to be selected after the filter changes, got: %v", host.URL(), counts)
+				}
+			}
+		})
+	}
+}
+
+func TestWeightedRoundRobin_HostFilterBoundedBurst(t *testing.T) {
+	weights := []int{3, 2, 1}
+	hosts := make([]*loadbalancer.Host, len(weights))
+
+	for i, weight := range weights {
+		host, err := loadbalancer.NewHost(nil, fmt.Sprintf("https://example%d.com", i), loadbalancer.WithWeight(weight))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
+	}
+
+	var excluded atomic.Bool
+
+	excluded.Store(true)
+
+	wrr, err := NewWeightedRoundRobin(hosts, WithHostFilter(func(h *loadbalancer.Host) bool {
+		return h != hosts[0] || !excluded.Load()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wrr.Close()
+
+	for range 6000 {
+		if _, err := wrr.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	excluded.Store(false)
+
+	var count, burst, maxBurst int
+
+	for range 600 {
+		host, err := wrr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if host != hosts[0] {
+			burst = 0
+
+			continue
+		}
+
+		count++
+		burst++
+		maxBurst = max(maxBurst, burst)
+	}
+
+	// The host excluded by the filter doesn't build a deficit to be repaid after the filter changes.
+	if expected := 600 * weights[0] / 6; count > expected+2*weights[0] {
+		t.Errorf("expected the host to serve about %d requests, got: %d", expected, count)
+	}
+
+	if maxBurst > 2*weights[0] {
+		t.Errorf("expected bounded consecutive selections, got: %d", maxBurst)
+	}
+}
+
+func TestWeightedRoundRobin_RefreshWithHostComparator(t *testing.T) {
+	newHosts := func(t *testing.T) (*loadbalancer.Host, *loadbalancer.Host) {
+		t.Helper()
+
+		oldHost, err := loadbalancer.NewHost(nil, "https://example1.com", loadbalancer.WithWeight(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		oldHost.SetName("primary")
+		oldHost.HealthCheckPolicy().Open()
+		oldHost.AddCurrentWeight()
+
+		newHost, err := loadbalancer.NewHost(nil, "https://example2.com", loadbalancer.WithWeight(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		newHost.SetName("primary")
+
+		return oldHost, newHost
+	}
+
+	t.Run("default comparator requires the same URL", func(t *testing.T) {
+		oldHost, newHost := newHosts(t)
+
+		wrr, err := NewWeightedRoundRobin([]*loadbalancer.Host{oldHost})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = wrr.Refresh([]*loadbalancer.Host{newHost})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if newHost.State() != circuitbreaker.ClosedState {
+			t.Errorf("expected the closed state, got: %v", newHost.State())
+		}
+
+		if newHost.CurrentWeight() != 0 {
+			t.Errorf("expected the current weight 0, got: %d", newHost.CurrentWeight())
+		}
+	})
+
+	t.Run("custom comparator by name", func(t *testing.T) {
+		oldHost, newHost := newHosts(t)
+
+		wrr, err := NewWeightedRoundRobin(
+			[]*loadbalancer.Host{oldHost},
+			WithHostComparator(func(a, b *loadbalancer.Host) bool {
+				return a.Name() == b.Name()
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = wrr.Refresh([]*loadbalancer.Host{newHost})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if newHost.State() != circuitbreaker.OpenState {
+			t.Errorf("expected the open state to be preserved, got: %v", newHost.State())
+		}
+
+		if newHost.CurrentWeight() != 2 {
+			t.Errorf("expected the current weight 2, got: %d", newHost.CurrentWeight())
+		}
+
+		if newHost.URL() != "https://example2.com" {
+			t.Errorf("expected the new URL, got: %s", newHost.URL())
+		}
+	})
+}
+
+func TestWeightedRoundRobin_FairnessCorrection(t *testing.T) {
+	weights := []int{3, 2, 1}
+	hosts := make([]*loadbalancer.Host, len(weights))
+
+	for i, weight := range weights {
+		host, err := loadbalancer.NewHost(nil, fmt.Sprintf("https://example%d.com", i), loadbalancer.WithWeight(weight))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
+	}
+
+	wrr, err := NewWeightedRoundRobin(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wrr.Close()
+
+	counts := map[*loadbalancer.Host]int{}
+
+	next := func(n int) {
+		for range n {
+			host, err := wrr.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			counts[host]++
+		}
+	}
+
+	// The first host is briefly open and skipped.
+	hosts[0].HealthCheckPolicy().Open()
+	next(60)
+
+	if counts[hosts[0]] != 0 {
+		t.Fatalf("expected the open host to be skipped, got %d requests", counts[hosts[0]])
+	}
+
+	hosts[0].HealthCheckPolicy().Close()
+	clear(counts)
+	next(1140)
+
+	total := 1140
+
+	for i, host := range hosts {
+		expected := total * weights[i] / 6
+		if diff := counts[host] - expected; diff < -weights[i] || diff > weights[i] {
+			t.Errorf("expected host %d to serve about %d requests, got: %d", i, expected, counts[host])
+		}
+	}
+
+	t.Run("bounded_catch_up_after_long_outage", func(t *testing.T) {
+		hosts[0].HealthCheckPolicy().Open()
+		next(6000)
+
+		hosts[0].HealthCheckPolicy().Close()
+		clear(counts)
+		next(600)
+
+		// The skips of the long outage decay instead of being repaid for thousands of requests.
+		if expected := 600 * weights[0] / 6; counts[hosts[0]] > expected+2*weights[0] {
+			t.Errorf("expected the recovered host to serve about %d requests, got: %d", expected, counts[hosts[0]])
+		}
+	})
+}
+
+func TestWeightedRoundRobin_Close(t *testing.T) {
+	var healthChecks atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthChecks.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newBalancer := func(t *testing.T) *WeightedRoundRobin {
+		t.Helper()
+
+		hosts := make([]*loadbalancer.Host, 2)
+
+		for i := range hosts {
+			host, err := loadbalancer.NewHost(server.Client(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hosts[i] = host
+		}
+
+		wrr, err := NewWeightedRoundRobin(hosts, WithHealthCheckInterval(5*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return wrr
+	}
+
+	t.Run("close twice", func(t *testing.T) {
+		wrr := newBalancer(t)
+
+		for range 2 {
+			if err := wrr.Close(); err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+		}
+	})
+
+	t.Run("close and cancel the health check concurrently", func(t *testing.T) {
+		for range 20 {
+			wrr := newBalancer(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+
+			go func() {
+				wrr.StartHealthCheck(ctx)
+				close(done)
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+
+			var wg sync.WaitGroup
+
+			wg.Go(cancel)
+
+			for range 2 {
+				wg.Go(func() {
+					if err := wrr.Close(); err != nil {
+						t.Errorf("expected no error, got: %s", err)
+					}
+				})
+			}
+
+			wg.Wait()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("expected the health check to stop after the context is canceled")
+			}
+		}
+	})
+
+	t.Run("stops health checks after close", func(t *testing.T) {
+		wrr := newBalancer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go wrr.StartHealthCheck(ctx)
+
+		time.Sleep(20 * time.Millisecond)
+
+		if err := wrr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Wait for the in-progress health check to complete.
+		time.Sleep(10 * time.Millisecond)
+
+		count := healthChecks.Load()
+
+		time.Sleep(30 * time.Millisecond)
+
+		if healthChecks.Load() != count {
+			t.Errorf("expected no health check after close, got: %d", healthChecks.Load()-count)
+		}
+	})
+}
+
 func TestWeightedRoundRobinIntegration(t *testing.T) {
 	counter1 := &atomic.Int32{}
 	counter2 := &atomic.Int32{}
@@ -243,7 +747,7 @@ func TestWeightedRoundRobinIntegration(t *testing.T) {
 	cancel()
 
 	for range 4 {
-		wrr.nextRoundRobin()
+		wrr.nextRoundRobin(nil)
 	}
 
 	if hosts[2].State() != circuitbreaker.HalfOpenState {
@@ -315,155 +819,274 @@ func TestRoundRobinIntegration(t *testing.T) {
 	}
 }
 
-func TestRoundRobinIntegrationWithRetry(t *testing.T) {
-	counter1 := atomic.Int32{}
-	counter2 := atomic.Int32{}
-	counter3 := atomic.Int32{}
+func newTestHost(t *testing.T, uri string, token string) *loadbalancer.Host {
+	t.Helper()
 
-	handler1 := http.NewServeMux()
-	handler1.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		counter1.Add(1)
+	parsedURI, err := url.Parse(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host, err := loadbalancer.NewHost(http.DefaultClient, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := httpauth.NewHTTPCredential(&httpauth.HTTPAuthConfig{
+		TokenLocation: authscheme.TokenLocation{
+			In:     authscheme.InHeader,
+			Name:   "Authorization",
+			Scheme: "bearer",
+		},
+		Value: goenvconf.NewEnvStringValue(token),
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %s", err.Error())
+	}
+
+	host.SetAuthenticator(authenticator)
+
+	healthCheckConfig, err := (loadbalancer.HTTPHealthCheckConfig{
+		Path: "/healthz",
+	}).ToPolicy(parsedURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host.SetHealthCheckPolicy(healthCheckConfig)
+
+	return host
+}
+
+func newMockServer(token string, counter *atomic.Int32) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		counter.Add(1)
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler2 := http.NewServeMux()
-	handler2.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		counter2.Add(1)
-		w.WriteHeader(http.StatusServiceUnavailable)
+	handler.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
 	})
 
-	handler3 := http.NewServeMux()
-	handler3.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		counter3.Add(1)
-		w.WriteHeader(http.StatusBadGateway)
-	})
+	return httptest.NewServer(handler)
+}
 
-	testServer1 := httptest.NewServer(handler1)
-	defer testServer1.Close()
+func TestWeightedRoundRobin_HealthCheckJitter(t *testing.T) {
+	const interval = 200 * time.Millisecond
 
-	testServer2 := httptest.NewServer(handler2)
-	defer testServer2.Close()
+	var (
+		lock       sync.Mutex
+		probeTimes []time.Time
+	)
 
-	testServer3 := httptest.NewServer(handler3)
-	defer testServer3.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		probeTimes = append(probeTimes, time.Now())
+		lock.Unlock()
 
-	host1, err := loadbalancer.NewHost(http.DefaultClient, testServer1.URL)
-	if err != nil {
-		t.Fatal(err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hosts := make([]*loadbalancer.Host, 10)
+
+	for i := range hosts {
+		host, err := loadbalancer.NewHost(server.Client(), server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
 	}
 
-	host2, err := loadbalancer.NewHost(http.DefaultClient, testServer2.URL)
+	wrr, err := NewWeightedRoundRobin(
+		hosts,
+		WithHealthCheckInterval(interval),
+		WithHealthCheckJitter(1),
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	host3, err := loadbalancer.NewHost(http.DefaultClient, testServer3.URL)
-	if err != nil {
-		t.Fatal(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		wrr.StartHealthCheck(ctx)
+		close(done)
+	}()
+
+	// Wait for the first round of probes to complete.
+	time.Sleep(2*interval + 50*time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the health check to stop after the context is canceled")
 	}
 
-	hosts := []*loadbalancer.Host{host1, host2, host3}
+	lock.Lock()
+	defer lock.Unlock()
 
-	wrr, err := NewWeightedRoundRobin(hosts)
-	if err != nil {
-		t.Fatal(err)
+	if len(probeTimes) < len(hosts) {
+		t.Fatalf("expected at least %d probes, got: %d", len(hosts), len(probeTimes))
 	}
 
-	retryConfig, err := (httpconfig.HTTPRetryConfig{
-		MaxAttempts: 3,
-		Delay:       new(int64(100)),
-	}).ToRetryPolicy()
-	if err != nil {
-		t.Fatalf("failed to create retry config: %s", err)
+	firstRound := probeTimes[:len(hosts)]
+	spread := firstRound[len(firstRound)-1].Sub(firstRound[0])
+
+	if spread < interval/5 {
+		t.Errorf("expected probes to be spread across the interval, got a spread of %s", spread)
 	}
+}
 
-	lb := loadbalancer.NewLoadBalancerClientWithOptions(
-		wrr,
-		gohttpc.NewClientOptions(gohttpc.WithRetry(retryConfig)),
-	)
+func TestWeightedRoundRobin_HealthCheckConcurrency(t *testing.T) {
+	const interval = 100 * time.Millisecond
 
-	for range 10 {
-		resp, err := lb.R(http.MethodGet, "/").Execute(context.TODO())
-		if err != nil {
-			t.Errorf("expected no err, got: %s", err)
+	var fastProbes atomic.Int32
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
 		}
 
-		if resp.StatusCode != http.StatusOK {
-			t.Errorf("expected HTTP 200, got: %d", resp.StatusCode)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slowServer.Close()
+
+	fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fastProbes.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fastServer.Close()
+
+	// The slow host is probed first.
+	hosts := make([]*loadbalancer.Host, 4)
+
+	for i := range hosts {
+		serverURL := fastServer.URL
+		if i == 0 {
+			serverURL = slowServer.URL
 		}
-	}
 
-	if counter1.Load() != 10 {
-		t.Errorf("expected 10 requests to host 1; got: %d", counter1.Load())
+		host, err := loadbalancer.NewHost(http.DefaultClient, serverURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hosts[i] = host
 	}
 
-	if counter2.Load() != 3 {
-		t.Errorf("expected 3 requests to host 2; got: %d", counter2.Load())
+	wrr, err := NewWeightedRoundRobin(
+		hosts,
+		WithHealthCheckInterval(interval),
+		WithHealthCheckConcurrency(2),
+	)
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	if counter3.Load() != 3 {
-		t.Errorf("expected 3 requests to host 3; got: %d", counter3.Load())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		wrr.StartHealthCheck(ctx)
+		close(done)
+	}()
+
+	time.Sleep(interval + 100*time.Millisecond)
+
+	if fastProbes.Load() != 3 {
+		t.Errorf("expected fast hosts to be probed without waiting for the slow host, got %d probes", fastProbes.Load())
 	}
 
-	if hosts[2].State() != circuitbreaker.OpenState {
-		t.Errorf("expected open state on host 3; got: %s", hosts[2].State().String())
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the in-flight probe to stop after the context is canceled")
 	}
 }
 
-func newTestHost(t *testing.T, uri string, token string) *loadbalancer.Host {
-	t.Helper()
+func TestWeightedRoundRobin_LoadHeader(t *testing.T) {
+	var busyCount, idleCount atomic.Int32
 
-	parsedURI, err := url.Parse(uri)
+	busyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		busyCount.Add(1)
+		w.Header().Set("X-Load", "0.8")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer busyServer.Close()
+
+	idleServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idleCount.Add(1)
+		w.Header().Set("X-Load", "0")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer idleServer.Close()
+
+	busyHost, err := loadbalancer.NewHost(busyServer.Client(), busyServer.URL)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	host, err := loadbalancer.NewHost(http.DefaultClient, uri)
+	idleHost, err := loadbalancer.NewHost(idleServer.Client(), idleServer.URL)
 	if err != nil {
 		t.Fatal(err)
 	}
-	authenticator, err := httpauth.NewHTTPCredential(&httpauth.HTTPAuthConfig{
-		TokenLocation: authscheme.TokenLocation{
-			In:     authscheme.InHeader,
-			Name:   "Authorization",
-			Scheme: "bearer",
-		},
-		Value: goenvconf.NewEnvStringValue(token),
-	}, nil)
-	if err != nil {
-		t.Fatalf("failed to create authenticator: %s", err.Error())
-	}
-
-	host.SetAuthenticator(authenticator)
 
-	healthCheckConfig, err := (loadbalancer.HTTPHealthCheckConfig{
-		Path: "/healthz",
-	}).ToPolicy(parsedURI)
+	wrr, err := NewWeightedRoundRobin(
+		[]*loadbalancer.Host{busyHost, idleHost},
+		WithLoadHeader("X-Load"),
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	host.SetHealthCheckPolicy(healthCheckConfig)
+	defer wrr.Close()
 
-	return host
-}
+	for range 120 {
+		host, err := wrr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
 
-func newMockServer(token string, counter *atomic.Int32) *httptest.Server {
-	handler := http.NewServeMux()
-	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("Authorization") != "Bearer "+token {
-			w.WriteHeader(http.StatusUnauthorized)
+		req, err := host.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-			return
+		resp, err := host.Do(req)
+		if err != nil {
+			t.Fatal(err)
 		}
 
-		counter.Add(1)
-		w.WriteHeader(http.StatusOK)
-	})
+		resp.Body.Close()
+	}
 
-	handler.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	if busyHost.Load() != 0.8 {
+		t.Errorf("expected the load of the busy host to be 0.8, got: %f", busyHost.Load())
+	}
 
-	return httptest.NewServer(handler)
+	if busyHost.EffectiveWeight() != 20 || idleHost.EffectiveWeight() != 100 {
+		t.Errorf(
+			"expected effective weights 20 and 100, got: %d and %d",
+			busyHost.EffectiveWeight(),
+			idleHost.EffectiveWeight(),
+		)
+	}
+
+	// The busy host receives about 1/6 of the traffic after its load is reported.
+	if busy, idle := busyCount.Load(), idleCount.Load(); busy < 15 || busy > 25 || busy+idle != 120 {
+		t.Errorf("expected the busy host to receive proportionally less traffic, got %d busy and %d idle", busy, idle)
+	}
 }
@@ -0,0 +1,216 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestMaxLogBodySize(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	responseBody := `{"items":["` + strings.Repeat("é", 1000) + `"]}`
+	requestBody := `{"name":"` + strings.Repeat("a", 1000) + `"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != requestBody {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(responseBody))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.WithValue(context.Background(), otelutils.LoggerContextKey, logger)
+
+	client := gohttpc.NewClient(gohttpc.WithMaxLogBodySize(64))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	req := client.R(http.MethodPost, server.URL)
+	req.Header().Set("Content-Type", "application/json")
+	req.SetBody(strings.NewReader(requestBody))
+
+	resp, err := req.Execute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer goutils.CloseResponse(resp)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != responseBody {
+		t.Errorf("expected the full response body, got %d bytes", len(body))
+	}
+
+	var loggedRequestBody, loggedResponseBody string
+
+	for line := range strings.SplitSeq(strings.TrimSpace(logs.String()), "\n") {
+		var entry struct {
+			Request  struct{ Body string } `json:"request"`
+			Response struct{ Body string } `json:"response"`
+		}
+
+		err := json.Unmarshal([]byte(line), &entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if entry.Request.Body != "" {
+			loggedRequestBody = entry.Request.Body
+		}
+
+		if entry.Response.Body != "" {
+			loggedResponseBody = entry.Response.Body
+		}
+	}
+
+	expectedRequestBody := requestBody[:64] + "…(truncated)"
+	if loggedRequestBody != expectedRequestBody {
+		t.Errorf("expected the logged request body %q, got: %q", expectedRequestBody, loggedRequestBody)
+	}
+
+	// The truncation doesn't split the two-byte characters.
+	expectedResponseBody := responseBody[:63] + "…(truncated)"
+	if loggedResponseBody != expectedResponseBody {
+		t.Errorf("expected the logged response body %q, got: %q", expectedResponseBody, loggedResponseBody)
+	}
+
+	spanRequestBody, _ := findSpanAttribute(recorder, "Request", "http.request.body")
+	if spanRequestBody != expectedRequestBody {
+		t.Errorf("expected the request body span attribute %q, got: %q", expectedRequestBody, spanRequestBody)
+	}
+
+	spanResponseBody, _ := findSpanAttribute(recorder, "Request", "http.response.body")
+	if spanResponseBody != expectedResponseBody {
+		t.Errorf("expected the response body span attribute %q, got: %q", expectedResponseBody, spanResponseBody)
+	}
+}
+
+func TestRequestLogFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.WithValue(context.Background(), otelutils.LoggerContextKey, logger)
+
+	client := gohttpc.NewClient(gohttpc.WithLogLevel(slog.LevelInfo))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	for _, path := range []string{"/ok", "/fail"} {
+		resp, _ := client.R(http.MethodGet, server.URL+path).
+			WithLogFields("tenant", "acme", slog.String("user", "alice")).
+			Execute(ctx)
+		if resp != nil {
+			goutils.CloseResponse(resp)
+		}
+	}
+
+	levels := map[string]bool{}
+
+	for line := range strings.Lines(logs.String()) {
+		var record map[string]any
+
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+
+		if record["tenant"] != "acme" || record["user"] != "alice" {
+			t.Errorf("expected the custom fields in the log record, got %v", record)
+		}
+
+		levels[record["level"].(string)] = true
+	}
+
+	for _, level := range []string{"DEBUG", "INFO", "ERROR"} {
+		if !levels[level] {
+			t.Errorf("expected a %s log record, got %v", level, levels)
+		}
+	}
+}
+
+func TestLogLevel_SuccessfulRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	ctx := context.WithValue(context.Background(), otelutils.LoggerContextKey, logger)
+
+	client := gohttpc.NewClient(gohttpc.WithSuccessLogLevel(slog.LevelDebug))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	if logs.Len() > 0 {
+		t.Errorf("expected no Info-level record for the successful request, got %s", logs.String())
+	}
+
+	resp, err = client.R(http.MethodGet, server.URL+"/fail").Execute(ctx)
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+
+	goutils.CloseResponse(resp)
+
+	if !strings.Contains(logs.String(), `"level":"ERROR"`) {
+		t.Errorf("expected an Error-level record for the failed request, got %s", logs.String())
+	}
+}
@@ -15,6 +15,8 @@
 package gohttpc
 
 import (
+	"context"
+	"io"
 	"sync/atomic"
 
 	"go.opentelemetry.io/otel/metric"
@@ -38,14 +40,31 @@ type HTTPClientMetrics struct {
 	ServerDuration metric.Float64Histogram
 	// Number of active HTTP requests.
 	ActiveRequests metric.Int64UpDownCounter
+	// Number of active HTTP/2 streams which are multiplexed over connections.
+	// A stream is active from the time the response is received until its body is closed.
+	ActiveStreams metric.Int64UpDownCounter
 	// Histogram metrics of the request body size.
 	RequestBodySize metric.Int64Histogram
 	// Histogram metrics of the response body size.
 	ResponseBodySize metric.Int64Histogram
 	// Duration of HTTP client requests.
 	RequestDuration metric.Float64Histogram
+	// Number of HTTP responses by status class.
+	Responses metric.Int64Counter
 	// The duration of DNS lookup operations performed by the HTTP client.
 	DNSLookupDuration metric.Float64Histogram
+	// Number of HTTP requests which are delayed by the client-side rate limiter.
+	RateLimitedRequests metric.Int64Counter
+	// Duration of HTTP requests waiting in the client-side queue before being sent.
+	QueueDuration metric.Float64Histogram
+	// Number of retries which are suppressed by the retry budget.
+	SuppressedRetries metric.Int64Counter
+	// Number of dials which fail fast because the host is in the DNS negative cache.
+	DNSNegativeCacheHits metric.Int64Counter
+	// Number of request body bytes which are sent by the client.
+	BytesSent metric.Int64Counter
+	// Number of response body bytes which are received by the client, before decompression.
+	BytesReceived metric.Int64Counter
 }
 
 // NewHTTPClientMetrics creates an HTTPClientMetrics instance from the OpenTelemetry meter.
@@ -117,6 +136,15 @@ func NewHTTPClientMetrics( //nolint:funlen
 		return nil, err
 	}
 
+	metrics.ActiveStreams, err = meter.Int64UpDownCounter(
+		"http.client.active_streams",
+		metric.WithDescription("Number of active HTTP/2 streams which are multiplexed over connections."),
+		metric.WithUnit("{stream}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	metrics.RequestBodySize, err = meter.Int64Histogram(
 		"http.client.request.body.size",
 		metric.WithDescription("Size of HTTP client request bodies."),
@@ -153,6 +181,72 @@ func NewHTTPClientMetrics( //nolint:funlen
 		return nil, err
 	}
 
+	metrics.Responses, err = meter.Int64Counter(
+		"http.client.responses",
+		metric.WithDescription("Number of HTTP client responses by status class."),
+		metric.WithUnit("{response}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.RateLimitedRequests, err = meter.Int64Counter(
+		"http.client.rate_limited_requests",
+		metric.WithDescription("Number of HTTP requests which are delayed by the client-side rate limiter."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.QueueDuration, err = meter.Float64Histogram(
+		"http.client.queue.duration",
+		metric.WithDescription("Duration of HTTP requests waiting in the client-side queue before being sent."),
+		metric.WithUnit("s"),
+		requestDurationBucketBoundaries,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.SuppressedRetries, err = meter.Int64Counter(
+		"http.client.suppressed_retries",
+		metric.WithDescription("Number of retries which are suppressed by the retry budget."),
+		metric.WithUnit("{retry}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.DNSNegativeCacheHits, err = meter.Int64Counter(
+		"http.client.dns.negative_cache_hits",
+		metric.WithDescription(
+			"Number of dials which fail fast because the host is in the DNS negative cache.",
+		),
+		metric.WithUnit("{hit}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.BytesSent, err = meter.Int64Counter(
+		"http.client.bytes_sent",
+		metric.WithDescription("Number of request body bytes which are sent by the client."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.BytesReceived, err = meter.Int64Counter(
+		"http.client.bytes_received",
+		metric.WithDescription("Number of response body bytes which are received by the client."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	metrics.ServerDuration, err = meter.Float64Histogram(
 		"http.client.server.duration",
 		metric.WithDescription("The duration of the server for responding to the first byte."),
@@ -219,11 +313,14 @@ func NewHTTPClientMetrics( //nolint:funlen
 var globalClientMetrics = defaultClientMetrics()
 
 // GetHTTPClientMetrics gets the global [HTTPClientMetrics] instance.
+// The global metrics are the fallback of clients without [WithMetrics].
 func GetHTTPClientMetrics() *HTTPClientMetrics {
 	return globalClientMetrics.Load()
 }
 
 // SetHTTPClientMetrics sets the global [HTTPClientMetrics] instance.
+// The global metrics are shared by all clients in the process which don't set [WithMetrics].
+// Prefer [WithMetrics] to keep metrics of independent clients separate.
 func SetHTTPClientMetrics(metrics *HTTPClientMetrics) {
 	if metrics == nil {
 		metrics = &noopHTTPClientMetrics
@@ -232,6 +329,28 @@ func SetHTTPClientMetrics(metrics *HTTPClientMetrics) {
 	globalClientMetrics.Store(metrics)
 }
 
+type httpClientMetricsContextKey struct{}
+
+// contextWithHTTPClientMetrics returns the context which carries the metrics of the client,
+// so the transport and the client trace record to the same instruments as the request.
+func contextWithHTTPClientMetrics(ctx context.Context, metrics *HTTPClientMetrics) context.Context {
+	if metrics == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, httpClientMetricsContextKey{}, metrics)
+}
+
+// metricsFromContext gets the metrics of the client from the context, or the global metrics if not set.
+func metricsFromContext(ctx context.Context) *HTTPClientMetrics {
+	metrics, ok := ctx.Value(httpClientMetricsContextKey{}).(*HTTPClientMetrics)
+	if ok {
+		return metrics
+	}
+
+	return GetHTTPClientMetrics()
+}
+
 var noopHTTPClientMetrics = HTTPClientMetrics{
 	ConnectionDuration:     noop.Float64Histogram{},
 	OpenConnections:        noop.Int64UpDownCounter{},
@@ -239,10 +358,119 @@ var noopHTTPClientMetrics = HTTPClientMetrics{
 	IdleConnectionDuration: noop.Float64Histogram{},
 	ServerDuration:         noop.Float64Histogram{},
 	ActiveRequests:         noop.Int64UpDownCounter{},
+	ActiveStreams:          noop.Int64UpDownCounter{},
 	RequestBodySize:        noop.Int64Histogram{},
 	ResponseBodySize:       noop.Int64Histogram{},
 	RequestDuration:        noop.Float64Histogram{},
+	Responses:              noop.Int64Counter{},
 	DNSLookupDuration:      noop.Float64Histogram{},
+	RateLimitedRequests:    noop.Int64Counter{},
+	QueueDuration:          noop.Float64Histogram{},
+	SuppressedRetries:      noop.Int64Counter{},
+	DNSNegativeCacheHits:   noop.Int64Counter{},
+	BytesSent:              noop.Int64Counter{},
+	BytesReceived:          noop.Int64Counter{},
+}
+
+// activeStreamBody wraps the response body of an HTTP/2 stream to decrement the active streams counter on close.
+type activeStreamBody struct {
+	io.ReadCloser
+
+	end    func()
+	closed atomic.Bool
+}
+
+func newActiveStreamBody(
+	ctx context.Context,
+	body io.ReadCloser,
+	metrics *HTTPClientMetrics,
+	attrs metric.MeasurementOption,
+) *activeStreamBody {
+	metrics.ActiveStreams.Add(ctx, 1, attrs)
+
+	return &activeStreamBody{
+		ReadCloser: body,
+		end: func() {
+			metrics.ActiveStreams.Add(ctx, -1, attrs)
+		},
+	}
+}
+
+func (asb *activeStreamBody) Close() error {
+	if asb.closed.CompareAndSwap(false, true) {
+		asb.end()
+	}
+
+	return asb.ReadCloser.Close()
+}
+
+// responseSizeBody wraps the response body of unknown length to count the bytes which are read,
+// and records the response body size metric on close.
+type responseSizeBody struct {
+	io.ReadCloser
+
+	size   atomic.Int64
+	record func(size int64)
+	closed atomic.Bool
+}
+
+func newResponseSizeBody(
+	ctx context.Context,
+	body io.ReadCloser,
+	metrics *HTTPClientMetrics,
+	attrs metric.RecordOption,
+) *responseSizeBody {
+	return &responseSizeBody{
+		ReadCloser: body,
+		record: func(size int64) {
+			metrics.ResponseBodySize.Record(ctx, size, attrs)
+		},
+	}
+}
+
+func (rsb *responseSizeBody) Read(p []byte) (int, error) {
+	n, err := rsb.ReadCloser.Read(p)
+	rsb.size.Add(int64(n))
+
+	return n, err
+}
+
+func (rsb *responseSizeBody) Close() error {
+	if rsb.closed.CompareAndSwap(false, true) {
+		rsb.record(rsb.size.Load())
+	}
+
+	return rsb.ReadCloser.Close()
+}
+
+// byteCounterBody wraps the body to add the number of bytes which are read to a counter.
+type byteCounterBody struct {
+	io.ReadCloser
+
+	add func(n int64)
+}
+
+func newByteCounterBody(
+	ctx context.Context,
+	body io.ReadCloser,
+	counter metric.Int64Counter,
+	attrs metric.AddOption,
+) *byteCounterBody {
+	return &byteCounterBody{
+		ReadCloser: body,
+		add: func(n int64) {
+			counter.Add(ctx, n, attrs)
+		},
+	}
+}
+
+func (bcb *byteCounterBody) Read(p []byte) (int, error) {
+	n, err := bcb.ReadCloser.Read(p)
+	if n > 0 {
+		bcb.add(int64(n))
+	}
+
+	return n, err
 }
 
 func defaultClientMetrics() *atomic.Pointer[HTTPClientMetrics] {
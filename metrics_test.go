@@ -0,0 +1,762 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestResponsesMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	for _, status := range []int{200, 201, 204, 304, 404, 400, 401, 500, 503} {
+		resp, _ := client.R(http.MethodGet, server.URL+"?status="+strconv.Itoa(status)).Execute(t.Context())
+		goutils.CloseResponse(resp)
+	}
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := map[string]int64{}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "http.client.responses" {
+				continue
+			}
+
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("expected int64 sum, got: %T", m.Data)
+			}
+
+			for _, dp := range sum.DataPoints {
+				class, _ := dp.Attributes.Value("http.response.status_class")
+				results[class.AsString()] += dp.Value
+			}
+		}
+	}
+
+	expected := map[string]int64{
+		"2xx": 3,
+		"3xx": 1,
+		"4xx": 3,
+		"5xx": 2,
+	}
+
+	for class, count := range expected {
+		if results[class] != count {
+			t.Errorf("expected %d responses of class %s, got: %d", count, class, results[class])
+		}
+	}
+
+	if len(results) != len(expected) {
+		t.Errorf("expected %d status classes, got: %v", len(expected), results)
+	}
+}
+
+func TestActiveStreamsMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	release := make(chan struct{})
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			w.WriteHeader(http.StatusHTTPVersionNotSupported)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		if r.URL.Query().Get("wait") != "" {
+			<-release
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+
+	defer server.Close()
+
+	options := gohttpc.NewClientOptions()
+	transport := gohttpc.TransportFromConfig(nil, options)
+	// Trust the test server certificate.
+	transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+	client := gohttpc.NewClient(gohttpc.WithHTTPClient(&http.Client{Transport: transport}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	// Establish the HTTP/2 connection before sending concurrent requests.
+	resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("expected HTTP/2 response, got: %s", resp.Proto)
+	}
+
+	const concurrency = 5
+
+	responses := make([]*http.Response, concurrency)
+	errs := make([]error, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := range concurrency {
+		wg.Go(func() {
+			responses[i], errs[i] = client.R(http.MethodGet, server.URL+"?wait=true").
+				Execute(t.Context())
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results := collectInt64Sums(t, reader)
+
+	if results["http.client.active_streams"] != concurrency {
+		t.Errorf(
+			"expected %d active streams, got: %d",
+			concurrency,
+			results["http.client.active_streams"],
+		)
+	}
+
+	if results["http.client.open_connections"] != 1 {
+		t.Errorf("expected 1 open connection, got: %d", results["http.client.open_connections"])
+	}
+
+	close(release)
+
+	for _, resp := range responses {
+		goutils.CloseResponse(resp)
+	}
+
+	results = collectInt64Sums(t, reader)
+
+	if results["http.client.active_streams"] != 0 {
+		t.Errorf("expected no active streams, got: %d", results["http.client.active_streams"])
+	}
+}
+
+func TestOpenConnectionsMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+
+	for range 3 {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	// The idle connection is reused by subsequent requests.
+	results := collectInt64Sums(t, reader)
+	if results["http.client.open_connections"] != 1 {
+		t.Errorf("expected 1 open connection, got: %d", results["http.client.open_connections"])
+	}
+
+	goutils.CatchWarnErrorFunc(client.Close)
+
+	results = collectInt64Sums(t, reader)
+	if results["http.client.open_connections"] != 0 {
+		t.Errorf("expected no open connections, got: %d", results["http.client.open_connections"])
+	}
+}
+
+func TestConnectionDurationMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	collect := func() metricdata.HistogramDataPoint[float64] {
+		var rm metricdata.ResourceMetrics
+
+		err := reader.Collect(t.Context(), &rm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result metricdata.HistogramDataPoint[float64]
+
+		for _, scope := range rm.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				histogram, ok := m.Data.(metricdata.Histogram[float64])
+				if !ok || m.Name != "http.client.connection.duration" {
+					continue
+				}
+
+				for _, dp := range histogram.DataPoints {
+					result.Count += dp.Count
+					result.Sum += dp.Sum
+				}
+			}
+		}
+
+		return result
+	}
+
+	client := gohttpc.NewClient()
+
+	for range 3 {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	// The duration is the lifetime of the connection, so it's recorded once the connection is closed.
+	if dp := collect(); dp.Count != 0 {
+		t.Errorf("expected no connection duration of the open connection, got: %d", dp.Count)
+	}
+
+	goutils.CatchWarnErrorFunc(client.Close)
+
+	// The reused connection is recorded once.
+	dp := collect()
+	if dp.Count != 1 {
+		t.Errorf("expected 1 connection duration sample, got: %d", dp.Count)
+	}
+
+	if dp.Sum <= 0 {
+		t.Errorf("expected a positive connection duration, got: %f", dp.Sum)
+	}
+}
+
+func TestPerClientMetrics(t *testing.T) {
+	newReader := func(t *testing.T) (*sdkmetric.ManualReader, *gohttpc.HTTPClientMetrics) {
+		t.Helper()
+
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		t.Cleanup(func() {
+			_ = meterProvider.Shutdown(context.Background())
+		})
+
+		clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return reader, clientMetrics
+	}
+
+	globalReader, globalMetrics := newReader(t)
+
+	gohttpc.SetHTTPClientMetrics(globalMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	readerA, metricsA := newReader(t)
+	readerB, metricsB := newReader(t)
+
+	clientA := gohttpc.NewClient(gohttpc.WithMetrics(metricsA))
+	clientB := gohttpc.NewClient(gohttpc.WithMetrics(metricsB))
+
+	for client, count := range map[*gohttpc.Client]int{clientA: 2, clientB: 1} {
+		for range count {
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		reader        sdkmetric.Reader
+		responses     int64
+		openConnCount int64
+	}{
+		"client_a": {reader: readerA, responses: 2, openConnCount: 1},
+		"client_b": {reader: readerB, responses: 1, openConnCount: 1},
+		"global":   {reader: globalReader},
+	} {
+		results := collectInt64Sums(t, tc.reader)
+
+		if results["http.client.responses"] != tc.responses {
+			t.Errorf("%s: expected %d responses, got: %d", name, tc.responses, results["http.client.responses"])
+		}
+
+		if results["http.client.open_connections"] != tc.openConnCount {
+			t.Errorf(
+				"%s: expected %d open connections, got: %d",
+				name,
+				tc.openConnCount,
+				results["http.client.open_connections"],
+			)
+		}
+	}
+
+	goutils.CatchWarnErrorFunc(clientA.Close)
+	goutils.CatchWarnErrorFunc(clientB.Close)
+}
+
+func TestResponseBodySizeMetric_Chunked(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const chunk = "hello world\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Flushing before the body is complete forces the chunked transfer encoding.
+		for range 3 {
+			_, _ = w.Write([]byte(chunk))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	collect := func() metricdata.HistogramDataPoint[int64] {
+		var rm metricdata.ResourceMetrics
+
+		err := reader.Collect(t.Context(), &rm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result metricdata.HistogramDataPoint[int64]
+
+		for _, scope := range rm.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				histogram, ok := m.Data.(metricdata.Histogram[int64])
+				if !ok || m.Name != "http.client.response.body.size" {
+					continue
+				}
+
+				for _, dp := range histogram.DataPoints {
+					result.Count += dp.Count
+					result.Sum += dp.Sum
+				}
+			}
+		}
+
+		return result
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithMetrics(clientMetrics))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.ContentLength != -1 {
+		t.Fatalf("expected a chunked response, got content length %d", resp.ContentLength)
+	}
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+	goutils.CloseResponse(resp)
+
+	dp := collect()
+	if dp.Count != 1 || dp.Sum != int64(3*len(chunk)) {
+		t.Errorf("expected 1 sample of %d bytes, got %d samples of %d bytes", 3*len(chunk), dp.Count, dp.Sum)
+	}
+}
+
+func TestBytesTransferredMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		requestSize  = 1000
+		responseSize = 3000
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write(bytes.Repeat([]byte("b"), responseSize))
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithMetrics(clientMetrics))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	for range 2 {
+		req := client.R(http.MethodPost, server.URL)
+		req.SetBody(bytes.NewReader(bytes.Repeat([]byte("a"), requestSize)))
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = io.Copy(io.Discard, resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+	}
+
+	results := collectInt64Sums(t, reader)
+
+	if results["http.client.bytes_sent"] != 2*requestSize {
+		t.Errorf("expected %d bytes sent, got: %d", 2*requestSize, results["http.client.bytes_sent"])
+	}
+
+	if results["http.client.bytes_received"] != 2*responseSize {
+		t.Errorf("expected %d bytes received, got: %d", 2*responseSize, results["http.client.bytes_received"])
+	}
+}
+
+func TestMetricAttributeFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	client := gohttpc.NewClient(gohttpc.WithMetricAttributeFilter(func(attr attribute.KeyValue) bool {
+		return attr.Key != "server.port"
+	}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributeSets := map[string][]attribute.Set{}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					attributeSets[m.Name] = append(attributeSets[m.Name], dp.Attributes)
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					attributeSets[m.Name] = append(attributeSets[m.Name], dp.Attributes)
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					attributeSets[m.Name] = append(attributeSets[m.Name], dp.Attributes)
+				}
+			}
+		}
+	}
+
+	testCases := []struct {
+		Name         string
+		ExpectedKeys []attribute.Key
+	}{
+		{
+			Name:         "http.client.request.duration",
+			ExpectedKeys: []attribute.Key{"http.response.status_code"},
+		},
+		{
+			Name:         "http.client.active_requests",
+			ExpectedKeys: []attribute.Key{"server.address", "http.request.method"},
+		},
+		{
+			Name:         "http.client.responses",
+			ExpectedKeys: []attribute.Key{"server.address", "http.request.method"},
+		},
+		{
+			Name:         "http.client.response.body.size",
+			ExpectedKeys: []attribute.Key{"server.address", "http.request.method"},
+		},
+	}
+
+	for _, tc := range testCases {
+		sets := attributeSets[tc.Name]
+		if len(sets) == 0 {
+			t.Errorf("expected data points of %s", tc.Name)
+		}
+
+		for _, set := range sets {
+			if set.HasValue("server.port") {
+				t.Errorf("expected server.port to be filtered from %s, got: %v", tc.Name, set.ToSlice())
+			}
+
+			for _, key := range tc.ExpectedKeys {
+				if !set.HasValue(key) {
+					t.Errorf("expected %s in %s, got: %v", key, tc.Name, set.ToSlice())
+				}
+			}
+		}
+	}
+}
+
+func TestRequestDurationExemplar(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	client := gohttpc.NewClient(gohttpc.EnableClientTrace(true))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	var requestSpan sdktrace.ReadOnlySpan
+
+	for _, span := range recorder.Ended() {
+		if span.Name() == "Request" {
+			requestSpan = span
+		}
+	}
+
+	if requestSpan == nil {
+		t.Fatal("expected the request span")
+	}
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exemplars []metricdata.Exemplar[float64]
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || m.Name != "http.client.request.duration" {
+				continue
+			}
+
+			for _, dp := range histogram.DataPoints {
+				exemplars = append(exemplars, dp.Exemplars...)
+			}
+		}
+	}
+
+	if len(exemplars) == 0 {
+		t.Fatal("expected exemplars of the request duration metric")
+	}
+
+	traceID := requestSpan.SpanContext().TraceID()
+	spanID := requestSpan.SpanContext().SpanID()
+
+	if !bytes.Equal(exemplars[0].TraceID, traceID[:]) || !bytes.Equal(exemplars[0].SpanID, spanID[:]) {
+		t.Errorf(
+			"expected the exemplar of the trace %s and span %s, got: %x %x",
+			traceID,
+			spanID,
+			exemplars[0].TraceID,
+			exemplars[0].SpanID,
+		)
+	}
+}
+
+func collectInt64Sums(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+
+	err := reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := map[string]int64{}
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+
+			for _, dp := range sum.DataPoints {
+				results[m.Name] += dp.Value
+			}
+		}
+	}
+
+	return results
+}
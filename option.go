@@ -15,15 +15,22 @@
 package gohttpc
 
 import (
+	"context"
+	"crypto/x509"
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/failsafe-go/failsafe-go/circuitbreaker"
 	"github.com/failsafe-go/failsafe-go/retrypolicy"
 	"github.com/hasura/goenvconf"
+	"github.com/hasura/gotel/otelutils"
 	"github.com/relychan/gohttpc/authc/authscheme"
 	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
 )
 
 // RequestOptionsGetter abstracts an interface to get the [RequestOptions].
@@ -33,17 +40,55 @@ type RequestOptionsGetter interface {
 
 // RequestOptions defines options for the request.
 type RequestOptions struct {
+	Metrics                     *HTTPClientMetrics
 	CustomAttributesFunc        CustomAttributesFunc
+	SpanNameFunc                SpanNameFunc
+	MetricAttributeFilter       attribute.Filter
 	Retry                       retrypolicy.RetryPolicy[*http.Response]
+	CircuitBreaker              circuitbreaker.CircuitBreaker[*http.Response]
 	Timeout                     time.Duration
+	MaxAttemptsOnError          int
+	MaxAttemptsOnStatus         int
+	ResponseDeadlinePerByte     time.Duration
+	ResponseReadGracePeriod     time.Duration
+	MaxResponseBodySize         int64
 	Authenticator               authscheme.HTTPClientAuthenticator
+	FallbackResponse            FallbackResponseFunc
+	OnRetryGiveUp               RetryGiveUpFunc
+	ResponseCache               Cache
+	CacheKeyFunc                CacheKeyFunc
+	RateLimiter                 *rate.Limiter
+	RetryBudget                 *RetryBudget
+	OnBodyLeak                  BodyLeakFunc
+	ResponseSchema              *ResponseSchema
+	RequestHooks                []RequestHookFunc
+	ResponseHooks               []ResponseHookFunc
+	ExecuteMiddlewares          []ExecuteMiddleware
+	ResponseReaderMiddlewares   []ResponseReaderMiddleware
+	BaseURL                     string
 	UserAgent                   string
+	IdempotencyKeyHeader        string
+	AcceptEncoding              string
+	CompressionMinSize          int
+	MaxLogBodySize              int
 	AllowedTraceRequestHeaders  []string
 	AllowedTraceResponseHeaders []string
+	RedactedHeaders             []string
 	LogLevel                    slog.Level
 	TraceHighCardinalityPath    bool
 	MetricHighCardinalityPath   bool
 	ClientTraceEnabled          bool
+	TraceContextPropagation     bool
+	TracePropagationDisabled    bool
+	BodyHashAttribute           bool
+	CaptureErrorBody            bool
+
+	// throttler delays requests when the rate limit quota of the host is exhausted.
+	throttler *rateLimitThrottler
+	// cancelTags tracks in-flight requests with cancel tags.
+	cancelTags *cancelTagRegistry
+	// bodyCaptureSampler samples requests which capture bodies in debug logs. Nil captures all requests.
+	bodyCaptureSampler *bodyCaptureSampler
 }
 
 var _ RequestOptionsGetter = (*RequestOptions)(nil)
@@ -53,6 +98,15 @@ func (ro *RequestOptions) GetRequestOptions() *RequestOptions {
 	return ro
 }
 
+// getMetrics returns the metrics of the client, or the global metrics if not set.
+func (ro *RequestOptions) getMetrics() *HTTPClientMetrics {
+	if ro.Metrics != nil {
+		return ro.Metrics
+	}
+
+	return GetHTTPClientMetrics()
+}
+
 // IsTraceRequestHeadersEnabled checks if the trace request headers are enabled.
 func (ro *RequestOptions) IsTraceRequestHeadersEnabled() bool {
 	return ro.AllowedTraceRequestHeaders == nil || len(ro.AllowedTraceRequestHeaders) > 0
@@ -63,12 +117,64 @@ func (ro *RequestOptions) IsTraceResponseHeadersEnabled() bool {
 	return ro.AllowedTraceResponseHeaders == nil || len(ro.AllowedTraceResponseHeaders) > 0
 }
 
+// metricAttributeSet creates the attribute set of metrics without attributes which are dropped by the filter.
+func (ro *RequestOptions) metricAttributeSet(attrs ...attribute.KeyValue) attribute.Set {
+	if ro.MetricAttributeFilter == nil {
+		return attribute.NewSet(attrs...)
+	}
+
+	set, _ := attribute.NewSetWithFiltered(attrs, ro.MetricAttributeFilter)
+
+	return set
+}
+
+// filterMetricAttributes returns a copy of the attributes without attributes which are dropped by the filter.
+func (ro *RequestOptions) filterMetricAttributes(attrs []attribute.KeyValue) []attribute.KeyValue {
+	if ro.MetricAttributeFilter == nil {
+		return attrs
+	}
+
+	return slices.DeleteFunc(slices.Clone(attrs), func(attr attribute.KeyValue) bool {
+		return !ro.MetricAttributeFilter(attr)
+	})
+}
+
+// extractTelemetryHeaders extracts headers for logs and span attributes with sensitive values redacted.
+func (ro *RequestOptions) extractTelemetryHeaders(header http.Header, allowedHeaders ...string) [][]string {
+	headers := otelutils.ExtractTelemetryHeaders(header, nil, allowedHeaders...)
+
+	for i, row := range headers {
+		isRedacted := slices.ContainsFunc(ro.RedactedHeaders, func(key string) bool {
+			return strings.EqualFold(key, row[0])
+		})
+		if isRedacted {
+			headers[i] = []string{row[0], otelutils.MaskString}
+		}
+	}
+
+	return headers
+}
+
+// defaultRedactedHeaders are headers whose values are redacted in logs and span attributes by default.
+var defaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
 // ClientOptions defines options for the client.
 type ClientOptions struct {
 	RequestOptions
 	authscheme.HTTPClientAuthenticatorOptions
 
 	HTTPClient *http.Client
+	// VerifyPeerCertificate is the custom hook to validate the certificates of the server
+	// after the normal verification. It applies to transports which are built by the library only.
+	VerifyPeerCertificate VerifyPeerCertificateFunc
+	// HTTP2PriorKnowledge enables HTTP/2 without TLS (h2c) for http:// URLs, without the HTTP/1.1 upgrade.
+	// It applies to transports which are built by the library only.
+	HTTP2PriorKnowledge bool
 }
 
 // NewClientOptions create a new [ClientOptions] instance.
@@ -78,6 +184,8 @@ func NewClientOptions(options ...ClientOption) *ClientOptions {
 			UserAgent:          "gohttpc/" + getBuildVersion(),
 			ClientTraceEnabled: os.Getenv("HTTP_CLIENT_TRACE_ENABLED") == "true",
 			LogLevel:           slog.LevelDebug,
+			RedactedHeaders:    slices.Clone(defaultRedactedHeaders),
+			cancelTags:         newCancelTagRegistry(),
 		},
 		HTTPClientAuthenticatorOptions: *authscheme.NewHTTPClientAuthenticatorOptions(),
 	}
@@ -97,8 +205,10 @@ func (co *ClientOptions) GetRequestOptions() *RequestOptions {
 }
 
 // Clone creates a new ClientOptions instance with copied values.
+// The clone tracks its own in-flight requests, so canceling a tag doesn't affect the original options.
 func (co *ClientOptions) Clone(options ...ClientOption) *ClientOptions {
 	newOptions := *co
+	newOptions.cancelTags = newCancelTagRegistry()
 
 	for _, opt := range options {
 		opt(&newOptions)
@@ -108,8 +218,45 @@ func (co *ClientOptions) Clone(options ...ClientOption) *ClientOptions {
 }
 
 // CustomAttributesFunc abstracts a function to add custom attributes to spans and metrics.
+// The function is called once per request execution and its result is shared by all retry attempts.
+// The attributes are added to metric attribute sets, so they should have low cardinality,
+// e.g. a service tier rather than a user or order ID. Use [Request.SetSpanAttributes] for high-cardinality values.
 type CustomAttributesFunc func(Requester) []attribute.KeyValue
 
+// SpanNameFunc abstracts a function to name the client span of the request,
+// e.g. GET /users/{id} with the route template to group traces without high-cardinality IDs.
+// The default name is used if the function returns an empty string.
+type SpanNameFunc func(req *Request) string
+
+// FallbackResponseFunc abstracts a function to synthesize a fallback response when the request totally fails.
+type FallbackResponseFunc func(req *Request, err error) (*http.Response, error)
+
+// CacheKeyFunc abstracts a function to compute the response cache key of the request.
+// Returning an empty string skips the response cache for the request.
+type CacheKeyFunc func(req *Request) string
+
+// RetryGiveUpFunc abstracts a function which is called when the request gives up retrying.
+// The last response is nil if the last attempt failed with an error.
+type RetryGiveUpFunc func(req *Request, lastResp *http.Response, lastErr error)
+
+// RequestHookFunc abstracts a function to intercept the HTTP request before it is sent.
+type RequestHookFunc func(req *http.Request) error
+
+// ResponseHookFunc abstracts a function to intercept the HTTP response after it is received.
+type ResponseHookFunc func(resp *http.Response) error
+
+// ExecuteFunc abstracts a function to execute the request, including retries and tracing.
+type ExecuteFunc func(ctx context.Context, req *Request) (*http.Response, error)
+
+// ExecuteMiddleware abstracts a function to wrap the whole execution of the request,
+// for cross-cutting concerns such as bulkheading, caching and custom metrics.
+// The middleware may short-circuit the execution by returning without calling next.
+type ExecuteMiddleware func(next ExecuteFunc) ExecuteFunc
+
+// VerifyPeerCertificateFunc abstracts a function to validate the certificates of the server.
+// The verified chains are empty if the normal verification is skipped.
+type VerifyPeerCertificateFunc func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+
 // ClientOption abstracts a function to modify client options.
 type ClientOption func(*ClientOptions)
 
@@ -123,6 +270,25 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithVerifyPeerCertificate sets the custom hook to validate the certificates of the server,
+// such as a custom extension or a SAN policy. The hook runs after the normal verification and
+// the handshake fails if the hook returns an error.
+// It is ignored if a custom HTTP client is set with [WithHTTPClient].
+func WithVerifyPeerCertificate(fn VerifyPeerCertificateFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.VerifyPeerCertificate = fn
+	}
+}
+
+// WithHTTP2PriorKnowledge creates an option to send requests over cleartext HTTP/2 (h2c)
+// to servers which are known to speak HTTP/2 without TLS, such as services in an internal mesh.
+// HTTP/1.1 is disabled on the transport, so the servers must support HTTP/2.
+func WithHTTP2PriorKnowledge() ClientOption {
+	return func(co *ClientOptions) {
+		co.HTTP2PriorKnowledge = true
+	}
+}
+
 // WithTraceHighCardinalityPath enables high cardinality path on traces.
 func WithTraceHighCardinalityPath(enabled bool) ClientOption {
 	return func(co *ClientOptions) {
@@ -130,6 +296,31 @@ func WithTraceHighCardinalityPath(enabled bool) ClientOption {
 	}
 }
 
+// WithTraceContextPropagation ensures the W3C traceparent and tracestate headers are propagated
+// regardless of the global text map propagator.
+func WithTraceContextPropagation(enabled bool) ClientOption {
+	return func(co *ClientOptions) {
+		co.TraceContextPropagation = enabled
+	}
+}
+
+// WithTracePropagation creates an option to enable or disable injecting the trace context into request headers.
+// It's enabled by default. Use [Request.DisableTracePropagation] to disable it for a single request.
+func WithTracePropagation(enabled bool) ClientOption {
+	return func(co *ClientOptions) {
+		co.TracePropagationDisabled = !enabled
+	}
+}
+
+// WithBodyHashAttribute creates an option to add the SHA-256 hash of the request body
+// to the http.request.body.hash span attribute, which helps to debug duplicate requests
+// without exposing the body. Only seekable bodies are hashed.
+func WithBodyHashAttribute(enabled bool) ClientOption {
+	return func(co *ClientOptions) {
+		co.BodyHashAttribute = enabled
+	}
+}
+
 // WithMetricHighCardinalityPath enables high cardinality path on metrics.
 func WithMetricHighCardinalityPath(enabled bool) ClientOption {
 	return func(co *ClientOptions) {
@@ -144,6 +335,32 @@ func WithCustomAttributesFunc(fn CustomAttributesFunc) ClientOption {
 	}
 }
 
+// WithMetrics creates an option to record the metrics of the client to the instruments,
+// instead of the global metrics which are set by [SetHTTPClientMetrics].
+// It keeps metrics of independent clients with separate meters in the same process apart.
+func WithMetrics(metrics *HTTPClientMetrics) ClientOption {
+	return func(co *ClientOptions) {
+		co.Metrics = metrics
+	}
+}
+
+// WithSpanNameFunc sets the function to name client spans of requests.
+// By default, the span is named by the method, followed by the path if [WithTraceHighCardinalityPath] is enabled.
+func WithSpanNameFunc(fn SpanNameFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.SpanNameFunc = fn
+	}
+}
+
+// WithMetricAttributeFilter creates an option to filter attributes of request metrics to control the cardinality.
+// The filter returns false to drop the attribute, e.g. server.port. Span attributes and connection metrics
+// of the transport aren't filtered.
+func WithMetricAttributeFilter(filter attribute.Filter) ClientOption {
+	return func(co *ClientOptions) {
+		co.MetricAttributeFilter = filter
+	}
+}
+
 // WithRetry creates an option to set the default retry policy.
 func WithRetry(retry retrypolicy.RetryPolicy[*http.Response]) ClientOption {
 	return func(co *ClientOptions) {
@@ -151,20 +368,265 @@ func WithRetry(retry retrypolicy.RetryPolicy[*http.Response]) ClientOption {
 	}
 }
 
+// WithConnectionErrorRetryBudget creates an option to limit the number of attempts
+// which fail with transport errors, such as connection resets or refusals.
+// The retry policy still caps the total number of attempts. Zero means no separate limit.
+func WithConnectionErrorRetryBudget(maxAttempts int) ClientOption {
+	return func(co *ClientOptions) {
+		co.MaxAttemptsOnError = max(maxAttempts, 0)
+	}
+}
+
+// WithStatusRetryBudget creates an option to limit the number of attempts
+// which fail with retryable HTTP statuses, such as 5xx responses.
+// The retry policy still caps the total number of attempts. Zero means no separate limit.
+func WithStatusRetryBudget(maxAttempts int) ClientOption {
+	return func(co *ClientOptions) {
+		co.MaxAttemptsOnStatus = max(maxAttempts, 0)
+	}
+}
+
+// WithResponseDeadlinePerByte creates an option to enforce the minimum throughput of response body reads
+// to defend against slow-trickle responses. Reading the first n bytes of the body must complete within
+// the grace period plus n times the deadline per byte, otherwise the read aborts with [ErrResponseBodyTooSlow].
+// For example, a deadline of 1ms per byte requires at least 1000 bytes per second. Zero disables the check.
+// The grace period starts on the first read and defaults to [DefaultResponseReadGracePeriod] if not positive.
+func WithResponseDeadlinePerByte(perByte time.Duration, gracePeriod time.Duration) ClientOption {
+	return func(co *ClientOptions) {
+		if gracePeriod <= 0 {
+			gracePeriod = DefaultResponseReadGracePeriod
+		}
+
+		co.ResponseDeadlinePerByte = max(perByte, 0)
+		co.ResponseReadGracePeriod = gracePeriod
+	}
+}
+
+// DefaultResponseReadGracePeriod is the default grace period of [WithResponseDeadlinePerByte].
+const DefaultResponseReadGracePeriod = time.Second
+
+// WithMaxResponseBodySize creates an option to limit the size of response bodies to protect against
+// huge bodies of misbehaving servers. The limit applies to the decompressed body.
+// Reading beyond the limit fails with [ErrResponseBodyTooLarge]. Zero means no limit.
+func WithMaxResponseBodySize(size int64) ClientOption {
+	return func(co *ClientOptions) {
+		co.MaxResponseBodySize = max(size, 0)
+	}
+}
+
+// WithMaxLogBodySize creates an option to truncate request and response bodies to n bytes
+// in debug logs and span attributes. The body which is read by the caller is never truncated.
+// Zero means no limit.
+func WithMaxLogBodySize(n int) ClientOption {
+	return func(co *ClientOptions) {
+		co.MaxLogBodySize = max(n, 0)
+	}
+}
+
+// WithBodyCaptureSampleRate creates an option to capture request and response bodies in debug logs
+// and span attributes for a fraction of requests only, e.g. 0.01 for 1% of requests.
+// The rate is clamped to [0, 1]. Requests which aren't sampled still log the metadata.
+// By default, the bodies of all requests are captured when the debug level is enabled.
+func WithBodyCaptureSampleRate(rate float64) ClientOption {
+	return func(co *ClientOptions) {
+		co.bodyCaptureSampler = newBodyCaptureSampler(rate)
+	}
+}
+
+// WithCaptureErrorBody creates an option to capture the body of 4xx and 5xx responses instead of closing it unread.
+// The body is read up to [MaxCapturedErrorBodySize] bytes, or the maximum response body size if smaller.
+// The captured body remains readable from the returned response and is attached to the error in the body extension.
+func WithCaptureErrorBody(enabled bool) ClientOption {
+	return func(co *ClientOptions) {
+		co.CaptureErrorBody = enabled
+	}
+}
+
+// WithResponseCache creates an option to cache responses of GET and HEAD requests which have
+// ETag or Last-Modified validators. Subsequent requests send If-None-Match and If-Modified-Since headers,
+// and the cached body is returned on a 304 Not Modified response. See [NewMemoryCache].
+// Responses with the Vary header are cached separately by the values of the listed request headers.
+func WithResponseCache(cache Cache) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseCache = cache
+	}
+}
+
+// WithCacheKeyFunc creates an option to compute response cache keys with a custom function,
+// e.g. to cache load-balanced responses by path regardless of the host which served them.
+// The default key is the request method and the resolved request URL.
+func WithCacheKeyFunc(fn CacheKeyFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.CacheKeyFunc = fn
+	}
+}
+
+// WithRateLimiter creates an option to throttle outbound requests of the client with the rate limiter.
+// Every attempt, including retries, waits for the limiter with respect to the context deadline.
+func WithRateLimiter(limiter *rate.Limiter) ClientOption {
+	return func(co *ClientOptions) {
+		co.RateLimiter = limiter
+	}
+}
+
+// WithCircuitBreaker creates an option to set the circuit breaker of the client.
+// When the circuit is open, requests fail fast with [ErrCircuitBreakerOpen] without dialing.
+// Transport errors and server outage statuses, see [IsServerOutageStatus], are recorded as failures.
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(co *ClientOptions) {
+		co.CircuitBreaker = newCircuitBreaker(config, co.getMetrics)
+	}
+}
+
+// WithRetryBudget creates an option to cap the retry amplification of the client with a shared retry budget.
+// Each successful request deposits ratio tokens and each retry consumes a token, while minPerSec retries
+// per second are always permitted. Failed requests aren't retried when the budget is exhausted.
+func WithRetryBudget(ratio float64, minPerSec int) ClientOption {
+	return func(co *ClientOptions) {
+		co.RetryBudget = NewRetryBudget(ratio, minPerSec)
+	}
+}
+
+// WithBodyLeakDetection creates an option to detect response bodies which are garbage collected
+// without being closed. The function is called with the stack trace of the request execution.
+// The detection is done by the garbage collector, so it should be used for development only.
+func WithBodyLeakDetection(onLeak BodyLeakFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.OnBodyLeak = onLeak
+	}
+}
+
+// WithResponseValidationSchema creates an option to validate JSON bodies of successful responses
+// against the schema. Requests whose responses don't conform fail with [ErrResponseSchemaValidation].
+// Streamed responses are not validated.
+func WithResponseValidationSchema(schema *ResponseSchema) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseSchema = schema
+	}
+}
+
+// WithFallbackResponse creates an option to set the function to synthesize a fallback response,
+// which is called when the request fails after exhausting retries or hosts.
+// The function isn't called on non-retryable failures, e.g. 4xx statuses, or when the context of the caller is done.
+// The fallback result replaces the failed response and error.
+// If the function returns a nil response and a nil error, the original failure is returned.
+func WithFallbackResponse(fn FallbackResponseFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.FallbackResponse = fn
+	}
+}
+
+// WithOnRetryGiveUp creates an option to set the callback which is called once
+// when the retry policy or a retry budget is exhausted, for example, to enqueue the request to a dead-letter queue.
+// The callback receives the response and error of the last attempt.
+func WithOnRetryGiveUp(fn RetryGiveUpFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.OnRetryGiveUp = fn
+	}
+}
+
+// WithRateLimitAutoThrottle enables or disables the auto-throttle based on RateLimit response headers.
+// When the remaining quota of a host reaches zero, subsequent requests to that host are delayed until the quota resets.
+func WithRateLimitAutoThrottle(enabled bool) ClientOption {
+	return func(co *ClientOptions) {
+		if enabled {
+			co.throttler = newRateLimitThrottler()
+		} else {
+			co.throttler = nil
+		}
+	}
+}
+
+// DefaultIdempotencyKeyHeader is the default header name of the idempotency key.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey enables the auto-injection of a unique idempotency key to POST and PATCH requests.
+// The key is generated once per request execution and reused on every retry attempt.
+// The header name defaults to Idempotency-Key if empty.
+func WithIdempotencyKey(header string) ClientOption {
+	return func(co *ClientOptions) {
+		if header == "" {
+			header = DefaultIdempotencyKeyHeader
+		}
+
+		co.IdempotencyKeyHeader = header
+	}
+}
+
+// WithAcceptEncoding sets the Accept-Encoding header of outgoing requests to negotiate the response compression.
+// Unsupported encodings are ignored. All supported encodings are advertised if the list is empty.
+// Compressed responses are decoded transparently.
+func WithAcceptEncoding(encodings ...string) ClientOption {
+	return func(co *ClientOptions) {
+		co.AcceptEncoding = negotiateAcceptEncoding(encodings)
+	}
+}
+
+// WithCompressionMinSize sets the minimum size in bytes of the request body to be compressed.
+// Smaller bodies are sent uncompressed and the Content-Encoding header is removed.
+func WithCompressionMinSize(size int) ClientOption {
+	return func(co *ClientOptions) {
+		co.CompressionMinSize = size
+	}
+}
+
+// WithRequestHook adds a hook which is invoked right before every request attempt is sent.
+// Hooks run in registration order. A hook returning an error aborts the request.
+// Hooks run before the request authenticator, so headers set by hooks are covered by request signatures.
+func WithRequestHook(hook RequestHookFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.RequestHooks = append(slices.Clip(co.RequestHooks), hook)
+	}
+}
+
+// WithResponseHook adds a hook which is invoked right after every response is received, before the status is checked.
+// Hooks run in registration order. A hook returning an error aborts the request.
+func WithResponseHook(hook ResponseHookFunc) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseHooks = append(slices.Clip(co.ResponseHooks), hook)
+	}
+}
+
+// WithExecuteMiddleware adds middlewares which wrap the whole execution of every request, including retries and tracing.
+// Middlewares are composed as a chain in registration order, so the first middleware is the outermost.
+func WithExecuteMiddleware(middlewares ...ExecuteMiddleware) ClientOption {
+	return func(co *ClientOptions) {
+		co.ExecuteMiddlewares = append(slices.Clip(co.ExecuteMiddlewares), middlewares...)
+	}
+}
+
+// WithResponseReaderMiddleware adds middlewares which wrap the response body after decompression,
+// so callers read the transformed stream lazily. Middlewares are applied in registration order,
+// so the last middleware is the outermost reader. Closing the response body always closes the underlying body.
+// The response cache stores the untransformed body, so cached responses are transformed when they are served.
+func WithResponseReaderMiddleware(middlewares ...ResponseReaderMiddleware) ClientOption {
+	return func(co *ClientOptions) {
+		co.ResponseReaderMiddlewares = append(slices.Clip(co.ResponseReaderMiddlewares), middlewares...)
+	}
+}
+
 // WithTimeout creates an option to set the default timeout.
+// The timeout of the request takes precedence. The deadline of the execution context still applies if it is tighter.
+// Note that the timeout of the underlying [http.Client] is enforced separately by the standard library.
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(co *ClientOptions) {
 		co.Timeout = timeout
 	}
 }
 
-// WithLogLevel creates an option to set the level for printing logs.
+// WithLogLevel creates an option to set the level for printing logs of successful requests.
+// The default level is Debug. Failed requests are always logged at the Error level.
 func WithLogLevel(level slog.Level) ClientOption {
 	return func(co *ClientOptions) {
 		co.LogLevel = level
 	}
 }
 
+// WithSuccessLogLevel is the alias of [WithLogLevel].
+func WithSuccessLogLevel(level slog.Level) ClientOption {
+	return WithLogLevel(level)
+}
+
 // WithAuthenticator creates an option to set the default authenticator.
 func WithAuthenticator(authenticator authscheme.HTTPClientAuthenticator) ClientOption {
 	return func(co *ClientOptions) {
@@ -193,6 +655,23 @@ func AllowTraceResponseHeaders(keys []string) ClientOption {
 	}
 }
 
+// WithRedactedHeaders creates an option to set headers whose values are redacted in logs and span attributes.
+// Header names are matched case-insensitively. Defaults to Authorization, Proxy-Authorization, Cookie and Set-Cookie.
+// Headers which contain sensitive keywords, e.g. auth, key, secret or token, are always redacted.
+func WithRedactedHeaders(keys ...string) ClientOption {
+	return func(co *ClientOptions) {
+		co.RedactedHeaders = keys
+	}
+}
+
+// WithBaseURL creates an option to set the base URL which relative request URLs are joined with.
+// Absolute request URLs are sent as-is. See [JoinURL] for the joining rules.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(co *ClientOptions) {
+		co.BaseURL = baseURL
+	}
+}
+
 // WithUserAgent creates an option to set the user agent.
 func WithUserAgent(userAgent string) ClientOption {
 	return func(co *ClientOptions) {
@@ -0,0 +1,94 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"io"
+	"net/http"
+)
+
+// ProgressFunc abstracts a function to report the progress of a transfer.
+// The total size is the content length of the body, or -1 if unknown.
+type ProgressFunc func(written int64, total int64)
+
+// UploadProgress returns the callback to report the progress of the request body upload.
+func (r *Request) UploadProgress() ProgressFunc {
+	return r.uploadProgress
+}
+
+// SetUploadProgress sets the callback to report the progress of the request body upload.
+// The callback is invoked as bytes of the body are sent, after the compression if any.
+// The counter restarts on every retry attempt. Returns the request itself for chaining.
+func (r *Request) SetUploadProgress(progress ProgressFunc) *Request {
+	r.uploadProgress = progress
+
+	return r
+}
+
+// withUploadProgress wraps the body of the request to report the upload progress.
+func (r *Request) withUploadProgress(req *http.Request) {
+	if r.uploadProgress == nil || req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+
+	total := req.ContentLength
+	if total <= 0 {
+		total = -1
+	}
+
+	req.Body = &progressReadCloser{
+		ReadCloser: req.Body,
+		total:      total,
+		progress:   r.uploadProgress,
+	}
+}
+
+// progressWriter wraps the writer to report the number of written bytes.
+type progressWriter struct {
+	io.Writer
+
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.Writer.Write(p)
+	if n > 0 {
+		pw.written += int64(n)
+		pw.progress(pw.written, pw.total)
+	}
+
+	return n, err
+}
+
+// progressReadCloser wraps the reader to report the number of read bytes.
+type progressReadCloser struct {
+	io.ReadCloser
+
+	read     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (prc *progressReadCloser) Read(p []byte) (int, error) {
+	n, err := prc.ReadCloser.Read(p)
+	if n > 0 {
+		prc.read += int64(n)
+		prc.progress(prc.read, prc.total)
+	}
+
+	return n, err
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+)
+
+func TestRequest_SetUploadProgress(t *testing.T) {
+	const size = 1 << 20
+
+	var (
+		attempts     atomic.Int32
+		receivedSize atomic.Int64
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		receivedSize.Store(n)
+
+		// Fails the first attempt of retry requests to verify the counter restarts.
+		if r.URL.Path == "/retry" && attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 2,
+		Delay:       &delay,
+		HTTPStatus:  []int{http.StatusServiceUnavailable},
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithRetry(retry))
+	defer client.Close()
+
+	testCases := []struct {
+		name            string
+		path            string
+		contentEncoding string
+		expectedResets  int
+	}{
+		{
+			name: "plain body",
+			path: "/",
+		},
+		{
+			name:            "compressed body",
+			path:            "/",
+			contentEncoding: "gzip",
+		},
+		{
+			name:           "retry",
+			path:           "/retry",
+			expectedResets: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var lastWritten, lastTotal int64
+
+			resets := 0
+
+			req := client.R(http.MethodPost, server.URL+tc.path)
+			req.SetBody(bytes.NewReader(bytes.Repeat([]byte("a"), size)))
+
+			if tc.contentEncoding != "" {
+				req.Header().Set("Content-Encoding", tc.contentEncoding)
+			}
+
+			req.SetUploadProgress(func(written, total int64) {
+				if written < lastWritten {
+					resets++
+				}
+
+				lastWritten = written
+				lastTotal = total
+			})
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			transmitted := receivedSize.Load()
+
+			if lastWritten != transmitted || lastTotal != transmitted {
+				t.Errorf("expected written and total equal %d, got: %d, %d", transmitted, lastWritten, lastTotal)
+			}
+
+			if tc.contentEncoding == "" && transmitted != size {
+				t.Errorf("expected %d transmitted bytes, got: %d", size, transmitted)
+			}
+
+			if tc.contentEncoding != "" && transmitted >= size {
+				t.Errorf("expected compressed size smaller than %d, got: %d", size, transmitted)
+			}
+
+			if resets != tc.expectedResets {
+				t.Errorf("expected %d counter resets, got: %d", tc.expectedResets, resets)
+			}
+		})
+	}
+}
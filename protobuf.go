@@ -0,0 +1,55 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+
+	"github.com/relychan/goutils/httpheader"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentTypeProtobuf is the content type of protobuf messages.
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// SetProtobufBody marshals the protobuf message to the request body
+// and sets the Content-Type header to application/x-protobuf.
+// The body is seekable so it can be replayed on retries.
+func (r *Request) SetProtobufBody(msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	r.SetBody(bytes.NewReader(data))
+	r.Header().Set(httpheader.ContentType, ContentTypeProtobuf)
+
+	return nil
+}
+
+// UnmarshalProto reads the response body and unmarshals it to the protobuf message.
+// The response body is closed after reading.
+func (r *Response) UnmarshalProto(msg proto.Message) error {
+	data, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		return ErrResponseBodyNoContent
+	}
+
+	return proto.Unmarshal(data, msg)
+}
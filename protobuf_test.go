@@ -0,0 +1,132 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestProtobufBody(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != gohttpc.ContentTypeProtobuf {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		// Fail the first attempt to verify that the body is replayed.
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		var msg structpb.Struct
+
+		err = proto.Unmarshal(body, &msg)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		msg.Fields["echo"] = structpb.NewBoolValue(true)
+
+		data, err := proto.Marshal(&msg)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", gohttpc.ContentTypeProtobuf)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 2,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithRetry(retry))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	input, err := structpb.NewStruct(map[string]any{
+		"id":   1,
+		"name": "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := client.R(http.MethodPost, server.URL)
+
+	err = req.SetProtobufBody(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output structpb.Struct
+
+	err = gohttpc.NewResponse(resp).UnmarshalProto(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts.Load() != 2 {
+		t.Errorf("expected 2 attempts, got: %d", attempts.Load())
+	}
+
+	result := output.AsMap()
+	if result["id"] != float64(1) || result["name"] != "test" || result["echo"] != true {
+		t.Errorf("expected the echoed message, got: %v", result)
+	}
+
+	err = gohttpc.NewResponse(&http.Response{Body: http.NoBody}).UnmarshalProto(&output)
+	if !errors.Is(err, gohttpc.ErrResponseBodyNoContent) {
+		t.Errorf("expected the no content error, got: %v", err)
+	}
+}
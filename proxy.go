@@ -0,0 +1,60 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+type proxyURLContextKey struct{}
+
+// parseProxyURL parses and validates the proxy URL.
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(supportedProxySchemes, strings.ToLower(proxyURL.Scheme)) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProxyScheme, proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("%w: %s", ErrProxyHostRequired, rawURL)
+	}
+
+	return proxyURL, nil
+}
+
+// requestProxy returns the proxy function of the transport which prefers the proxy of the request
+// and falls back to the given function if the request has no proxy.
+func requestProxy(
+	fallback func(*http.Request) (*url.URL, error),
+) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, ok := req.Context().Value(proxyURLContextKey{}).(*url.URL)
+		if ok {
+			return proxyURL, nil
+		}
+
+		return fallback(req)
+	}
+}
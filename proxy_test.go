@@ -0,0 +1,154 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestRequest_SetProxy(t *testing.T) {
+	type proxyRequest struct {
+		method string
+		target string
+	}
+
+	proxyRequests := make(chan proxyRequest, 1)
+
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.String()
+		if r.Method == http.MethodConnect {
+			target = r.Host
+		}
+
+		proxyRequests <- proxyRequest{method: r.Method, target: target}
+
+		if r.Method == http.MethodConnect {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		w.Header().Set("X-Proxy", "true")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxyServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	t.Run("http", func(t *testing.T) {
+		req := client.R(http.MethodGet, "http://example.local/todos/1")
+
+		req.SetProxy(proxyServer.URL)
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if resp.Header.Get("X-Proxy") != "true" {
+			t.Error("expected the response from the proxy")
+		}
+
+		expected := proxyRequest{method: http.MethodGet, target: "http://example.local/todos/1"}
+		if pr := <-proxyRequests; pr != expected {
+			t.Errorf("expected the proxy to see %v, got: %v", expected, pr)
+		}
+	})
+
+	t.Run("https", func(t *testing.T) {
+		req := client.R(http.MethodGet, "https://example.local/todos/1")
+
+		req.SetProxy(proxyServer.URL)
+
+		_, err := req.Execute(t.Context()) //nolint:bodyclose
+		if err == nil {
+			t.Fatal("expected the rejected tunnel error, got nil")
+		}
+
+		expected := proxyRequest{method: http.MethodConnect, target: "example.local:443"}
+		if pr := <-proxyRequests; pr != expected {
+			t.Errorf("expected the proxy to see %v, got: %v", expected, pr)
+		}
+	})
+
+	t.Run("without_proxy", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if resp.Header.Get("X-Proxy") != "" {
+			t.Error("expected the request not to be routed through the proxy")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, proxyURL := range []string{"ftp://localhost:21", "socks5://"} {
+			req := client.R(http.MethodGet, server.URL)
+
+			req.SetProxy(proxyURL)
+
+			_, err := req.Execute(t.Context()) //nolint:bodyclose
+			if !errors.Is(err, gohttpc.ErrInvalidProxyURL) {
+				t.Errorf("expected ErrInvalidProxyURL for %s, got: %v", proxyURL, err)
+			}
+
+			if !errors.Is(err, gohttpc.ErrUnsupportedProxyScheme) &&
+				!errors.Is(err, gohttpc.ErrProxyHostRequired) {
+				t.Errorf("expected invalid proxy error for %s, got: %v", proxyURL, err)
+			}
+
+			if req.Proxy() != nil {
+				t.Errorf("expected no proxy for %s, got: %s", proxyURL, req.Proxy())
+			}
+		}
+
+		req := client.R(http.MethodGet, server.URL)
+		req.SetProxy("ftp://localhost:21").SetProxy("socks5://localhost:1080")
+
+		if req.Proxy().String() != "socks5://localhost:1080" {
+			t.Errorf("expected the socks5 proxy, got: %s", req.Proxy())
+		}
+
+		req.SetProxy("")
+
+		if req.Proxy() != nil {
+			t.Errorf("expected the proxy to be removed, got: %v", req.Proxy())
+		}
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected the valid proxy to clear the previous error, got: %v", err)
+		}
+
+		goutils.CloseResponse(resp)
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limit header names defined by the RateLimit header fields draft.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimitInfo represents the rate limit quota advertised by the server via RateLimit headers.
+type RateLimitInfo struct {
+	// The request quota of the current time window. Zero if the header is absent.
+	Limit int64
+	// The remaining quota units of the current time window.
+	Remaining int64
+	// The duration until the quota resets.
+	Reset time.Duration
+}
+
+// ParseRateLimitInfo parses RateLimit headers from the response header.
+// Returns nil if the remaining header is absent or invalid.
+func ParseRateLimitInfo(header http.Header) *RateLimitInfo {
+	remaining, ok := parseRateLimitHeaderValue(header, RateLimitRemainingHeader)
+	if !ok {
+		return nil
+	}
+
+	result := &RateLimitInfo{
+		Remaining: remaining,
+	}
+
+	if limit, ok := parseRateLimitHeaderValue(header, RateLimitLimitHeader); ok {
+		result.Limit = limit
+	}
+
+	if reset, ok := parseRateLimitHeaderValue(header, RateLimitResetHeader); ok {
+		result.Reset = time.Duration(reset) * time.Second
+	}
+
+	return result
+}
+
+func parseRateLimitHeaderValue(header http.Header, key string) (int64, bool) {
+	rawValue := strings.TrimSpace(header.Get(key))
+	if rawValue == "" {
+		return 0, false
+	}
+
+	// The limit header may contain quota policies after the first comma or semicolon, e.g. 100, 100;w=60.
+	if index := strings.IndexAny(rawValue, ",;"); index >= 0 {
+		rawValue = strings.TrimSpace(rawValue[:index])
+	}
+
+	value, err := strconv.ParseInt(rawValue, 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+
+	return value, true
+}
+
+// rateLimitThrottler delays requests to hosts whose rate limit quota is exhausted until the quota resets.
+type rateLimitThrottler struct {
+	lock   sync.Mutex
+	resets map[string]time.Time
+}
+
+func newRateLimitThrottler() *rateLimitThrottler {
+	return &rateLimitThrottler{
+		resets: map[string]time.Time{},
+	}
+}
+
+// Wait blocks until the quota of the host resets or the context is done.
+func (rt *rateLimitThrottler) Wait(ctx context.Context, host string) error {
+	rt.lock.Lock()
+	resetAt, ok := rt.resets[host]
+	rt.lock.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	delay := time.Until(resetAt)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe records the rate limit quota of the host from response headers.
+func (rt *rateLimitThrottler) Observe(host string, header http.Header) {
+	info := ParseRateLimitInfo(header)
+	if info == nil {
+		return
+	}
+
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+
+	if info.Remaining > 0 || info.Reset <= 0 {
+		delete(rt.resets, host)
+
+		return
+	}
+
+	rt.resets[host] = time.Now().Add(info.Reset)
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestResponseRateLimit(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Header   http.Header
+		Expected *gohttpc.RateLimitInfo
+	}{
+		{
+			Name: "all_headers",
+			Header: http.Header{
+				gohttpc.RateLimitLimitHeader:     []string{"100"},
+				gohttpc.RateLimitRemainingHeader: []string{"42"},
+				gohttpc.RateLimitResetHeader:     []string{"30"},
+			},
+			Expected: &gohttpc.RateLimitInfo{
+				Limit:     100,
+				Remaining: 42,
+				Reset:     30 * time.Second,
+			},
+		},
+		{
+			Name: "limit_with_policy",
+			Header: http.Header{
+				gohttpc.RateLimitLimitHeader:     []string{"10, 10;w=1, 50;w=60"},
+				gohttpc.RateLimitRemainingHeader: []string{"0"},
+			},
+			Expected: &gohttpc.RateLimitInfo{
+				Limit: 10,
+			},
+		},
+		{
+			Name: "missing_remaining",
+			Header: http.Header{
+				gohttpc.RateLimitLimitHeader: []string{"100"},
+			},
+		},
+		{
+			Name: "invalid_remaining",
+			Header: http.Header{
+				gohttpc.RateLimitRemainingHeader: []string{"abc"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tc.Header {
+					w.Header()[key] = values
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := gohttpc.NewClient()
+			defer goutils.CatchWarnErrorFunc(client.Close)
+
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			result := gohttpc.NewResponse(resp).RateLimit()
+
+			if tc.Expected == nil {
+				if result != nil {
+					t.Errorf("expected nil rate limit info, got: %+v", result)
+				}
+
+				return
+			}
+
+			if result == nil || *result != *tc.Expected {
+				t.Errorf("expected rate limit info %+v, got: %+v", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestRateLimitAutoThrottle(t *testing.T) {
+	var count atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) == 1 {
+			w.Header().Set(gohttpc.RateLimitRemainingHeader, "0")
+			w.Header().Set(gohttpc.RateLimitResetHeader, "1")
+		} else {
+			w.Header().Set(gohttpc.RateLimitRemainingHeader, "10")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("delays the next request until reset", func(t *testing.T) {
+		count.Store(0)
+
+		client := gohttpc.NewClient(gohttpc.WithRateLimitAutoThrottle(true))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		start := time.Now()
+
+		resp, err = client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+			t.Errorf("expected the second request to be delayed until reset, got: %s", elapsed)
+		}
+
+		// the quota is refilled so the next request isn't delayed.
+		start = time.Now()
+
+		resp, err = client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		if elapsed := time.Since(start); elapsed >= 900*time.Millisecond {
+			t.Errorf("expected the third request not to be delayed, got: %s", elapsed)
+		}
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		count.Store(0)
+
+		client := gohttpc.NewClient(gohttpc.WithRateLimitAutoThrottle(true))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = client.R(http.MethodGet, server.URL).Execute(ctx) //nolint:bodyclose
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context deadline exceeded, got: %v", err)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		count.Store(0)
+
+		client := gohttpc.NewClient()
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		start := time.Now()
+
+		for range 2 {
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+
+		if elapsed := time.Since(start); elapsed >= 900*time.Millisecond {
+			t.Errorf("expected requests not to be delayed, got: %s", elapsed)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/time/rate"
+)
+
+// queueReasonKey is the attribute key of the reason why the request waits in the queue.
+const queueReasonKey = attribute.Key("http.client.queue.reason")
+
+const (
+	// queueReasonRateLimit is the reason of requests which wait for the client-side rate limiter.
+	queueReasonRateLimit = "rate_limit"
+	// queueReasonThrottle is the reason of requests which wait for the rate limit quota of the server to be reset.
+	queueReasonThrottle = "throttle"
+)
+
+// recordQueueDuration records the duration of the request waiting in the queue with the reason.
+func recordQueueDuration(
+	ctx context.Context,
+	startTime time.Time,
+	reason string,
+	options ...metric.RecordOption,
+) {
+	metricsFromContext(ctx).QueueDuration.Record(
+		ctx,
+		time.Since(startTime).Seconds(),
+		append(slices.Clip(options), metric.WithAttributes(queueReasonKey.String(reason)))...,
+	)
+}
+
+// WaitRateLimiter waits until the client-side rate limiter permits the request,
+// or the context is done. Requests which are delayed by the limiter are counted
+// by the rate limited requests metric, and the waiting time is recorded by the queue duration metric.
+// Returns an error immediately if the delay exceeds the context deadline.
+func WaitRateLimiter(ctx context.Context, limiter *rate.Limiter, options ...metric.AddOption) error {
+	if limiter == nil {
+		return nil
+	}
+
+	reservation := limiter.Reserve()
+	if !reservation.OK() {
+		return ErrRateLimiterBurstExceeded
+	}
+
+	startTime := time.Now()
+
+	// Attribute options of the counter are also measurement options of the histogram.
+	recordOptions := make([]metric.RecordOption, 0, len(options))
+
+	for _, option := range options {
+		if recordOption, ok := option.(metric.RecordOption); ok {
+			recordOptions = append(recordOptions, recordOption)
+		}
+	}
+
+	defer recordQueueDuration(ctx, startTime, queueReasonRateLimit, recordOptions...)
+
+	delay := reservation.Delay()
+	if delay <= 0 {
+		return nil
+	}
+
+	metricsFromContext(ctx).RateLimitedRequests.Add(ctx, 1, options...)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		reservation.Cancel()
+
+		return fmt.Errorf("rate limiter delay of %s exceeds the context deadline: %w", delay, context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		reservation.Cancel()
+
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
+}
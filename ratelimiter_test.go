@@ -0,0 +1,199 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("throughput_is_capped", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		defer func() {
+			_ = meterProvider.Shutdown(t.Context())
+		}()
+
+		clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gohttpc.SetHTTPClientMetrics(clientMetrics)
+		defer gohttpc.SetHTTPClientMetrics(nil)
+
+		// 20 requests per second with the burst of 1.
+		client := gohttpc.NewClient(gohttpc.WithRateLimiter(rate.NewLimiter(rate.Every(50*time.Millisecond), 1)))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		start := time.Now()
+
+		for range 5 {
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+
+		if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+			t.Errorf("expected 5 requests to take at least 200ms, got: %s", elapsed)
+		}
+
+		results := collectInt64Sums(t, reader)
+		if results["http.client.rate_limited_requests"] != 4 {
+			t.Errorf("expected 4 rate limited requests, got: %d", results["http.client.rate_limited_requests"])
+		}
+	})
+
+	t.Run("queue_duration", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+		defer func() {
+			_ = meterProvider.Shutdown(t.Context())
+		}()
+
+		clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gohttpc.SetHTTPClientMetrics(clientMetrics)
+		defer gohttpc.SetHTTPClientMetrics(nil)
+
+		client := gohttpc.NewClient(gohttpc.WithRateLimiter(rate.NewLimiter(rate.Every(100*time.Millisecond), 1)))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		// The first request is permitted by the burst and the second one waits.
+		for range 2 {
+			resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+		}
+
+		var rm metricdata.ResourceMetrics
+
+		err = reader.Collect(t.Context(), &rm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var dataPoints []metricdata.HistogramDataPoint[float64]
+
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != "http.client.queue.duration" {
+					continue
+				}
+
+				histogram, ok := m.Data.(metricdata.Histogram[float64])
+				if ok {
+					dataPoints = append(dataPoints, histogram.DataPoints...)
+				}
+			}
+		}
+
+		if len(dataPoints) != 1 {
+			t.Fatalf("expected 1 data point of the queue duration, got: %d", len(dataPoints))
+		}
+
+		dataPoint := dataPoints[0]
+
+		if reason, ok := dataPoint.Attributes.Value("http.client.queue.reason"); !ok ||
+			reason.AsString() != "rate_limit" {
+			t.Errorf("expected the rate_limit reason, got: %v", reason)
+		}
+
+		if dataPoint.Count != 2 {
+			t.Fatalf("expected 2 queue durations, got: %d", dataPoint.Count)
+		}
+
+		if minValue, ok := dataPoint.Min.Value(); !ok || minValue > 0.02 {
+			t.Errorf("expected a near-zero queue duration of the unthrottled request, got: %f", minValue)
+		}
+
+		if maxValue, ok := dataPoint.Max.Value(); !ok || maxValue < 0.05 {
+			t.Errorf("expected a queue duration of the throttled request, got: %f", maxValue)
+		}
+	})
+
+	t.Run("deadline_exceeded", func(t *testing.T) {
+		client := gohttpc.NewClient(gohttpc.WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err = client.R(http.MethodGet, server.URL).Execute(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected deadline exceeded error, got: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("expected to fail without waiting for the deadline, got: %s", elapsed)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		client := gohttpc.NewClient(gohttpc.WithRateLimiter(rate.NewLimiter(rate.Every(time.Hour), 1)))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		_, err = client.R(http.MethodGet, server.URL).Execute(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context canceled error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestRedactedHeaders(t *testing.T) {
+	const (
+		bearerToken = "Bearer s3cr3t-b34r3r-t0k3n"
+		cookie      = "session=c00k13-v4lu3"
+		tenant      = "t3n4nt-1d"
+	)
+
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", cookie)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Options  []gohttpc.ClientOption
+		Secrets  []string
+		Visibles []string
+	}{
+		{
+			Name:     "default",
+			Secrets:  []string{bearerToken, cookie},
+			Visibles: []string{tenant},
+		},
+		{
+			Name:     "custom",
+			Options:  []gohttpc.ClientOption{gohttpc.WithRedactedHeaders("x-tenant-id", "COOKIE", "set-cookie")},
+			Secrets:  []string{bearerToken, cookie, tenant},
+			Visibles: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var logs bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			ctx := context.WithValue(t.Context(), otelutils.LoggerContextKey, logger)
+
+			endedSpans := len(recorder.Ended())
+
+			client := gohttpc.NewClient(tc.Options...)
+			defer goutils.CatchWarnErrorFunc(client.Close)
+
+			req := client.R(http.MethodGet, server.URL)
+			req.Header().Set("Authorization", bearerToken)
+			req.Header().Set("Cookie", cookie)
+			req.Header().Set("X-Tenant-Id", tenant)
+
+			resp, err := req.Execute(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			var spanValues strings.Builder
+
+			for _, span := range recorder.Ended()[endedSpans:] {
+				for _, attr := range span.Attributes() {
+					spanValues.WriteString(attr.Value.Emit())
+					spanValues.WriteString("\n")
+				}
+			}
+
+			for _, secret := range tc.Secrets {
+				if strings.Contains(logs.String(), secret) {
+					t.Errorf("expected %q to be redacted in logs, got: %s", secret, logs.String())
+				}
+
+				if strings.Contains(spanValues.String(), secret) {
+					t.Errorf("expected %q to be redacted in span attributes", secret)
+				}
+			}
+
+			for _, value := range tc.Visibles {
+				if !strings.Contains(logs.String(), value) {
+					t.Errorf("expected %q in logs, got: %s", value, logs.String())
+				}
+			}
+
+			if !strings.Contains(logs.String(), otelutils.MaskString) {
+				t.Errorf("expected redacted values in logs, got: %s", logs.String())
+			}
+		})
+	}
+}
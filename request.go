@@ -16,16 +16,23 @@ package gohttpc
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log/slog"
 	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/failsafe-go/failsafe-go/retrypolicy"
 	"github.com/google/uuid"
 	"github.com/hasura/gotel/otelutils"
 	"github.com/relychan/gohttpc/authc/authscheme"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -72,6 +79,8 @@ type Request struct {
 
 	// Timeout is the maximum timeout for the request.
 	timeout time.Duration
+	// The maximum duration to obtain a connection of each attempt, including DNS lookup, dial and TLS handshake.
+	connectTimeout time.Duration
 
 	// RetryPolicy is the retry policy for the request.
 	retry         retrypolicy.RetryPolicy[*http.Response]
@@ -79,6 +88,55 @@ type Request struct {
 	header        http.Header
 	retryAttempts int
 	options       *RequestOptions
+	// The algorithm to compute the checksum header of the request body.
+	checksumAlgorithm string
+	// The idempotency key which is generated once per execution and sent on every attempt.
+	idempotencyKey string
+	// streaming is true if the body is streamed from a channel and can't be replayed.
+	streaming bool
+	// consumed is true if the request body was read by a previous execution.
+	consumed bool
+	// Vendor-specific entries which are added to the W3C tracestate header.
+	traceState trace.TraceState
+	// The TLS server name (SNI) which overrides the server name of the transport for this request.
+	serverName string
+	// authDisabled is true if the request is sent without authentication.
+	authDisabled bool
+	// streamResponse is true if the response body is streamed to the caller and mustn't be captured for logs.
+	streamResponse bool
+	// uploadProgress reports the progress of the request body upload.
+	uploadProgress ProgressFunc
+	// Values of {name} placeholders in the path of the request URL.
+	pathParams map[string]string
+	// The request URL after the path parameters are substituted.
+	// It is resolved once per execution.
+	requestURL string
+	// The relative cost of the request which weights the in-flight accounting of load-aware balancers.
+	cost int
+	// The hint which selects the load-balancing strategy of composite balancers.
+	balancerHint string
+	// The proxy which overrides the proxy of the transport for this request.
+	proxyURL *url.URL
+	// The validation error of the proxy URL which is returned when the request is executed.
+	proxyErr error
+	// The JSON schema which overrides the response schema of the client for this request.
+	responseSchema *ResponseSchema
+	// Caller-defined attributes which are added to the span of the request.
+	spanAttributes []attribute.KeyValue
+	// The attributes returned by the custom attributes function of the client.
+	// They are evaluated once per execution.
+	customAttributes []attribute.KeyValue
+	// The tag which groups in-flight requests to be canceled together by [Client.CancelTag].
+	cancelTag string
+	// Caller-defined fields which are added to all logs of the request.
+	logFields []any
+	// clientTraceEnabled overrides the client trace option of the client for this request if set.
+	clientTraceEnabled *bool
+	// tracePropagationDisabled is true if the trace context isn't injected into the request headers.
+	tracePropagationDisabled bool
+	// captureBody is true if the bodies are captured in debug logs and span attributes.
+	// It is sampled once per execution.
+	captureBody bool
 }
 
 // NewRequest creates a raw request without client options.
@@ -110,6 +168,7 @@ func (r *Request) Header() http.Header {
 }
 
 // Clone creates a new request. The body can be nil if it was already read.
+// The clone of an executed request keeps the consumed state, so a new body must be set before executing it.
 func (r *Request) Clone() *Request {
 	newRequest := *r
 
@@ -117,6 +176,13 @@ func (r *Request) Clone() *Request {
 		newRequest.header = maps.Clone(r.header)
 	}
 
+	if newRequest.pathParams != nil {
+		newRequest.pathParams = maps.Clone(r.pathParams)
+	}
+
+	newRequest.spanAttributes = slices.Clone(r.spanAttributes)
+	newRequest.logFields = slices.Clone(r.logFields)
+
 	return &newRequest
 }
 
@@ -130,6 +196,174 @@ func (r *Request) SetURL(value string) {
 	r.url = value
 }
 
+// Proxy returns the proxy URL which overrides the proxy of the transport for this request.
+func (r *Request) Proxy() *url.URL {
+	return r.proxyURL
+}
+
+// SetProxy routes this request through the proxy, independent of the proxy of the client.
+// Supported schemes are http, https, socks5 and socks5h. An empty URL removes the override.
+// The override applies to transports which are built by the library only.
+// The URL is validated eagerly and the validation error, which wraps [ErrInvalidProxyURL], is returned by Execute.
+// Returns the request itself for chaining.
+func (r *Request) SetProxy(proxyURL string) *Request {
+	r.proxyURL = nil
+	r.proxyErr = nil
+
+	if proxyURL == "" {
+		return r
+	}
+
+	u, err := parseProxyURL(proxyURL)
+	if err != nil {
+		r.proxyErr = fmt.Errorf("%w: %w", ErrInvalidProxyURL, err)
+
+		return r
+	}
+
+	r.proxyURL = u
+
+	return r
+}
+
+// Cost returns the relative cost of the request. Defaults to 1.
+func (r *Request) Cost() int {
+	return max(r.cost, 1)
+}
+
+// SetCost sets the relative cost of the request, such as a heavy query.
+// Load-aware balancers count the request as cost in-flight requests on the selected host,
+// so high-cost requests influence subsequent host selection more. Values less than 1 reset the cost to 1.
+func (r *Request) SetCost(cost int) {
+	r.cost = max(cost, 1)
+}
+
+type requestCostContextKey struct{}
+
+// WithRequestCost returns a copy of the context with the relative cost of the request.
+func WithRequestCost(ctx context.Context, cost int) context.Context {
+	return context.WithValue(ctx, requestCostContextKey{}, cost)
+}
+
+// GetRequestCost gets the relative cost of the request from the context. Defaults to 1.
+func GetRequestCost(ctx context.Context) int {
+	cost, ok := ctx.Value(requestCostContextKey{}).(int)
+	if !ok {
+		return 1
+	}
+
+	return max(cost, 1)
+}
+
+// BalancerHint returns the load-balancing strategy hint of the request.
+func (r *Request) BalancerHint() string {
+	return r.balancerHint
+}
+
+// SetBalancerHint sets the hint which composite load balancers use to select
+// the load-balancing strategy for the request, e.g. affinity for reads and round-robin for writes.
+func (r *Request) SetBalancerHint(hint string) {
+	r.balancerHint = hint
+}
+
+type balancerHintContextKey struct{}
+
+// WithBalancerHint returns a copy of the context with the load-balancing strategy hint of the request.
+func WithBalancerHint(ctx context.Context, hint string) context.Context {
+	return context.WithValue(ctx, balancerHintContextKey{}, hint)
+}
+
+// GetBalancerHint gets the load-balancing strategy hint of the request from the context.
+func GetBalancerHint(ctx context.Context) (string, bool) {
+	hint, ok := ctx.Value(balancerHintContextKey{}).(string)
+
+	return hint, ok
+}
+
+// PathParams returns the values of path parameters.
+func (r *Request) PathParams() map[string]string {
+	return r.pathParams
+}
+
+// SetPathParam sets the value of the {name} placeholder in the path of the request URL.
+// The value is escaped when the placeholder is substituted during execution.
+// Returns the request itself for chaining.
+func (r *Request) SetPathParam(name string, value string) *Request {
+	if r.pathParams == nil {
+		r.pathParams = make(map[string]string)
+	}
+
+	r.pathParams[name] = value
+
+	return r
+}
+
+// SetPathParams sets values of {name} placeholders in the path of the request URL.
+// Existing parameters with the same names are overridden. Returns the request itself for chaining.
+func (r *Request) SetPathParams(params map[string]string) *Request {
+	if r.pathParams == nil {
+		r.pathParams = make(map[string]string, len(params))
+	}
+
+	maps.Copy(r.pathParams, params)
+
+	return r
+}
+
+// resolveURL substitutes {name} placeholders in the path of the request URL with escaped path parameters.
+// The query string and fragment are kept as is.
+// The URL is kept as is if no path parameter is set, so literal braces in the path don't fail the request.
+func (r *Request) resolveURL() (string, error) {
+	if len(r.pathParams) == 0 {
+		return r.url, nil
+	}
+
+	pathEnd := strings.IndexAny(r.url, "?#")
+	if pathEnd < 0 {
+		pathEnd = len(r.url)
+	}
+
+	rawPath := r.url[:pathEnd]
+
+	if !strings.Contains(rawPath, "{") {
+		return r.url, nil
+	}
+
+	var sb strings.Builder
+
+	sb.Grow(len(r.url))
+
+	for {
+		start := strings.IndexByte(rawPath, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(rawPath[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+		name := rawPath[start+1 : end]
+
+		value, ok := r.pathParams[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrPathParamMissing, name)
+		}
+
+		sb.WriteString(rawPath[:start])
+		sb.WriteString(url.PathEscape(value))
+
+		rawPath = rawPath[end+1:]
+	}
+
+	sb.WriteString(rawPath)
+	sb.WriteString(r.url[pathEnd:])
+
+	return sb.String(), nil
+}
+
 // Method returns the request method.
 func (r *Request) Method() string {
 	return r.method
@@ -146,18 +380,127 @@ func (r *Request) Timeout() time.Duration {
 }
 
 // SetTimeout sets the request timeout.
+// The request timeout overrides the timeout of client options.
+// The deadline of the execution context still applies if it is tighter.
 func (r *Request) SetTimeout(timeout time.Duration) {
 	r.timeout = timeout
 }
 
+// SpanAttributes returns caller-defined attributes which are added to the span of the request.
+func (r *Request) SpanAttributes() []attribute.KeyValue {
+	return r.spanAttributes
+}
+
+// SetSpanAttributes adds caller-defined attributes to the span of the request, such as a tenant or business ID.
+// Unlike [CustomAttributesFunc], the attributes aren't added to metrics to avoid high cardinality.
+// Returns the request itself for chaining.
+func (r *Request) SetSpanAttributes(attrs ...attribute.KeyValue) *Request {
+	r.spanAttributes = append(r.spanAttributes, attrs...)
+
+	return r
+}
+
+// LogFields returns caller-defined fields which are added to all logs of the request.
+func (r *Request) LogFields() []any {
+	return r.logFields
+}
+
+// WithLogFields adds structured fields to all logs emitted during the execution of the request,
+// such as a tenant or user ID. The arguments are the same as [slog.Logger.With].
+func (r *Request) WithLogFields(args ...any) *Request {
+	r.logFields = append(r.logFields, args...)
+
+	return r
+}
+
+// CancelTag returns the cancel tag of the request.
+func (r *Request) CancelTag() string {
+	return r.cancelTag
+}
+
+// SetCancelTag sets the tag of the request. All in-flight requests with the same tag
+// can be canceled at once by [Client.CancelTag], e.g. when the user navigates away.
+func (r *Request) SetCancelTag(tag string) {
+	r.cancelTag = tag
+}
+
+// ResponseSchema returns the JSON schema to validate the response body of the request.
+func (r *Request) ResponseSchema() *ResponseSchema {
+	if r.responseSchema != nil {
+		return r.responseSchema
+	}
+
+	return r.options.ResponseSchema
+}
+
+// SetResponseSchema compiles the JSON schema to validate the JSON body of the successful response.
+// The request fails with [ErrResponseSchemaValidation] if the response doesn't conform to the schema,
+// while the response body is still available to read. An empty schema removes the override.
+func (r *Request) SetResponseSchema(schema []byte) error {
+	if len(schema) == 0 {
+		r.responseSchema = nil
+
+		return nil
+	}
+
+	compiled, err := NewResponseSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	r.responseSchema = compiled
+
+	return nil
+}
+
+// ConnectTimeout returns the connect timeout of the request.
+func (r *Request) ConnectTimeout() time.Duration {
+	return r.connectTimeout
+}
+
+// SetConnectTimeout sets the maximum duration to obtain a connection for each attempt,
+// including DNS lookup, dial and TLS handshake, independently of the overall timeout.
+// The request fails with [ErrConnectTimeout] if the connection isn't ready in time.
+// Reused connections are obtained immediately. Zero means no connect timeout.
+func (r *Request) SetConnectTimeout(timeout time.Duration) {
+	r.connectTimeout = max(timeout, 0)
+}
+
+// ServerName returns the TLS server name override of the request.
+func (r *Request) ServerName() string {
+	return r.serverName
+}
+
+// SetServerName overrides the TLS server name (SNI) for this request only, independent of the dial host.
+// The HTTP client must implement [ServerNameClient].
+func (r *Request) SetServerName(serverName string) {
+	r.serverName = serverName
+}
+
+// AddTraceState adds a vendor-specific entry to the W3C tracestate header of the request.
+// The entry takes precedence over the entry with the same key of the parent span context.
+// Setting a trace state entry implies the W3C trace context propagation.
+func (r *Request) AddTraceState(key string, value string) error {
+	traceState, err := r.traceState.Insert(key, value)
+	if err != nil {
+		return err
+	}
+
+	r.traceState = traceState
+
+	return nil
+}
+
 // Body returns the request body.
 func (r *Request) Body() io.Reader {
 	return r.body
 }
 
-// SetBody sets the request body.
+// SetBody sets the request body. The request can be executed again with the new body.
 func (r *Request) SetBody(body io.Reader) {
 	r.body = body
+	r.streaming = false
+	r.consumed = false
 }
 
 // Retry returns the retry policy.
@@ -165,6 +508,38 @@ func (r *Request) Retry() retrypolicy.RetryPolicy[*http.Response] {
 	return r.retry
 }
 
+// ClientTraceEnabled checks if the detailed client trace is enabled for the request.
+// The override of the request takes precedence over the option of the client.
+func (r *Request) ClientTraceEnabled() bool {
+	if r.clientTraceEnabled != nil {
+		return *r.clientTraceEnabled
+	}
+
+	return r.options.ClientTraceEnabled
+}
+
+// EnableClientTrace overrides the client trace option of the client for this request,
+// e.g. to trace DNS, connect and TLS timings of a single problematic endpoint.
+func (r *Request) EnableClientTrace(enabled bool) *Request {
+	r.clientTraceEnabled = &enabled
+
+	return r
+}
+
+// TracePropagationDisabled checks if the trace context isn't injected into the headers of the request.
+func (r *Request) TracePropagationDisabled() bool {
+	return r.tracePropagationDisabled || r.options.TracePropagationDisabled
+}
+
+// DisableTracePropagation disables injecting the trace context, e.g. traceparent and baggage headers,
+// into the request headers. It helps to call third-party APIs which reject unexpected headers.
+// The span of the request is still recorded.
+func (r *Request) DisableTracePropagation() *Request {
+	r.tracePropagationDisabled = true
+
+	return r
+}
+
 // SetRetry sets the retry policy.
 func (r *Request) SetRetry(retry retrypolicy.RetryPolicy[*http.Response]) {
 	r.retry = retry
@@ -175,26 +550,75 @@ func (r *Request) Authenticator() authscheme.HTTPClientAuthenticator {
 	return r.authenticator
 }
 
-// SetAuthenticator sets the HTTP authenticator.
+// SetAuthenticator sets the HTTP authenticator which takes precedence over the authenticator of the client.
+// Setting a non-nil authenticator re-enables the authentication if it was disabled.
 func (r *Request) SetAuthenticator(authenticator authscheme.HTTPClientAuthenticator) {
 	r.authenticator = authenticator
+
+	if authenticator != nil {
+		r.authDisabled = false
+	}
+}
+
+// AuthDisabled checks if the authentication is disabled for this request.
+func (r *Request) AuthDisabled() bool {
+	return r.authDisabled
 }
 
-func (r *Request) applyAuth(req *http.Request) error {
-	authenticator := r.authenticator
+// DisableAuth sends the request without authentication,
+// even if the request or the client has an authenticator.
+func (r *Request) DisableAuth() {
+	r.authDisabled = true
+}
+
+type authDisabledContextKey struct{}
+
+// WithAuthDisabled returns a copy of the context which disables the authentication of the request,
+// so HTTP clients skip their own authenticators when creating the request.
+func WithAuthDisabled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authDisabledContextKey{}, true)
+}
+
+// IsAuthDisabled checks if the authentication of the request is disabled in the context.
+func IsAuthDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(authDisabledContextKey{}).(bool)
 
-	if authenticator == nil {
-		authenticator = r.options.Authenticator
+	return disabled
+}
+
+func (r *Request) applyAuth(client HTTPClient, req *http.Request) error {
+	if r.authDisabled {
+		return nil
 	}
 
-	if authenticator == nil {
+	authenticator, appliedByClient := r.resolveAuthenticator(client)
+	if authenticator == nil || appliedByClient {
 		return nil
 	}
 
 	return authenticator.Authenticate(req)
 }
 
+// resolveAuthenticator returns the authenticator of the request.
+// The second value is true if the request was authenticated by the HTTP client when it was created.
+func (r *Request) resolveAuthenticator(client HTTPClient) (authscheme.HTTPClientAuthenticator, bool) {
+	if r.authenticator != nil {
+		return r.authenticator, false
+	}
+
+	if ac, ok := client.(AuthenticatorClient); ok && ac.Authenticator() != nil {
+		return ac.Authenticator(), true
+	}
+
+	return r.options.Authenticator, false
+}
+
 func (r *Request) getRetryPolicy() retrypolicy.RetryPolicy[*http.Response] {
+	// The streaming body can't be replayed.
+	if r.streaming {
+		return nil
+	}
+
 	if r.retry != nil {
 		return r.retry
 	}
@@ -202,12 +626,92 @@ func (r *Request) getRetryPolicy() retrypolicy.RetryPolicy[*http.Response] {
 	return r.options.Retry
 }
 
-func (r *Request) getTimeout() time.Duration {
-	if r.timeout > 0 {
-		return r.timeout
+// effectiveTimeout resolves the timeout of the request execution.
+// The request timeout takes precedence over the timeout of client options.
+// If both are unset, the request has no timeout of its own and only the deadline of the context applies.
+// Otherwise, the result is bounded by the deadline of the context so the tightest deadline wins.
+// Returns 0 if there is no timeout or a non-positive duration if the context deadline has passed.
+func (r *Request) effectiveTimeout(ctx context.Context) time.Duration {
+	timeout := r.timeout
+
+	if timeout <= 0 && r.options != nil {
+		timeout = r.options.Timeout
+	}
+
+	if timeout <= 0 {
+		return 0
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		// The deadline has passed. Return a negative value to avoid being confused with the unset timeout.
+		return -1
+	}
+
+	if remaining < timeout {
+		return remaining
+	}
+
+	return timeout
+}
+
+// doWithServerName sends the request with the TLS server name override.
+func (r *Request) doWithServerName(client HTTPClient, req *http.Request) (*http.Response, error) {
+	snc, ok := client.(ServerNameClient)
+	if !ok {
+		return nil, ErrServerNameOverrideUnsupported
+	}
+
+	return snc.DoWithServerName(req, r.serverName)
+}
+
+// injectTraceContext injects the trace context of the request into the header.
+func (r *Request) injectTraceContext(ctx context.Context, header http.Header) {
+	if r.TracePropagationDisabled() {
+		return
+	}
+
+	propagator := otel.GetTextMapPropagator()
+
+	if !r.options.TraceContextPropagation && r.traceState.Len() == 0 {
+		propagator.Inject(ctx, propagation.HeaderCarrier(header))
+
+		return
 	}
 
-	return r.options.Timeout
+	if r.traceState.Len() > 0 {
+		ctx = r.withTraceState(ctx)
+	}
+
+	propagation.NewCompositeTextMapPropagator(propagator, propagation.TraceContext{}).
+		Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// withTraceState merges custom trace state entries into the span context.
+func (r *Request) withTraceState(ctx context.Context) context.Context {
+	spanContext := trace.SpanContextFromContext(ctx)
+	traceState := spanContext.TraceState()
+
+	entries := make([][2]string, 0, r.traceState.Len())
+
+	r.traceState.Walk(func(key, value string) bool {
+		entries = append(entries, [2]string{key, value})
+
+		return true
+	})
+
+	// Insert entries in reverse order to keep the most recently added entry at the leftmost position.
+	for i := len(entries) - 1; i >= 0; i-- {
+		// Entries were validated when they were added.
+		traceState, _ = traceState.Insert(entries[i][0], entries[i][1])
+	}
+
+	return trace.ContextWithSpanContext(ctx, spanContext.WithTraceState(traceState))
 }
 
 func (r *Request) getLogger(ctx context.Context) *slog.Logger {
@@ -216,7 +720,7 @@ func (r *Request) getLogger(ctx context.Context) *slog.Logger {
 	value := ctx.Value(otelutils.LoggerContextKey)
 	if value != nil {
 		if logger, ok := value.(*slog.Logger); ok {
-			return logger.With(typeAttr)
+			return logger.With(typeAttr).With(r.logFields...)
 		}
 	}
 
@@ -229,7 +733,19 @@ func (r *Request) getLogger(ctx context.Context) *slog.Logger {
 		requestID = uuid.NewString()
 	}
 
-	return slog.Default().With(typeAttr, slog.String("request_id", requestID))
+	return slog.Default().With(typeAttr, slog.String("request_id", requestID)).With(r.logFields...)
+}
+
+// newIdempotencyKey generates a new idempotency key if the option is enabled for POST and PATCH requests.
+// Returns an empty string if the key is disabled or was already set by the caller.
+func (r *Request) newIdempotencyKey() string {
+	if r.options == nil || r.options.IdempotencyKeyHeader == "" ||
+		(r.method != http.MethodPost && r.method != http.MethodPatch) ||
+		r.header.Get(r.options.IdempotencyKeyHeader) != "" {
+		return ""
+	}
+
+	return uuid.NewString()
 }
 
 // RequestWithClient embeds the [Request] with an [HTTPClient] to make the Execute method shorter.
@@ -260,7 +776,37 @@ func (r *RequestWithClient) Clone() *RequestWithClient {
 	return newRequest
 }
 
+// WithLogFields adds structured fields to all logs emitted during the execution of the request.
+// See [Request.WithLogFields] for more details.
+func (rwc *RequestWithClient) WithLogFields(args ...any) *RequestWithClient {
+	rwc.Request.WithLogFields(args...)
+
+	return rwc
+}
+
+// EnableClientTrace overrides the client trace option of the client for this request.
+// See [Request.EnableClientTrace] for more details.
+func (rwc *RequestWithClient) EnableClientTrace(enabled bool) *RequestWithClient {
+	rwc.Request.EnableClientTrace(enabled)
+
+	return rwc
+}
+
+// DisableTracePropagation disables injecting the trace context into the request headers.
+// See [Request.DisableTracePropagation] for more details.
+func (rwc *RequestWithClient) DisableTracePropagation() *RequestWithClient {
+	rwc.Request.DisableTracePropagation()
+
+	return rwc
+}
+
 // Execute handles the HTTP request to the remote server.
 func (rwc *RequestWithClient) Execute(ctx context.Context) (*http.Response, error) {
 	return rwc.Request.Execute(ctx, rwc.client)
 }
+
+// Send handles the HTTP request to the remote server and wraps the response with helper methods.
+// See [Request.Send] for more details.
+func (rwc *RequestWithClient) Send(ctx context.Context) (*Response, error) {
+	return rwc.Request.Send(ctx, rwc.client)
+}
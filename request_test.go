@@ -0,0 +1,561 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc/authc/authscheme"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequest_EffectiveTimeout(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requestTimeout time.Duration
+		optionTimeout  time.Duration
+		ctxTimeout     time.Duration
+		expected       time.Duration
+	}{
+		{
+			name:     "no_timeout",
+			expected: 0,
+		},
+		{
+			name:          "client_option",
+			optionTimeout: 10 * time.Second,
+			expected:      10 * time.Second,
+		},
+		{
+			name:           "request",
+			requestTimeout: 5 * time.Second,
+			expected:       5 * time.Second,
+		},
+		{
+			name:           "request_over_client_option",
+			requestTimeout: 20 * time.Second,
+			optionTimeout:  10 * time.Second,
+			expected:       20 * time.Second,
+		},
+		{
+			name:       "context_deadline_only",
+			ctxTimeout: time.Minute,
+			expected:   0,
+		},
+		{
+			name:           "context_deadline_tighter_than_request",
+			requestTimeout: time.Hour,
+			optionTimeout:  10 * time.Second,
+			ctxTimeout:     time.Minute,
+			expected:       time.Minute,
+		},
+		{
+			name:          "client_option_tighter_than_context_deadline",
+			optionTimeout: 10 * time.Second,
+			ctxTimeout:    time.Hour,
+			expected:      10 * time.Second,
+		},
+		{
+			name:           "request_tighter_than_context_deadline",
+			requestTimeout: 5 * time.Second,
+			optionTimeout:  time.Minute,
+			ctxTimeout:     time.Hour,
+			expected:       5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			if tc.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
+				defer cancel()
+			}
+
+			req := NewRequest(http.MethodGet, "/", &RequestOptions{Timeout: tc.optionTimeout})
+			req.SetTimeout(tc.requestTimeout)
+
+			result := req.effectiveTimeout(ctx)
+
+			// the remaining time of the context deadline decreases slightly.
+			if result > tc.expected || result < tc.expected-time.Second {
+				t.Errorf("expected effective timeout %s, got: %s", tc.expected, result)
+			}
+		})
+	}
+
+	t.Run("expired_context_deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		req := NewRequest(http.MethodGet, "/", &RequestOptions{Timeout: time.Second})
+
+		result := req.effectiveTimeout(ctx)
+		if result >= 0 {
+			t.Errorf("expected negative effective timeout, got: %s", result)
+		}
+	})
+}
+
+func TestRequest_TightestDeadlineWins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		requestTimeout time.Duration
+		optionTimeout  time.Duration
+		ctxTimeout     time.Duration
+	}{
+		{
+			name:           "request",
+			requestTimeout: 50 * time.Millisecond,
+			optionTimeout:  time.Minute,
+			ctxTimeout:     time.Minute,
+		},
+		{
+			name:          "client_option",
+			optionTimeout: 50 * time.Millisecond,
+			ctxTimeout:    time.Minute,
+		},
+		{
+			name:           "context_deadline",
+			requestTimeout: time.Minute,
+			optionTimeout:  time.Minute,
+			ctxTimeout:     50 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(WithTimeout(tc.optionTimeout))
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(t.Context(), tc.ctxTimeout)
+			defer cancel()
+
+			req := client.R(http.MethodGet, server.URL)
+			req.SetTimeout(tc.requestTimeout)
+
+			startTime := time.Now()
+
+			resp, err := req.Execute(ctx)
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected deadline exceeded error, got: %v", err)
+			}
+
+			if elapsed := time.Since(startTime); elapsed > time.Second {
+				t.Errorf("expected the tightest deadline to abort the request, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestRequest_TraceContextPropagation(t *testing.T) {
+	headers := make(chan http.Header, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parentTraceState, _ := trace.ParseTraceState("parent=value")
+
+	ctx := trace.ContextWithSpanContext(t.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: parentTraceState,
+		Remote:     true,
+	}))
+
+	testCases := []struct {
+		name               string
+		options            []ClientOption
+		traceState         [][2]string
+		expectedTraceState string
+	}{
+		{
+			name:               "explicit_propagation",
+			options:            []ClientOption{WithTraceContextPropagation(true)},
+			expectedTraceState: "parent=value",
+		},
+		{
+			name:               "custom_trace_state",
+			traceState:         [][2]string{{"vendor", "abc"}, {"other", "xyz"}},
+			expectedTraceState: "other=xyz,vendor=abc,parent=value",
+		},
+		{
+			name:               "override_parent_trace_state",
+			options:            []ClientOption{WithTraceContextPropagation(true)},
+			traceState:         [][2]string{{"parent", "child"}},
+			expectedTraceState: "parent=child",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(tc.options...)
+			defer client.Close()
+
+			req := client.R(http.MethodGet, server.URL)
+
+			for _, entry := range tc.traceState {
+				err := req.AddTraceState(entry[0], entry[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resp, err := req.Execute(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			header := <-headers
+
+			traceParent := header.Get("Traceparent")
+			if !strings.HasPrefix(traceParent, "00-"+traceID.String()+"-") {
+				t.Errorf("expected traceparent of trace %s, got: %s", traceID, traceParent)
+			}
+
+			if traceState := header.Get("Tracestate"); traceState != tc.expectedTraceState {
+				t.Errorf("expected tracestate %s, got: %s", tc.expectedTraceState, traceState)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		send := func(t *testing.T, req *RequestWithClient) http.Header {
+			t.Helper()
+
+			resp, err := req.Execute(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			return <-headers
+		}
+
+		t.Run("client", func(t *testing.T) {
+			client := NewClient(WithTraceContextPropagation(true), WithTracePropagation(false))
+			defer client.Close()
+
+			header := send(t, client.R(http.MethodGet, server.URL))
+			if traceParent := header.Get("Traceparent"); traceParent != "" {
+				t.Errorf("expected no traceparent, got: %s", traceParent)
+			}
+		})
+
+		t.Run("request", func(t *testing.T) {
+			client := NewClient(WithTraceContextPropagation(true))
+			defer client.Close()
+
+			header := send(t, client.R(http.MethodGet, server.URL).DisableTracePropagation())
+			if traceParent := header.Get("Traceparent"); traceParent != "" {
+				t.Errorf("expected no traceparent, got: %s", traceParent)
+			}
+
+			header = send(t, client.R(http.MethodGet, server.URL))
+			if traceParent := header.Get("Traceparent"); traceParent == "" {
+				t.Error("expected traceparent of the request without the override")
+			}
+		})
+	})
+
+	t.Run("invalid_key", func(t *testing.T) {
+		req := NewRequest(http.MethodGet, server.URL, &RequestOptions{})
+
+		err := req.AddTraceState("Invalid Key", "value")
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestRequest_SetServerName(t *testing.T) {
+	serverNames := make(chan string, 4)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverNames <- hello.ServerName
+
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	httpClient := server.Client()
+	sharedTransport := httpClient.Transport.(*http.Transport)
+
+	client := NewClient(WithHTTPClient(httpClient))
+	defer client.Close()
+
+	// The certificate of the test server is valid for example.com.
+	req := client.R(http.MethodGet, server.URL)
+	req.SetServerName("example.com")
+
+	resp, err := req.Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = resp.Body.Close()
+
+	if serverName := <-serverNames; serverName != "example.com" {
+		t.Errorf("expected SNI example.com, got: %q", serverName)
+	}
+
+	if sharedTransport.TLSClientConfig.ServerName != "" {
+		t.Errorf("expected the shared transport not mutated, got: %s", sharedTransport.TLSClientConfig.ServerName)
+	}
+
+	// The next request without override uses the default server name which is empty for IP addresses.
+	resp, err = client.R(http.MethodGet, server.URL).Execute(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = resp.Body.Close()
+
+	if serverName := <-serverNames; serverName != "" {
+		t.Errorf("expected empty SNI, got: %q", serverName)
+	}
+
+	t.Run("unsupported_client", func(t *testing.T) {
+		req := NewRequest(http.MethodGet, server.URL, &RequestOptions{})
+		req.SetServerName("example.com")
+
+		_, err := req.Execute(t.Context(), &mockHTTPClientGetter{})
+		if !errors.Is(err, ErrServerNameOverrideUnsupported) {
+			t.Errorf("expected ErrServerNameOverrideUnsupported, got: %v", err)
+		}
+	})
+}
+
+func TestRequest_DisableAuth(t *testing.T) {
+	authorizations := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations <- r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithAuthenticator(mockAuthenticator{token: "client"}))
+	defer client.Close()
+
+	testCases := []struct {
+		name     string
+		setup    func(req *RequestWithClient)
+		expected string
+	}{
+		{
+			name:     "client_authenticator",
+			setup:    func(req *RequestWithClient) {},
+			expected: "Bearer client",
+		},
+		{
+			name: "request_authenticator",
+			setup: func(req *RequestWithClient) {
+				req.SetAuthenticator(mockAuthenticator{token: "request"})
+			},
+			expected: "Bearer request",
+		},
+		{
+			name: "disable_auth",
+			setup: func(req *RequestWithClient) {
+				req.SetAuthenticator(mockAuthenticator{token: "request"})
+				req.DisableAuth()
+			},
+			expected: "",
+		},
+		{
+			name: "reenable_auth",
+			setup: func(req *RequestWithClient) {
+				req.DisableAuth()
+				req.SetAuthenticator(mockAuthenticator{token: "request"})
+			},
+			expected: "Bearer request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := client.R(http.MethodGet, server.URL)
+			tc.setup(req)
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			if authorization := <-authorizations; authorization != tc.expected {
+				t.Errorf("expected Authorization %q, got: %q", tc.expected, authorization)
+			}
+		})
+	}
+}
+
+func TestRequest_PathParams(t *testing.T) {
+	paths := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.EscapedPath() + "?" + r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	defer client.Close()
+
+	testCases := []struct {
+		name     string
+		path     string
+		setup    func(req *RequestWithClient)
+		expected string
+		err      string
+	}{
+		{
+			name: "single",
+			path: "/todos/{id}",
+			setup: func(req *RequestWithClient) {
+				req.SetPathParam("id", "1")
+			},
+			expected: "/todos/1?",
+		},
+		{
+			name: "multiple",
+			path: "/users/{user}/todos/{id}?filter={done}",
+			setup: func(req *RequestWithClient) {
+				req.SetPathParam("id", "0").SetPathParams(map[string]string{
+					"user": "foo bar/baz",
+					"id":   "2",
+				})
+			},
+			expected: "/users/foo%20bar%2Fbaz/todos/2?filter={done}",
+		},
+		{
+			name:     "literal_braces_without_params",
+			path:     "/files/{literal}",
+			setup:    func(req *RequestWithClient) {},
+			expected: "/files/%7Bliteral%7D?",
+		},
+		{
+			name: "missing",
+			path: "/users/{user}/todos/{id}",
+			setup: func(req *RequestWithClient) {
+				req.SetPathParam("user", "foo")
+			},
+			err: "missing path parameter: id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := client.R(http.MethodGet, server.URL+tc.path)
+			tc.setup(req)
+
+			resp, err := req.Execute(t.Context())
+			if tc.err != "" {
+				if !errors.Is(err, ErrPathParamMissing) || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got: %v", tc.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			if path := <-paths; path != tc.expected {
+				t.Errorf("expected path %q, got: %q", tc.expected, path)
+			}
+
+			if req.URL() != server.URL+tc.path {
+				t.Errorf("expected the URL template to be unchanged, got: %s", req.URL())
+			}
+		})
+	}
+}
+
+type mockAuthenticator struct {
+	token string
+}
+
+func (ma mockAuthenticator) Authenticate(req *http.Request, _ ...authscheme.AuthenticateOption) error {
+	req.Header.Set("Authorization", "Bearer "+ma.token)
+
+	return nil
+}
+
+func (mockAuthenticator) Close() error {
+	return nil
+}
+
+type mockHTTPClientGetter struct{}
+
+func (mockHTTPClientGetter) HTTPClient() (HTTPClient, error) {
+	return mockHTTPClient{}, nil
+}
+
+type mockHTTPClient struct{}
+
+func (mockHTTPClient) NewRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, method, url, body)
+}
+
+func (mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
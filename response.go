@@ -15,9 +15,126 @@
 package gohttpc
 
 import (
+	"context"
+	"encoding/json"
 	"io"
+	"net/http"
+
+	"github.com/relychan/goutils"
 )
 
+// Response wraps the raw HTTP response with helper methods.
+type Response struct {
+	// RawResponse is the underlying HTTP response.
+	RawResponse *http.Response
+
+	// The body is cached after the first read so that the helpers can be called many times.
+	body     []byte
+	bodyErr  error
+	bodyRead bool
+}
+
+// NewResponse creates a [Response] wrapper from the raw HTTP response.
+func NewResponse(rawResponse *http.Response) *Response {
+	return &Response{
+		RawResponse: rawResponse,
+	}
+}
+
+// Send handles the HTTP request to the remote server like [Request.Execute]
+// and wraps the response with helper methods.
+// The response is nil if the request fails without response.
+func (r *Request) Send(ctx context.Context, client HTTPClientGetter) (*Response, error) {
+	resp, err := r.Execute(ctx, client)
+	if resp == nil {
+		return nil, err
+	}
+
+	return NewResponse(resp), err
+}
+
+// StatusCode returns the status code of the response. Returns zero if the raw response is nil.
+func (r *Response) StatusCode() int {
+	if r.RawResponse == nil {
+		return 0
+	}
+
+	return r.RawResponse.StatusCode
+}
+
+// Close drains and closes the response body. It is safe to call Close many times.
+func (r *Response) Close() {
+	goutils.CloseResponse(r.RawResponse)
+}
+
+// Bytes reads the (decompressed) response body and closes it.
+// The body is cached so the next calls return the same result.
+func (r *Response) Bytes() ([]byte, error) {
+	if r.bodyRead {
+		return r.body, r.bodyErr
+	}
+
+	r.bodyRead = true
+
+	if r.RawResponse == nil || r.RawResponse.Body == nil || r.RawResponse.Body == http.NoBody {
+		return nil, nil
+	}
+
+	r.body, r.bodyErr = io.ReadAll(r.RawResponse.Body)
+
+	r.Close()
+
+	return r.body, r.bodyErr
+}
+
+// ReadString reads the (decompressed) response body as a string and closes it.
+func (r *Response) ReadString() (string, error) {
+	body, err := r.Bytes()
+
+	return string(body), err
+}
+
+// JSON reads the (decompressed) response body, closes it and decodes the JSON body into the output value.
+func (r *Response) JSON(out any) error {
+	body, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if len(body) == 0 {
+		return ErrResponseBodyNoContent
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// RateLimit returns the rate limit quota parsed from RateLimit headers of the response.
+// Returns nil if the server doesn't advertise the quota.
+func (r *Response) RateLimit() *RateLimitInfo {
+	if r.RawResponse == nil {
+		return nil
+	}
+
+	return ParseRateLimitInfo(r.RawResponse.Header)
+}
+
+// Timing returns the timing breakdown of the request attempt which produced the response.
+// Returns nil if the client trace is disabled.
+func (r *Response) Timing() *Timing {
+	if r.RawResponse == nil || r.RawResponse.Request == nil {
+		return nil
+	}
+
+	ct := getClientTrace(r.RawResponse.Request.Context())
+	if ct == nil {
+		return nil
+	}
+
+	timing := ct.Timing()
+
+	return &timing
+}
+
 // responseBodyWithCancel wraps the original body of the HTTP response with cancel if timeout is configured.
 type responseBodyWithCancel struct {
 	io.ReadCloser
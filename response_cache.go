@@ -0,0 +1,295 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/relychan/goutils/httpheader"
+)
+
+// CacheEntry represents a cached response which is revalidated with conditional requests.
+type CacheEntry struct {
+	// StatusCode is the status code of the cached response.
+	StatusCode int
+	// Header is the header of the cached response, including the ETag and Last-Modified validators.
+	Header http.Header
+	// Body is the decompressed body of the cached response.
+	Body []byte
+}
+
+// Cache abstracts a storage of conditional response cache entries, e.g. in memory or Redis.
+type Cache interface {
+	// Get returns the cache entry of the key. Returns nil if the entry doesn't exist.
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+	// Set stores the cache entry of the key.
+	Set(ctx context.Context, key string, entry *CacheEntry) error
+}
+
+// MemoryCache is the in-memory implementation of the [Cache] interface.
+// Entries are kept until they are replaced, so the cache should be used for a bounded set of URLs.
+type MemoryCache struct {
+	entries sync.Map
+}
+
+var _ Cache = (*MemoryCache)(nil)
+
+// NewMemoryCache creates a new [MemoryCache] instance.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{}
+}
+
+// Get returns the cache entry of the key. Returns nil if the entry doesn't exist.
+func (mc *MemoryCache) Get(_ context.Context, key string) (*CacheEntry, error) {
+	entry, ok := mc.entries.Load(key)
+	if !ok {
+		return nil, nil
+	}
+
+	return entry.(*CacheEntry), nil //nolint:forcetypeassert
+}
+
+// Set stores the cache entry of the key.
+func (mc *MemoryCache) Set(_ context.Context, key string, entry *CacheEntry) error {
+	mc.entries.Store(key, entry)
+
+	return nil
+}
+
+// responseCacheKey returns the cache key of the request.
+// Returns an empty string if the response cache is disabled or the method isn't safe.
+func (r *Request) responseCacheKey() string {
+	if r.options.ResponseCache == nil ||
+		(r.method != http.MethodGet && r.method != http.MethodHead) {
+		return ""
+	}
+
+	if r.options.CacheKeyFunc != nil {
+		return r.options.CacheKeyFunc(r)
+	}
+
+	return r.method + " " + r.requestURL
+}
+
+// applyCacheValidators gets the cache entry of the request and sends its validators
+// with the If-None-Match and If-Modified-Since headers.
+// Returns nil if there is no cached entry, or the caller sets the conditional headers by themselves.
+func (r *Request) applyCacheValidators(
+	ctx context.Context,
+	logger *slog.Logger,
+	req *http.Request,
+	key string,
+) *CacheEntry {
+	if req.Header.Get(httpheader.IfNoneMatch) != "" || req.Header.Get(httpheader.IfModifiedSince) != "" {
+		return nil
+	}
+
+	entry, err := r.options.ResponseCache.Get(ctx, key)
+	if err != nil {
+		logger.Warn(
+			"failed to get the response cache entry: "+err.Error(),
+			slog.String("key", key),
+		)
+
+		return nil
+	}
+
+	if entry != nil && entry.Header.Get(httpheader.Vary) != "" {
+		// The latest response varies on request headers. Select the variant of the current request.
+		variantKey, ok := varyCacheKey(key, entry.Header, req.Header)
+		if !ok {
+			return nil
+		}
+
+		entry, err = r.options.ResponseCache.Get(ctx, variantKey)
+		if err != nil {
+			logger.Warn(
+				"failed to get the response cache entry: "+err.Error(),
+				slog.String("key", variantKey),
+			)
+
+			return nil
+		}
+	}
+
+	if entry == nil {
+		return nil
+	}
+
+	etag := entry.Header.Get(httpheader.ETag)
+	lastModified := entry.Header.Get(httpheader.LastModified)
+
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	if etag != "" {
+		req.Header.Set(httpheader.IfNoneMatch, etag)
+	}
+
+	if lastModified != "" {
+		req.Header.Set(httpheader.IfModifiedSince, lastModified)
+	}
+
+	return entry
+}
+
+// newCachedResponse creates a fresh response from the cache entry to replace the not modified response.
+func newCachedResponse(notModifiedResp *http.Response, entry *CacheEntry) *http.Response {
+	resp := &http.Response{
+		Status:        strconv.Itoa(entry.StatusCode) + " " + http.StatusText(entry.StatusCode),
+		StatusCode:    entry.StatusCode,
+		Proto:         notModifiedResp.Proto,
+		ProtoMajor:    notModifiedResp.ProtoMajor,
+		ProtoMinor:    notModifiedResp.ProtoMinor,
+		Header:        entry.Header.Clone(),
+		Body:          http.NoBody,
+		ContentLength: int64(len(entry.Body)),
+		Request:       notModifiedResp.Request,
+		TLS:           notModifiedResp.TLS,
+	}
+
+	if len(entry.Body) > 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Body))
+	}
+
+	return resp
+}
+
+// storeResponseCache stores the response to the cache if it has validators.
+// The body is stored when the caller reads it to the end, so the response is still streamed.
+// Responses with the Vary header are also stored by the values of the listed request headers,
+// while the entry of the key tells the varying headers of the latest response.
+func (r *Request) storeResponseCache(
+	ctx context.Context,
+	logger *slog.Logger,
+	resp *http.Response,
+	reqHeader http.Header,
+	key string,
+) {
+	if resp.StatusCode != http.StatusOK ||
+		(resp.Header.Get(httpheader.ETag) == "" && resp.Header.Get(httpheader.LastModified) == "") {
+		return
+	}
+
+	variantKey, ok := varyCacheKey(key, resp.Header, reqHeader)
+	if !ok {
+		return
+	}
+
+	keys := []string{key}
+
+	if variantKey != key {
+		keys = append(keys, variantKey)
+	}
+
+	store := func(body []byte) {
+		entry := &CacheEntry{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       body,
+		}
+
+		for _, key := range keys {
+			err := r.options.ResponseCache.Set(ctx, key, entry)
+			if err != nil {
+				logger.Warn(
+					"failed to set the response cache entry: "+err.Error(),
+					slog.String("key", key),
+				)
+			}
+		}
+	}
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		store(nil)
+
+		return
+	}
+
+	resp.Body = &cachingBody{
+		ReadCloser: resp.Body,
+		store:      store,
+	}
+}
+
+// varyCacheKey returns the cache key of the response variant which is selected by the values of
+// the request headers listed in the Vary header. Returns the key as is if the response doesn't vary.
+// Returns false if the response varies on all request headers, so it can't be cached.
+func varyCacheKey(key string, respHeader http.Header, reqHeader http.Header) (string, bool) {
+	var names []string
+
+	for _, value := range respHeader.Values(httpheader.Vary) {
+		for name := range strings.SplitSeq(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return "", false
+			}
+
+			if name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return key, true
+	}
+
+	slices.Sort(names)
+
+	var sb strings.Builder
+
+	sb.WriteString(key)
+
+	for _, name := range slices.Compact(names) {
+		sb.WriteString("\n")
+		sb.WriteString(name)
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(reqHeader.Values(name), ", "))
+	}
+
+	return sb.String(), true
+}
+
+// cachingBody wraps the response body to store the body to the cache when it is read to the end.
+type cachingBody struct {
+	io.ReadCloser
+
+	buf    bytes.Buffer
+	store  func(body []byte)
+	stored bool
+}
+
+// Read reads the body and stores it to the cache at the end of the body.
+func (cb *cachingBody) Read(p []byte) (int, error) {
+	n, err := cb.ReadCloser.Read(p)
+	cb.buf.Write(p[:n])
+
+	if err == io.EOF && !cb.stored {
+		cb.stored = true
+		cb.store(cb.buf.Bytes())
+	}
+
+	return n, err
+}
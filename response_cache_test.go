@@ -0,0 +1,189 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestResponseCache(t *testing.T) {
+	const (
+		etag = `"v1"`
+		body = `{"version":1}`
+	)
+
+	var notModifiedCount, requestCount atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+
+		if r.Header.Get("If-None-Match") == etag {
+			notModifiedCount.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+
+		if r.Method != http.MethodHead {
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	execute := func(t *testing.T, client *gohttpc.Client, method string) (int, string) {
+		t.Helper()
+
+		resp, err := client.R(method, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer goutils.CloseResponse(resp)
+
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return resp.StatusCode, string(respBody)
+	}
+
+	t.Run("not_modified", func(t *testing.T) {
+		notModifiedCount.Store(0)
+
+		client := gohttpc.NewClient(gohttpc.WithResponseCache(gohttpc.NewMemoryCache()))
+		defer client.Close()
+
+		for i := range 3 {
+			status, respBody := execute(t, client, http.MethodGet)
+			if status != http.StatusOK {
+				t.Errorf("%d: expected status 200, got: %d", i, status)
+			}
+
+			if respBody != body {
+				t.Errorf("%d: expected body %s, got: %s", i, body, respBody)
+			}
+		}
+
+		if count := notModifiedCount.Load(); count != 2 {
+			t.Errorf("expected 2 not modified responses, got: %d", count)
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		notModifiedCount.Store(0)
+
+		client := gohttpc.NewClient(gohttpc.WithResponseCache(gohttpc.NewMemoryCache()))
+		defer client.Close()
+
+		for range 2 {
+			status, _ := execute(t, client, http.MethodHead)
+			if status != http.StatusOK {
+				t.Errorf("expected status 200, got: %d", status)
+			}
+		}
+
+		if count := notModifiedCount.Load(); count != 1 {
+			t.Errorf("expected 1 not modified response, got: %d", count)
+		}
+	})
+
+	t.Run("unsafe_methods_bypass", func(t *testing.T) {
+		notModifiedCount.Store(0)
+
+		cache := gohttpc.NewMemoryCache()
+
+		client := gohttpc.NewClient(gohttpc.WithResponseCache(cache))
+		defer client.Close()
+
+		for range 2 {
+			status, respBody := execute(t, client, http.MethodPost)
+			if status != http.StatusOK || respBody != body {
+				t.Errorf("expected the full response, got: %d %s", status, respBody)
+			}
+		}
+
+		if count := notModifiedCount.Load(); count != 0 {
+			t.Errorf("expected no conditional requests, got: %d", count)
+		}
+
+		entry, err := cache.Get(t.Context(), http.MethodPost+" "+server.URL)
+		if err != nil || entry != nil {
+			t.Errorf("expected no cache entry, got: %v, %v", entry, err)
+		}
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		var notModified atomic.Int32
+
+		varyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept")
+			varyETag := `"` + accept + `"`
+
+			w.Header().Set("Vary", "Accept")
+
+			if r.Header.Get("If-None-Match") == varyETag {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.Header().Set("ETag", varyETag)
+			_, _ = w.Write([]byte(accept))
+		}))
+		defer varyServer.Close()
+
+		client := gohttpc.NewClient(gohttpc.WithResponseCache(gohttpc.NewMemoryCache()))
+		defer client.Close()
+
+		for _, accept := range []string{"application/json", "application/xml", "application/json", "application/xml"} {
+			req := client.R(http.MethodGet, varyServer.URL)
+			req.Header().Set("Accept", accept)
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			respBody, err := io.ReadAll(resp.Body)
+			goutils.CloseResponse(resp)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(respBody) != accept {
+				t.Errorf("expected the body of %s, got: %s", accept, respBody)
+			}
+		}
+
+		if count := notModified.Load(); count != 2 {
+			t.Errorf("expected 2 not modified responses, got: %d", count)
+		}
+	})
+
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// BodyLeakFunc abstracts a function which is called with the stack trace of the request execution
+// when the response body is garbage collected without being closed.
+type BodyLeakFunc func(stack []byte)
+
+// leakDetectorBody wraps the response body to detect if it is garbage collected without being closed.
+type leakDetectorBody struct {
+	io.ReadCloser
+
+	state *leakDetectorState
+}
+
+// leakDetectorState is the state of the leak detector which outlives the body.
+// It must not reference the body so the body can be garbage collected.
+type leakDetectorState struct {
+	closed atomic.Bool
+	stack  []byte
+}
+
+// newLeakDetectorResponse returns a shallow copy of the response with the leak detector body.
+// The transport keeps the original response while the body is open,
+// so the copy is required to let the returned response be garbage collected.
+func newLeakDetectorResponse(resp *http.Response, onLeak BodyLeakFunc) *http.Response {
+	result := *resp
+	result.Body = newLeakDetectorBody(resp.Body, onLeak)
+
+	return &result
+}
+
+func newLeakDetectorBody(body io.ReadCloser, onLeak BodyLeakFunc) *leakDetectorBody {
+	state := &leakDetectorState{
+		stack: debug.Stack(),
+	}
+
+	ldb := &leakDetectorBody{
+		ReadCloser: body,
+		state:      state,
+	}
+
+	runtime.AddCleanup(ldb, func(state *leakDetectorState) {
+		if !state.closed.Load() {
+			onLeak(state.stack)
+		}
+	}, state)
+
+	return ldb
+}
+
+// Close closes the body and marks the body as closed.
+func (ldb *leakDetectorBody) Close() error {
+	ldb.state.closed.Store(true)
+
+	return ldb.ReadCloser.Close()
+}
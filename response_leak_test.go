@@ -0,0 +1,93 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestBodyLeakDetection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	leaks := make(chan []byte, 10)
+
+	client := gohttpc.NewClient(gohttpc.WithBodyLeakDetection(func(stack []byte) {
+		leaks <- stack
+	}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	// execute runs the request in a separate function so the response is unreachable after return.
+	execute := func(t *testing.T, closeBody bool) {
+		t.Helper()
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if closeBody {
+			goutils.CloseResponse(resp)
+		}
+	}
+
+	// waitLeak runs the garbage collector until a leak is reported or the timeout is exceeded.
+	waitLeak := func(timeout time.Duration) []byte {
+		deadline := time.Now().Add(timeout)
+
+		for time.Now().Before(deadline) {
+			runtime.GC()
+
+			select {
+			case stack := <-leaks:
+				return stack
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+
+		return nil
+	}
+
+	t.Run("closed", func(t *testing.T) {
+		execute(t, true)
+
+		if stack := waitLeak(200 * time.Millisecond); stack != nil {
+			t.Errorf("expected no leak for the closed body, got: %s", stack)
+		}
+	})
+
+	t.Run("unclosed", func(t *testing.T) {
+		execute(t, false)
+
+		stack := waitLeak(5 * time.Second)
+		if stack == nil {
+			t.Fatal("expected the leak of the unclosed body to be reported")
+		}
+
+		if !strings.Contains(string(stack), "TestBodyLeakDetection") {
+			t.Errorf("expected the stack trace of the request execution, got: %s", stack)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"io"
+)
+
+// limitedBody wraps the response body to fail the read with [ErrResponseBodyTooLarge]
+// once the body exceeds the maximum size.
+type limitedBody struct {
+	io.Closer
+
+	// The reader is limited to one byte more than the maximum size to detect the overflow.
+	reader io.LimitedReader
+}
+
+func newLimitedBody(body io.ReadCloser, maxSize int64) *limitedBody {
+	return &limitedBody{
+		Closer: body,
+		reader: io.LimitedReader{
+			R: body,
+			N: maxSize + 1,
+		},
+	}
+}
+
+// Read reads the body and returns [ErrResponseBodyTooLarge] if the maximum size is exceeded.
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	if lb.reader.N <= 0 {
+		return 0, ErrResponseBodyTooLarge
+	}
+
+	n, err := lb.reader.Read(p)
+	if lb.reader.N <= 0 {
+		// Drop the extra byte which is beyond the maximum size.
+		return n - 1, ErrResponseBodyTooLarge
+	}
+
+	return n, err
+}
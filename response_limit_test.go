@@ -0,0 +1,136 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestMaxResponseBodySize(t *testing.T) {
+	// The server writes the body of the given size, with or without the Content-Length header.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+
+		w.Header().Set("Content-Type", "text/plain")
+
+		chunked := r.URL.Query().Get("chunked") != ""
+		if !chunked {
+			w.Header().Set("Content-Length", strconv.Itoa(size))
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		if chunked {
+			// Flush the header so that the server can't infer the content length.
+			w.(http.Flusher).Flush()
+		}
+
+		_, _ = w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithMaxResponseBodySize(100))
+	defer client.Close()
+
+	testCases := []struct {
+		Name        string
+		Query       string
+		ExpectedErr error
+		ExpectedLen int
+	}{
+		{
+			Name:        "under_limit",
+			Query:       "size=50",
+			ExpectedLen: 50,
+		},
+		{
+			Name:        "at_limit",
+			Query:       "size=100&chunked=true",
+			ExpectedLen: 100,
+		},
+		{
+			Name:        "over_limit",
+			Query:       "size=101&chunked=true",
+			ExpectedErr: gohttpc.ErrResponseBodyTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := client.R(http.MethodGet, server.URL+"?"+tc.Query).Execute(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer goutils.CloseResponse(resp)
+
+			body, err := io.ReadAll(resp.Body)
+			if !errors.Is(err, tc.ExpectedErr) {
+				t.Fatalf("expected error %v, got: %v", tc.ExpectedErr, err)
+			}
+
+			if tc.ExpectedErr == nil && len(body) != tc.ExpectedLen {
+				t.Errorf("expected %d bytes, got: %d", tc.ExpectedLen, len(body))
+			}
+		})
+	}
+
+	t.Run("content_length_over_limit", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, server.URL+"?size=1000").Execute(context.Background())
+		if err == nil {
+			goutils.CloseResponse(resp)
+		}
+
+		if !errors.Is(err, gohttpc.ErrResponseBodyTooLarge) {
+			t.Fatalf("expected error %v, got: %v", gohttpc.ErrResponseBodyTooLarge, err)
+		}
+	})
+
+	t.Run("debug_body_capture", func(t *testing.T) {
+		var logs bytes.Buffer
+
+		logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := context.WithValue(t.Context(), otelutils.LoggerContextKey, logger)
+
+		req := client.R(http.MethodGet, server.URL+"?size=1000&chunked=true")
+		req.Header().Set("Content-Type", "text/plain")
+
+		resp, err := req.Execute(ctx)
+		if err == nil {
+			goutils.CloseResponse(resp)
+		}
+
+		if !errors.Is(err, gohttpc.ErrResponseBodyTooLarge) {
+			t.Fatalf("expected error %v, got: %v", gohttpc.ErrResponseBodyTooLarge, err)
+		}
+
+		if strings.Contains(logs.String(), strings.Repeat("a", 101)) {
+			t.Error("expected the captured body to be limited")
+		}
+	})
+}
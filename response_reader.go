@@ -0,0 +1,63 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ResponseReaderMiddleware abstracts a function to wrap the decompressed response body,
+// for stream transformations such as decryption or line-ending normalization.
+// The returned reader should read lazily from the body instead of buffering it.
+type ResponseReaderMiddleware func(body io.ReadCloser, resp *http.Response) io.ReadCloser
+
+// wrapResponseReader applies reader middlewares to the response body in order,
+// so the first middleware wraps the decompressed body and the last one is read by the caller.
+func wrapResponseReader(resp *http.Response, middlewares []ResponseReaderMiddleware) {
+	if len(middlewares) == 0 || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	body := resp.Body
+	wrapped := body
+
+	for _, middleware := range middlewares {
+		wrapped = middleware(wrapped, resp)
+	}
+
+	if wrapped == body {
+		return
+	}
+
+	resp.Body = &responseReaderBody{
+		ReadCloser: wrapped,
+		body:       body,
+	}
+}
+
+// responseReaderBody closes the underlying body when the outermost wrapper is closed,
+// even if the wrapper doesn't close the reader it wraps.
+type responseReaderBody struct {
+	io.ReadCloser
+
+	body io.ReadCloser
+}
+
+// Close closes the outermost wrapper and the underlying body.
+func (rb *responseReaderBody) Close() error {
+	return errors.Join(rb.ReadCloser.Close(), rb.body.Close())
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+)
+
+type upperCaseReader struct {
+	reader io.Reader
+}
+
+func (ur upperCaseReader) Read(p []byte) (int, error) {
+	n, err := ur.reader.Read(p)
+	copy(p[:n], bytes.ToUpper(p[:n]))
+
+	return n, err
+}
+
+func TestResponseReaderMiddleware(t *testing.T) {
+	var (
+		next         chan struct{}
+		disconnected chan struct{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write([]byte("hello\n"))
+		w.(http.Flusher).Flush()
+
+		// The second line is only sent after the client read the first one.
+		select {
+		case <-next:
+			_, _ = w.Write([]byte("world\n"))
+		case <-r.Context().Done():
+			close(disconnected)
+		}
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(
+		gohttpc.WithResponseReaderMiddleware(
+			func(body io.ReadCloser, resp *http.Response) io.ReadCloser {
+				if resp.Header.Get("Content-Type") != "text/plain" {
+					return body
+				}
+
+				// The wrapper doesn't close the underlying body.
+				return io.NopCloser(upperCaseReader{reader: body})
+			},
+		),
+	)
+
+	t.Run("streams_transformed_body", func(t *testing.T) {
+		next = make(chan struct{})
+		disconnected = make(chan struct{})
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != "HELLO\n" {
+			t.Errorf("expected the first line to be transformed, got: %q", line)
+		}
+
+		close(next)
+
+		line, err = reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != "WORLD\n" {
+			t.Errorf("expected the second line to be transformed, got: %q", line)
+		}
+	})
+
+	t.Run("close_underlying_body", func(t *testing.T) {
+		next = make(chan struct{})
+		disconnected = make(chan struct{})
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != "HELLO\n" {
+			t.Errorf("expected the first line to be transformed, got: %q", line)
+		}
+
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-disconnected:
+		case <-time.After(time.Second):
+			t.Error("expected the underlying body to be closed")
+			close(next)
+		}
+	})
+}
+
+func TestResponseReaderMiddleware_CachedResponse(t *testing.T) {
+	const etag = `"v1"`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(
+		gohttpc.WithResponseCache(gohttpc.NewMemoryCache()),
+		gohttpc.WithResponseReaderMiddleware(
+			func(body io.ReadCloser, _ *http.Response) io.ReadCloser {
+				return struct {
+					io.Reader
+					io.Closer
+				}{
+					Reader: io.MultiReader(strings.NewReader("X"), body),
+					Closer: body,
+				}
+			},
+		),
+	)
+	defer client.Close()
+
+	// The second response is served from the cache after the 304 revalidation.
+	for i := range 2 {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(respBody) != "Xbody" {
+			t.Errorf("%d: expected the body transformed once, got: %s", i, respBody)
+		}
+	}
+}
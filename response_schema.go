@@ -0,0 +1,95 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/relychan/goutils"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// responseSchemaURL is the resource location of the compiled response schema.
+const responseSchemaURL = "response.schema.json"
+
+// ResponseSchema represents a compiled JSON schema which validates response bodies.
+type ResponseSchema struct {
+	schema *jsonschema.Schema
+}
+
+// NewResponseSchema compiles the JSON schema document to validate response bodies.
+func NewResponseSchema(schema []byte) (*ResponseSchema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponseSchema, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+
+	err = compiler.AddResource(responseSchemaURL, doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponseSchema, err)
+	}
+
+	compiled, err := compiler.Compile(responseSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponseSchema, err)
+	}
+
+	return &ResponseSchema{schema: compiled}, nil
+}
+
+// Validate validates the JSON document against the schema.
+func (rs *ResponseSchema) Validate(data []byte) error {
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: invalid JSON: %w", ErrResponseSchemaValidation, err)
+	}
+
+	err = rs.schema.Validate(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResponseSchemaValidation, err)
+	}
+
+	return nil
+}
+
+// validateResponse reads the response body and validates it against the schema.
+// The body is replaced with the read content so it is still available to the caller.
+func (rs *ResponseSchema) validateResponse(resp *http.Response) error {
+	if resp == nil || resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+
+	goutils.CatchWarnErrorFunc(resp.Body.Close)
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	// Responses without content, such as 204 No Content, are not validated.
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	return rs.Validate(data)
+}
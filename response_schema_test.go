@@ -0,0 +1,141 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"properties": {
+		"id": { "type": "integer" },
+		"name": { "type": "string" }
+	},
+	"required": ["id", "name"]
+}`
+
+func TestResponseSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/valid":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Alice"}`))
+		case "/invalid":
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_, _ = w.Write([]byte(`not json`))
+		}
+	}))
+	defer server.Close()
+
+	schema, err := gohttpc.NewResponseSchema([]byte(testUserSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithResponseValidationSchema(schema))
+	defer client.Close()
+
+	testCases := []struct {
+		Name          string
+		Path          string
+		ExpectedError bool
+		ExpectedBody  string
+	}{
+		{
+			Name:         "conforming",
+			Path:         "/valid",
+			ExpectedBody: `{"id": 1, "name": "Alice"}`,
+		},
+		{
+			Name:          "non_conforming",
+			Path:          "/invalid",
+			ExpectedError: true,
+			ExpectedBody:  `{"id": "1"}`,
+		},
+		{
+			Name:          "invalid_json",
+			Path:          "/text",
+			ExpectedError: true,
+			ExpectedBody:  `not json`,
+		},
+		{
+			Name: "no_content",
+			Path: "/empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := client.R(http.MethodGet, server.URL+tc.Path).Execute(context.Background())
+			if tc.ExpectedError {
+				if !errors.Is(err, gohttpc.ErrResponseSchemaValidation) {
+					t.Fatalf("expected the schema validation error, got: %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error, got: %s", err)
+			}
+
+			if resp == nil {
+				t.Fatal("expected the response, got nil")
+			}
+			defer resp.Body.Close()
+
+			// The body is still available after validation.
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != tc.ExpectedBody {
+				t.Errorf("expected body %s, got: %s", tc.ExpectedBody, string(body))
+			}
+		})
+	}
+
+	t.Run("request_override", func(t *testing.T) {
+		req := client.R(http.MethodGet, server.URL+"/invalid")
+
+		err := req.SetResponseSchema([]byte(`{"type": "object"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatalf("expected no error, got: %s", err)
+		}
+
+		resp.Body.Close()
+	})
+
+	t.Run("invalid_schema", func(t *testing.T) {
+		_, err := gohttpc.NewResponseSchema([]byte(`{"type": 1}`))
+		if !errors.Is(err, gohttpc.ErrInvalidResponseSchema) {
+			t.Fatalf("expected the invalid schema error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hasura/gotel/otelutils"
+	"github.com/relychan/gohttpc"
+)
+
+func TestResponseHelpers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+
+			gw := gzip.NewWriter(w)
+			_, _ = gw.Write([]byte(`{"name":"gohttpc"}`))
+			_ = gw.Close()
+		default:
+			_, _ = w.Write([]byte(`{"name":"gohttpc"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient()
+	defer client.Close()
+
+	t.Run("json", func(t *testing.T) {
+		for _, path := range []string{"/", "/gzip"} {
+			resp, err := client.R(http.MethodGet, server.URL+path).Send(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode() != http.StatusOK {
+				t.Fatalf("%s: expected status 200, got: %d", path, resp.StatusCode())
+			}
+
+			var body struct {
+				Name string `json:"name"`
+			}
+
+			err = resp.JSON(&body)
+			if err != nil {
+				t.Fatalf("%s: %s", path, err)
+			}
+
+			if body.Name != "gohttpc" {
+				t.Errorf("%s: expected name gohttpc, got: %s", path, body.Name)
+			}
+
+			// The body is cached so it can be read again after closed.
+			resp.Close()
+
+			str, err := resp.ReadString()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str != `{"name":"gohttpc"}` {
+				t.Errorf("%s: expected the cached body, got: %s", path, str)
+			}
+		}
+	})
+
+	t.Run("debug_body_capture", func(t *testing.T) {
+		logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		ctx := context.WithValue(t.Context(), otelutils.LoggerContextKey, logger)
+
+		req := client.R(http.MethodGet, server.URL)
+		req.Header().Set("Content-Type", "application/json")
+
+		resp, err := req.Send(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Close()
+
+		for range 2 {
+			body, err := resp.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(body) != `{"name":"gohttpc"}` {
+				t.Errorf("expected the response body, got: %s", body)
+			}
+		}
+	})
+
+	t.Run("no_content", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, server.URL+"/empty").Send(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Close()
+
+		body, err := resp.Bytes()
+		if err != nil || len(body) != 0 {
+			t.Errorf("expected empty body, got: %s, %v", body, err)
+		}
+
+		var out map[string]any
+
+		err = resp.JSON(&out)
+		if !errors.Is(err, gohttpc.ErrResponseBodyNoContent) {
+			t.Errorf("expected ErrResponseBodyNoContent, got: %v", err)
+		}
+	})
+}
+
+func TestResponseTiming(t *testing.T) {
+	const serverDelay = 20 * time.Millisecond
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(serverDelay)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	execute := func(t *testing.T, client *gohttpc.Client, url string) *gohttpc.Timing {
+		t.Helper()
+
+		resp, err := client.R(http.MethodGet, url).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer resp.Body.Close()
+
+		return gohttpc.NewResponse(resp).Timing()
+	}
+
+	t.Run("tls", func(t *testing.T) {
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+
+		client := gohttpc.NewClient(
+			gohttpc.WithHTTPClient(server.Client()),
+			gohttpc.EnableClientTrace(true),
+		)
+		defer client.Close()
+
+		timing := execute(t, client, server.URL)
+		if timing == nil {
+			t.Fatal("expected timing, got nil")
+		}
+
+		if timing.ConnectionReused {
+			t.Error("expected a new connection")
+		}
+
+		if timing.RemoteAddress != server.Listener.Addr().String() {
+			t.Errorf("expected remote address %s, got: %s", server.Listener.Addr(), timing.RemoteAddress)
+		}
+
+		if timing.Connect <= 0 || timing.TLSHandshake <= 0 {
+			t.Errorf("expected connect and TLS handshake durations, got: %+v", timing)
+		}
+
+		if timing.TimeToFirstByte < serverDelay {
+			t.Errorf("expected time to first byte >= %s, got: %s", serverDelay, timing.TimeToFirstByte)
+		}
+
+		if timing.Total < timing.TimeToFirstByte ||
+			timing.Total < timing.Connect+timing.TLSHandshake {
+			t.Errorf("expected total to cover other durations, got: %+v", timing)
+		}
+
+		reusedTiming := execute(t, client, server.URL)
+		if reusedTiming == nil {
+			t.Fatal("expected timing, got nil")
+		}
+
+		if !reusedTiming.ConnectionReused || reusedTiming.Connect != 0 || reusedTiming.TLSHandshake != 0 {
+			t.Errorf("expected a reused connection without connect and TLS handshake, got: %+v", reusedTiming)
+		}
+
+		if reusedTiming.TimeToFirstByte < serverDelay || reusedTiming.Total < reusedTiming.TimeToFirstByte {
+			t.Errorf("unexpected timing of the reused connection: %+v", reusedTiming)
+		}
+	})
+
+	t.Run("dns", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		client := gohttpc.NewClient(gohttpc.EnableClientTrace(true))
+		defer client.Close()
+
+		timing := execute(t, client, strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+		if timing == nil {
+			t.Fatal("expected timing, got nil")
+		}
+
+		if timing.DNSLookup <= 0 {
+			t.Errorf("expected DNS lookup duration, got: %+v", timing)
+		}
+
+		if timing.TLSHandshake != 0 {
+			t.Errorf("expected no TLS handshake, got: %s", timing.TLSHandshake)
+		}
+
+		if timing.Total < timing.DNSLookup+timing.Connect {
+			t.Errorf("expected total to cover other durations, got: %+v", timing)
+		}
+	})
+
+	t.Run("request_override", func(t *testing.T) {
+		recorder := getSpanRecorder()
+
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		client := gohttpc.NewClient(gohttpc.EnableClientTrace(false))
+		defer client.Close()
+
+		url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+		send := func(t *testing.T, req *gohttpc.RequestWithClient) *gohttpc.Timing {
+			t.Helper()
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer resp.Body.Close()
+
+			return gohttpc.NewResponse(resp).Timing()
+		}
+
+		timing := send(t, client.R(http.MethodGet, url).EnableClientTrace(true))
+		if timing == nil || timing.DNSLookup <= 0 {
+			t.Errorf("expected DNS lookup duration, got: %+v", timing)
+		}
+
+		if _, ok := findSpanAttribute(recorder, "GET", "http.stats.dns_lookup_time_ms"); !ok {
+			t.Error("expected the DNS lookup span attribute of the traced request")
+		}
+
+		// A new client dials a fresh connection, so the DNS lookup happens again.
+		untracedClient := gohttpc.NewClient(gohttpc.EnableClientTrace(false))
+		defer untracedClient.Close()
+
+		if timing := send(t, untracedClient.R(http.MethodGet, url)); timing != nil {
+			t.Errorf("expected nil timing of the request without the override, got: %+v", timing)
+		}
+
+		if _, ok := findSpanAttribute(recorder, "GET", "http.stats.dns_lookup_time_ms"); ok {
+			t.Error("expected no DNS lookup span attribute of the request without the override")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		client := gohttpc.NewClient(gohttpc.EnableClientTrace(false))
+		defer client.Close()
+
+		if timing := execute(t, client, server.URL); timing != nil {
+			t.Errorf("expected nil timing, got: %+v", timing)
+		}
+	})
+}
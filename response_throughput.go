@@ -0,0 +1,103 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// throughputBody wraps the response body to abort the read if the throughput drops below the minimum rate.
+// Reading the first n bytes must complete within the grace period plus n times the deadline per byte,
+// starting from the first read so a caller which is slow to start reading doesn't burn the grace period.
+// The underlying body is closed when the deadline passes so a stalled read is unblocked.
+type throughputBody struct {
+	io.ReadCloser
+
+	perByte     time.Duration
+	gracePeriod time.Duration
+	started     bool
+	deadline    time.Time
+	timer       *time.Timer
+	tooSlow     atomic.Bool
+}
+
+func newThroughputBody(body io.ReadCloser, perByte time.Duration, gracePeriod time.Duration) *throughputBody {
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultResponseReadGracePeriod
+	}
+
+	tb := &throughputBody{
+		ReadCloser:  body,
+		perByte:     perByte,
+		gracePeriod: gracePeriod,
+	}
+
+	// The timer is armed on the first read.
+	tb.timer = time.AfterFunc(gracePeriod, tb.abort)
+	tb.timer.Stop()
+
+	return tb
+}
+
+// Read reads the body and checks the throughput.
+func (tb *throughputBody) Read(p []byte) (int, error) {
+	if !tb.started {
+		tb.started = true
+		tb.deadline = time.Now().Add(tb.gracePeriod)
+		tb.timer.Reset(tb.gracePeriod)
+	}
+
+	n, err := tb.ReadCloser.Read(p)
+
+	if tb.tooSlow.Load() {
+		return n, ErrResponseBodyTooSlow
+	}
+
+	if err != nil {
+		tb.timer.Stop()
+
+		return n, err
+	}
+
+	tb.deadline = tb.deadline.Add(time.Duration(n) * tb.perByte)
+
+	remaining := time.Until(tb.deadline)
+	if remaining <= 0 {
+		tb.abort()
+
+		return n, ErrResponseBodyTooSlow
+	}
+
+	tb.timer.Reset(remaining)
+
+	return n, nil
+}
+
+// Close stops the deadline timer and closes the body.
+func (tb *throughputBody) Close() error {
+	tb.timer.Stop()
+
+	return tb.ReadCloser.Close()
+}
+
+func (tb *throughputBody) abort() {
+	if tb.tooSlow.Swap(true) {
+		return
+	}
+
+	_ = tb.ReadCloser.Close()
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestResponseDeadlinePerByte(t *testing.T) {
+	// The server writes the chunks of the given size with the interval in between.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunkSize, _ := strconv.Atoi(r.URL.Query().Get("chunk"))
+		interval, _ := time.ParseDuration(r.URL.Query().Get("interval"))
+
+		w.WriteHeader(http.StatusOK)
+
+		for range 10 {
+			_, err := w.Write([]byte(strings.Repeat("a", chunkSize)))
+			if err != nil {
+				return
+			}
+
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}))
+	defer server.Close()
+
+	// Requires at least 1000 bytes per second after a grace period of 100ms.
+	client := gohttpc.NewClient(gohttpc.WithResponseDeadlinePerByte(time.Millisecond, 100*time.Millisecond))
+
+	testCases := []struct {
+		Name        string
+		Query       string
+		ExpectedErr error
+		ExpectedLen int
+	}{
+		{
+			Name:        "above_threshold",
+			Query:       "chunk=100&interval=10ms",
+			ExpectedLen: 1000,
+		},
+		{
+			Name:        "below_threshold",
+			Query:       "chunk=1&interval=50ms",
+			ExpectedErr: gohttpc.ErrResponseBodyTooSlow,
+		},
+		{
+			Name:        "stalled",
+			Query:       "chunk=1&interval=10s",
+			ExpectedErr: gohttpc.ErrResponseBodyTooSlow,
+		},
+	}
+
+	t.Run("grace_period_starts_on_first_read", func(t *testing.T) {
+		resp, err := client.R(http.MethodGet, server.URL+"?chunk=100&interval=1ms").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer goutils.CloseResponse(resp)
+
+		// The caller is slower to start reading than the grace period.
+		time.Sleep(300 * time.Millisecond)
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(body) != 1000 {
+			t.Errorf("expected 1000 bytes, got: %d", len(body))
+		}
+	})
+
+	t.Run("zero_grace_period_uses_default", func(t *testing.T) {
+		zeroGraceClient := gohttpc.NewClient(gohttpc.WithResponseDeadlinePerByte(time.Millisecond, 0))
+
+		resp, err := zeroGraceClient.R(http.MethodGet, server.URL+"?chunk=100&interval=1ms").
+			Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer goutils.CloseResponse(resp)
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if len(body) != 1000 {
+			t.Errorf("expected 1000 bytes, got: %d", len(body))
+		}
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := client.R(http.MethodGet, server.URL+"?"+tc.Query).Execute(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer goutils.CloseResponse(resp)
+
+			start := time.Now()
+			body, err := io.ReadAll(resp.Body)
+
+			if !errors.Is(err, tc.ExpectedErr) {
+				t.Fatalf("expected error %v, got: %v", tc.ExpectedErr, err)
+			}
+
+			if tc.ExpectedErr == nil && len(body) != tc.ExpectedLen {
+				t.Errorf("expected %d bytes, got: %d", tc.ExpectedLen, len(body))
+			}
+
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("expected the read to complete or abort quickly, got: %s", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetMaxDeposits is the number of successful requests whose deposits can be accumulated,
+// so a long healthy period can't bank unbounded retries for the next outage.
+const retryBudgetMaxDeposits = 100
+
+// RetryBudget is a token bucket which is shared across requests of a client to cap the retry amplification.
+// Each retry consumes a token, and each successful request deposits the ratio of a token.
+// When the bucket is empty, requests are no longer retried even if the retry policy would.
+type RetryBudget struct {
+	ratio     float64
+	minPerSec int
+
+	lock    sync.Mutex
+	balance float64
+	// The unix second of the current window of the minimum retries.
+	second int64
+	// The number of minimum retries which were used in the current window.
+	reserved int
+}
+
+// NewRetryBudget creates a new retry budget. Each successful request deposits ratio tokens,
+// e.g. 0.1 permits one retry for every 10 successful requests.
+// minPerSec retries are permitted per second regardless of the balance so low-traffic clients can still retry.
+func NewRetryBudget(ratio float64, minPerSec int) *RetryBudget {
+	return &RetryBudget{
+		ratio:     max(ratio, 0),
+		minPerSec: max(minPerSec, 0),
+	}
+}
+
+// Deposit adds the ratio of a token for a successful request.
+func (rb *RetryBudget) Deposit() {
+	if rb.ratio <= 0 {
+		return
+	}
+
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	rb.balance = min(rb.balance+rb.ratio, rb.ratio*retryBudgetMaxDeposits)
+}
+
+// TryWithdraw consumes a token for a retry. Returns false if the budget is exhausted.
+func (rb *RetryBudget) TryWithdraw() bool {
+	now := time.Now().Unix()
+
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+
+	if rb.second != now {
+		rb.second = now
+		rb.reserved = 0
+	}
+
+	if rb.reserved < rb.minPerSec {
+		rb.reserved++
+
+		return true
+	}
+
+	if rb.balance >= 1 {
+		rb.balance--
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestRetryBudget(t *testing.T) {
+	var failedAttempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			failedAttempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 3,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	// Two successful requests deposit a token for one retry.
+	client := gohttpc.NewClient(gohttpc.WithRetry(retry), gohttpc.WithRetryBudget(0.5, 0))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	send := func(path string) {
+		resp, _ := client.R(http.MethodGet, server.URL+path).Execute(context.Background())
+		if resp != nil {
+			goutils.CloseResponse(resp)
+		}
+	}
+
+	// The budget is empty, so the failed request isn't retried.
+	send("/fail")
+
+	if failedAttempts.Load() != 1 {
+		t.Errorf("expected the failed request not to be retried, got %d attempts", failedAttempts.Load())
+	}
+
+	send("/ok")
+	send("/ok")
+
+	// The deposited token permits a single retry before the budget is exhausted again.
+	failedAttempts.Store(0)
+	send("/fail")
+
+	if failedAttempts.Load() != 2 {
+		t.Errorf("expected the failed request to be retried once, got %d attempts", failedAttempts.Load())
+	}
+
+	failedAttempts.Store(0)
+	send("/fail")
+
+	if failedAttempts.Load() != 1 {
+		t.Errorf("expected the failed request not to be retried, got %d attempts", failedAttempts.Load())
+	}
+
+	results := collectInt64Sums(t, reader)
+	if results["http.client.suppressed_retries"] != 3 {
+		t.Errorf("expected 3 suppressed retries, got: %d", results["http.client.suppressed_retries"])
+	}
+
+	t.Run("minimum_retries_per_second", func(t *testing.T) {
+		budget := gohttpc.NewRetryBudget(0.1, 2)
+
+		for i := range 2 {
+			if !budget.TryWithdraw() {
+				t.Fatalf("expected the reserved retry %d to be permitted", i)
+			}
+		}
+
+		// The second can roll over between withdrawals, which only permits more retries.
+		if budget.TryWithdraw() && budget.TryWithdraw() && budget.TryWithdraw() {
+			t.Error("expected the retry budget to be exhausted")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ServerNameRoundTripper abstracts a round-tripper which can derive a round-tripper with a custom TLS server name.
+// Implement it to override server names of requests with a round-tripper other than [http.Transport].
+type ServerNameRoundTripper interface {
+	// WithServerName returns a round-tripper which sends requests with the TLS server name.
+	// The original round-tripper must not be mutated.
+	WithServerName(serverName string) http.RoundTripper
+}
+
+type serverNameContextKey struct{}
+
+// ServerNameFromContext returns the overridden TLS server name of the request context.
+// Custom TLS dialers use it to handshake with the overridden server name.
+func ServerNameFromContext(ctx context.Context) string {
+	serverName, _ := ctx.Value(serverNameContextKey{}).(string)
+
+	return serverName
+}
+
+// ServerNameTransports caches a round-tripper per TLS server name (SNI) for an HTTP client,
+// so requests with the same server name reuse connections. The zero value is ready to use.
+type ServerNameTransports struct {
+	transports sync.Map
+}
+
+// Do sends an HTTP request with the TLS server name overridden for this request only.
+// The round-tripper of the client is cloned once per server name, so the shared round-tripper is never mutated.
+func (st *ServerNameTransports) Do(
+	client *http.Client,
+	req *http.Request,
+	serverName string,
+) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	roundTripper, ok := st.transports.Load(serverName)
+	if !ok {
+		newRoundTripper, err := newServerNameRoundTripper(client.Transport, serverName)
+		if err != nil {
+			return nil, err
+		}
+
+		var loaded bool
+
+		roundTripper, loaded = st.transports.LoadOrStore(serverName, newRoundTripper)
+		if loaded {
+			closeIdleConnections(newRoundTripper)
+		}
+	}
+
+	serverNameClient := *client
+	serverNameClient.Transport = roundTripper.(http.RoundTripper) //nolint:forcetypeassert
+
+	return serverNameClient.Do(withServerNameContext(req, serverName)) //nolint:gosec
+}
+
+// Reset closes idle connections and removes all cached round-trippers.
+// Call it when the round-tripper of the HTTP client is replaced.
+func (st *ServerNameTransports) Reset() {
+	st.transports.Range(func(key, value any) bool {
+		st.transports.Delete(key)
+		closeIdleConnections(value.(http.RoundTripper)) //nolint:forcetypeassert
+
+		return true
+	})
+}
+
+// CloseIdleConnections closes idle connections of cached round-trippers.
+func (st *ServerNameTransports) CloseIdleConnections() {
+	st.transports.Range(func(_, value any) bool {
+		closeIdleConnections(value.(http.RoundTripper)) //nolint:forcetypeassert
+
+		return true
+	})
+}
+
+// DoWithServerName sends an HTTP request with the TLS server name (SNI) overridden for this request only.
+// The transport of the client is cloned so the shared transport is never mutated.
+// Connections of the cloned transport are closed when the response body is closed.
+// Use [ServerNameTransports] to reuse connections of repeated requests.
+func DoWithServerName(client *http.Client, req *http.Request, serverName string) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	roundTripper, err := newServerNameRoundTripper(client.Transport, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	serverNameClient := *client
+	serverNameClient.Transport = roundTripper
+
+	resp, err := serverNameClient.Do(withServerNameContext(req, serverName)) //nolint:gosec
+	if err != nil || resp.Body == nil || resp.Body == http.NoBody {
+		closeIdleConnections(roundTripper)
+
+		return resp, err
+	}
+
+	resp.Body = &serverNameResponseBody{
+		ReadCloser:   resp.Body,
+		roundTripper: roundTripper,
+	}
+
+	return resp, nil
+}
+
+// newServerNameRoundTripper derives a round-tripper which sends requests with the TLS server name.
+func newServerNameRoundTripper(roundTripper http.RoundTripper, serverName string) (http.RoundTripper, error) {
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+
+	switch rt := roundTripper.(type) {
+	case ServerNameRoundTripper:
+		return rt.WithServerName(serverName), nil
+	case *http.Transport:
+		// Clone also deep-copies the TLS config.
+		transport := rt.Clone()
+
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{} //nolint:gosec
+		}
+
+		transport.TLSClientConfig.ServerName = serverName
+
+		return transport, nil
+	default:
+		return nil, ErrServerNameOverrideUnsupported
+	}
+}
+
+// withServerNameContext stores the server name to the request context for custom TLS dialers.
+func withServerNameContext(req *http.Request, serverName string) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), serverNameContextKey{}, serverName))
+}
+
+func closeIdleConnections(roundTripper http.RoundTripper) {
+	if closer, ok := roundTripper.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// serverNameResponseBody closes connections of the temporary round-tripper when the body is closed.
+type serverNameResponseBody struct {
+	io.ReadCloser
+
+	roundTripper http.RoundTripper
+}
+
+// Close closes the body and idle connections of the temporary round-tripper.
+func (sb *serverNameResponseBody) Close() error {
+	err := sb.ReadCloser.Close()
+
+	closeIdleConnections(sb.roundTripper)
+
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+)
+
+// serverNameRoundTripper records server names of derived round-trippers.
+type serverNameRoundTripper struct {
+	serverNames chan string
+}
+
+func (rt serverNameRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("not derived")
+}
+
+func (rt serverNameRoundTripper) WithServerName(serverName string) http.RoundTripper {
+	rt.serverNames <- serverName
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if gohttpc.ServerNameFromContext(req.Context()) != serverName {
+			return nil, errors.New("expected the server name in the request context")
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestServerNameTransports(t *testing.T) {
+	var handshakes atomic.Int32
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			if hello.ServerName == "example.com" {
+				handshakes.Add(1)
+			}
+
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithHTTPClient(server.Client()))
+	defer client.Close()
+
+	for range 3 {
+		req := client.R(http.MethodGet, server.URL)
+		req.SetServerName("example.com")
+
+		resp, err := req.Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = resp.Body.Close()
+	}
+
+	if handshakes.Load() != 1 {
+		t.Errorf("expected connections of the server name reused, got %d handshakes", handshakes.Load())
+	}
+
+	t.Run("custom_round_tripper", func(t *testing.T) {
+		serverNames := make(chan string, 2)
+
+		client := gohttpc.NewClient(gohttpc.WithHTTPClient(&http.Client{
+			Transport: serverNameRoundTripper{serverNames: serverNames},
+		}))
+		defer client.Close()
+
+		for range 2 {
+			req := client.R(http.MethodGet, "https://127.0.0.1")
+			req.SetServerName("example.com")
+
+			resp, err := req.Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+		}
+
+		if len(serverNames) != 1 || <-serverNames != "example.com" {
+			t.Error("expected the round-tripper derived once for example.com")
+		}
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanAttributes(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	defer func() {
+		_ = meterProvider.Shutdown(t.Context())
+	}()
+
+	clientMetrics, err := gohttpc.NewHTTPClientMetrics(meterProvider.Meter("test"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gohttpc.SetHTTPClientMetrics(clientMetrics)
+	defer gohttpc.SetHTTPClientMetrics(nil)
+
+	client := gohttpc.NewClient(gohttpc.WithCustomAttributesFunc(func(gohttpc.Requester) []attribute.KeyValue {
+		return []attribute.KeyValue{attribute.String("service.tier", "gold")}
+	}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	req := client.R(http.MethodGet, server.URL)
+	req.SetSpanAttributes(attribute.String("tenant.id", "acme")).
+		SetSpanAttributes(attribute.Int("order.id", 42))
+
+	resp, err := req.Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	expectedSpanAttrs := map[string]string{
+		"tenant.id":    "acme",
+		"order.id":     "42",
+		"service.tier": "gold",
+	}
+
+	for key, expected := range expectedSpanAttrs {
+		value, ok := findSpanAttribute(recorder, "Request", key)
+		if !ok || value != expected {
+			t.Errorf("expected the span attribute %s=%s, got: %s", key, expected, value)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+
+	err = reader.Collect(t.Context(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || m.Name != "http.client.request.duration" {
+				continue
+			}
+
+			for _, dp := range histogram.DataPoints {
+				if value, ok := dp.Attributes.Value("service.tier"); ok && value.AsString() == "gold" {
+					found = true
+				}
+
+				if _, ok := dp.Attributes.Value("tenant.id"); ok {
+					t.Error("expected request span attributes not to be added to metrics")
+				}
+			}
+		}
+	}
+
+	if !found {
+		t.Error("expected custom attributes to be added to the request duration metric")
+	}
+}
+
+func TestCustomAttributesFunc_CalledOncePerRequest(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 3,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls atomic.Int32
+
+	client := gohttpc.NewClient(
+		gohttpc.WithRetry(retry),
+		gohttpc.WithCustomAttributesFunc(func(gohttpc.Requester) []attribute.KeyValue {
+			calls.Add(1)
+
+			return nil
+		}),
+	)
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got: %d", attempts.Load())
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected the custom attributes function to be called once, got: %d", calls.Load())
+	}
+}
+
+func TestSpanNameFunc(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spanNameFunc := gohttpc.WithSpanNameFunc(func(req *gohttpc.Request) string {
+		if req.PathParams()["id"] == "" {
+			return ""
+		}
+
+		return req.Method() + " /users/{id}"
+	})
+
+	for _, clientTrace := range []bool{true, false} {
+		client := gohttpc.NewClient(spanNameFunc, gohttpc.EnableClientTrace(clientTrace))
+
+		req := client.R(http.MethodGet, server.URL+"/users/{id}")
+		req.SetPathParam("id", "42")
+
+		resp, err := req.Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+
+		// Falls back to the default name if the function returns an empty string.
+		resp, err = client.R(http.MethodDelete, server.URL+"/users").Execute(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goutils.CloseResponse(resp)
+		goutils.CatchWarnErrorFunc(client.Close)
+
+		spans := recorder.Ended()
+		names := make([]string, 0, 2)
+
+		for _, span := range spans {
+			if span.SpanKind() == trace.SpanKindClient {
+				names = append(names, span.Name())
+			}
+		}
+
+		if len(names) < 2 || names[len(names)-2] != "GET /users/{id}" || names[len(names)-1] != "DELETE" {
+			t.Errorf("client trace %t: expected the span names [GET /users/{id} DELETE], got %v", clientTrace, names)
+		}
+	}
+}
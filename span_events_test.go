@@ -0,0 +1,162 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// findSpanEvents finds the events with the name of the last ended span with the span name.
+func findSpanEvents(recorder *tracetest.SpanRecorder, spanName string, eventName string) []sdktrace.Event {
+	spans := recorder.Ended()
+
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() != spanName {
+			continue
+		}
+
+		var events []sdktrace.Event
+
+		for _, event := range spans[i].Events() {
+			if event.Name == eventName {
+				events = append(events, event)
+			}
+		}
+
+		return events
+	}
+
+	return nil
+}
+
+func getEventAttribute(event sdktrace.Event, key string) string {
+	for _, attr := range event.Attributes {
+		if string(attr.Key) == key {
+			return attr.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+func TestRetrySpanEvents(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	delay := int64(1)
+
+	retry, err := httpconfig.HTTPRetryConfig{
+		MaxAttempts: 3,
+		Delay:       &delay,
+	}.ToRetryPolicy() //nolint:bodyclose
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := gohttpc.NewClient(gohttpc.WithRetry(retry))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goutils.CloseResponse(resp)
+
+	events := findSpanEvents(recorder, "Request", "retry")
+	if len(events) != 2 {
+		t.Fatalf("expected 2 retry events, got: %d", len(events))
+	}
+
+	for i, event := range events {
+		expectedAttempt := []string{"2", "3"}[i]
+
+		if attempt := getEventAttribute(event, "http.request.attempt"); attempt != expectedAttempt {
+			t.Errorf("expected the attempt %s, got: %s", expectedAttempt, attempt)
+		}
+
+		if status := getEventAttribute(event, "http.response.status_code"); status != "503" {
+			t.Errorf("expected the previous status 503, got: %s", status)
+		}
+	}
+}
+
+func TestCircuitBreakerSpanEvents(t *testing.T) {
+	recorder := getSpanRecorder()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := gohttpc.NewClient(gohttpc.WithCircuitBreaker(gohttpc.CircuitBreakerConfig{
+		Name:             "test",
+		FailureThreshold: 2,
+	}))
+	defer goutils.CatchWarnErrorFunc(client.Close)
+
+	for i := range 2 {
+		resp, err := client.R(http.MethodGet, server.URL).Execute(context.Background())
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		goutils.CloseResponse(resp)
+
+		events := findSpanEvents(recorder, "Request", "circuit_breaker.state_change")
+
+		if i == 0 {
+			if len(events) != 0 {
+				t.Fatalf("expected no state change event, got: %d", len(events))
+			}
+
+			continue
+		}
+
+		if len(events) != 1 {
+			t.Fatalf("expected 1 state change event, got: %d", len(events))
+		}
+
+		if state := getEventAttribute(events[0], "circuit_breaker.old_state"); state != "closed" {
+			t.Errorf("expected the old state closed, got: %s", state)
+		}
+
+		if state := getEventAttribute(events[0], "circuit_breaker.new_state"); state != "open" {
+			t.Errorf("expected the new state open, got: %s", state)
+		}
+	}
+}
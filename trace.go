@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"runtime/debug"
 	"strconv"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -98,7 +99,7 @@ func (sct *simpleClientTrace) EndSpan(
 	sct.End(options...)
 	totalTime := time.Since(sct.startTime)
 
-	GetHTTPClientMetrics().ServerDuration.Record(
+	metricsFromContext(ctx).ServerDuration.Record(
 		ctx,
 		totalTime.Seconds(),
 		metric.WithAttributeSet(attribute.NewSet(sct.metricAttrs...)),
@@ -121,10 +122,42 @@ type clientTrace struct {
 	gotFirstResponseByte time.Time
 	host                 string
 	remoteAddr           string
+
+	// timing is read by the response after the request attempt, so it is guarded by the lock.
+	timingLock sync.Mutex
+	timing     Timing
 }
 
 var _ HTTPClientTracer = (*clientTrace)(nil)
 
+// Timing contains the timing breakdown of an HTTP request attempt.
+// It is recorded only if the client trace is enabled.
+type Timing struct {
+	// DNSLookup is the duration of the DNS lookup. Zero if the connection is reused or the host is an IP address.
+	DNSLookup time.Duration
+	// Connect is the duration to establish the TCP connection. Zero if the connection is reused.
+	Connect time.Duration
+	// TLSHandshake is the duration of the TLS handshake. Zero if the connection is reused or TLS is not used.
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the duration from the start of the attempt until the first response byte is received.
+	TimeToFirstByte time.Duration
+	// Total is the duration from the start of the attempt until the response headers are processed.
+	Total time.Duration
+	// ConnectionReused indicates whether the connection was reused from the pool.
+	ConnectionReused bool
+	// RemoteAddress is the address of the remote peer of the connection, e.g. 127.0.0.1:8080.
+	RemoteAddress string
+}
+
+type clientTraceContextKey struct{}
+
+// getClientTrace gets the client trace from the context.
+func getClientTrace(ctx context.Context) *clientTrace {
+	ct, _ := ctx.Value(clientTraceContextKey{}).(*clientTrace)
+
+	return ct
+}
+
 func startClientTrace(
 	ctx context.Context,
 	name string,
@@ -141,9 +174,26 @@ func startClientTrace(
 	)
 	ct.Span = span
 
+	spanContext = context.WithValue(spanContext, clientTraceContextKey{}, ct)
+
 	return ct.createContext(spanContext), ct //nolint:spancheck
 }
 
+// Timing returns a copy of the recorded timing breakdown.
+func (t *clientTrace) Timing() Timing {
+	t.timingLock.Lock()
+	defer t.timingLock.Unlock()
+
+	return t.timing
+}
+
+// updateTiming modifies the timing breakdown with the lock.
+func (t *clientTrace) updateTiming(fn func(timing *Timing)) {
+	t.timingLock.Lock()
+	fn(&t.timing)
+	t.timingLock.Unlock()
+}
+
 // SetMetricAttributes sets common attributes for metrics.
 func (t *clientTrace) SetMetricAttributes(attrs []attribute.KeyValue) {
 	t.metricAttrs = attrs
@@ -167,7 +217,7 @@ func (t *clientTrace) EndSpan(ctx context.Context, options ...trace.SpanEndOptio
 			requestStartTime = t.getConn
 		}
 
-		GetHTTPClientMetrics().ServerDuration.Record(
+		metricsFromContext(ctx).ServerDuration.Record(
 			ctx,
 			endTime.Sub(requestStartTime).Seconds(),
 			metricAttrSet,
@@ -184,6 +234,10 @@ func (t *clientTrace) EndSpan(ctx context.Context, options ...trace.SpanEndOptio
 
 	span.End(options...)
 
+	t.updateTiming(func(timing *Timing) {
+		timing.Total = totalTime
+	})
+
 	return totalTime
 }
 
@@ -197,7 +251,7 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 ) context.Context {
 	t.startTime = time.Now()
 	isTraceLogLevelEnabled := t.logger.Enabled(ctx, LogLevelTrace)
-	metrics := GetHTTPClientMetrics()
+	metrics := metricsFromContext(ctx)
 
 	var dnsStart, dnsDone, tlsHandshakeStart time.Time
 
@@ -215,7 +269,7 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 			// Calculate the total time accordingly when connection is reused,
 			// and DNS start and get conn time may be zero if the request is invalid.
 			t.host = info.Host
-			dnsStart := time.Now()
+			dnsStart = time.Now()
 			t.startTime = dnsStart
 		},
 		DNSDone: func(info httptrace.DNSDoneInfo) {
@@ -231,6 +285,10 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 
 			dnsLookupDuration := time.Since(dnsStart)
 
+			t.updateTiming(func(timing *Timing) {
+				timing.DNSLookup = dnsLookupDuration
+			})
+
 			t.SetAttributes(
 				attribute.Float64(
 					"http.stats.dns_lookup_time_ms",
@@ -288,6 +346,10 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 
 			tcpConnTime := time.Since(dnsDone)
 
+			t.updateTiming(func(timing *Timing) {
+				timing.Connect = tcpConnTime
+			})
+
 			t.SetAttributes(
 				attribute.Float64(
 					"http.stats.tcp_connection_time_ms",
@@ -325,6 +387,11 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 			t.gotConn = time.Now()
 			t.remoteAddr = ci.Conn.RemoteAddr().String()
 
+			t.updateTiming(func(timing *Timing) {
+				timing.ConnectionReused = ci.Reused
+				timing.RemoteAddress = t.remoteAddr
+			})
+
 			connTime := time.Since(t.getConn)
 
 			if ci.WasIdle {
@@ -357,6 +424,10 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 
 			t.gotFirstResponseByte = time.Now()
 
+			t.updateTiming(func(timing *Timing) {
+				timing.TimeToFirstByte = t.gotFirstResponseByte.Sub(t.startTime)
+			})
+
 			if !t.gotConn.IsZero() {
 				serverTime := t.gotFirstResponseByte.Sub(t.gotConn)
 				metrics.ServerDuration.Record(
@@ -397,6 +468,10 @@ func (t *clientTrace) createContext( //nolint:gocognit,funlen,maintidx
 
 			tlsHandshakeDuration := time.Since(tlsHandshakeStart)
 
+			t.updateTiming(func(timing *Timing) {
+				timing.TLSHandshake = tlsHandshakeDuration
+			})
+
 			t.SetAttributes(
 				attribute.Float64(
 					"http.stats.tls_handshake_time_ms",
@@ -459,6 +534,11 @@ func newNetworkProtocolVersion(protoMajor, protoMinor int) attribute.KeyValue {
 	)
 }
 
+// httpResponseStatusClassAttr returns the status class attribute of the response status code, e.g. 2xx.
+func httpResponseStatusClassAttr(statusCode int) attribute.KeyValue {
+	return attribute.String("http.response.status_class", strconv.Itoa(statusCode/100)+"xx")
+}
+
 func addRequestMetricAttributes(
 	attrs []attribute.KeyValue,
 	method string,
@@ -15,11 +15,16 @@
 package gohttpc
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"runtime"
 	"time"
 
+	"github.com/hasura/goenvconf"
 	"github.com/relychan/goutils"
 )
 
@@ -42,6 +47,9 @@ type HTTPDialerConfig struct {
 	// That is, this is the amount of time to wait for IPv6 to succeed before assuming that IPv6 is misconfigured and falling back to IPv4.
 	// If zero, a default delay of 300ms is used. A negative value disables Fast Fallback support.
 	FallbackDelay *goutils.Duration `json:"fallbackDelay,omitempty" jsonschema:"oneof_ref=#/$defs/Duration,oneof_type=null" yaml:"fallbackDelay"`
+	// DNSNegativeCacheTTL is the duration to cache hosts which are not found by DNS lookups (NXDOMAIN).
+	// Dials to a cached host fail fast without repeated lookups until the entry expires. Zero disables the cache.
+	DNSNegativeCacheTTL *goutils.Duration `json:"dnsNegativeCacheTTL,omitempty" jsonschema:"oneof_ref=#/$defs/Duration,oneof_type=null" yaml:"dnsNegativeCacheTTL"`
 }
 
 // IsZero if the current instance is empty.
@@ -49,7 +57,7 @@ func (c *HTTPDialerConfig) IsZero() bool {
 	return (c.Timeout == nil || *c.Timeout <= 0) &&
 		c.KeepAliveEnabled == nil && c.KeepAliveInterval == nil &&
 		c.KeepAliveCount == nil && c.KeepAliveIdle == nil &&
-		c.FallbackDelay == nil
+		c.FallbackDelay == nil && c.DNSNegativeCacheTTL == nil
 }
 
 // Equal checks if this instance equals the target.
@@ -59,7 +67,78 @@ func (c HTTPDialerConfig) Equal(target HTTPDialerConfig) bool {
 		goutils.EqualComparablePtr(c.KeepAliveInterval, target.KeepAliveInterval) &&
 		goutils.EqualComparablePtr(c.KeepAliveCount, target.KeepAliveCount) &&
 		goutils.EqualComparablePtr(c.KeepAliveIdle, target.KeepAliveIdle) &&
-		goutils.EqualComparablePtr(c.FallbackDelay, target.FallbackDelay)
+		goutils.EqualComparablePtr(c.FallbackDelay, target.FallbackDelay) &&
+		goutils.EqualComparablePtr(c.DNSNegativeCacheTTL, target.DNSNegativeCacheTTL)
+}
+
+// Validate checks if the ranges of the dialer configuration are valid.
+func (c HTTPDialerConfig) Validate() error {
+	if c.KeepAliveCount != nil && *c.KeepAliveCount < 0 {
+		return fmt.Errorf("keepAliveCount %w", ErrNegativeConfigValue)
+	}
+
+	return nil
+}
+
+// HTTPProxyConfig contains the configuration of the proxy server which the transport dials through.
+type HTTPProxyConfig struct {
+	// The URL of the proxy server. Supported schemes are http, https, socks5 and socks5h.
+	URL goenvconf.EnvString `json:"url" yaml:"url"`
+	// The optional username to authenticate to the proxy server.
+	Username *goenvconf.EnvString `json:"username,omitempty" yaml:"username,omitempty"`
+	// The optional password to authenticate to the proxy server.
+	Password *goenvconf.EnvString `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// IsZero if the current instance is empty.
+func (c *HTTPProxyConfig) IsZero() bool {
+	return c.URL.IsZero() &&
+		(c.Username == nil || c.Username.IsZero()) &&
+		(c.Password == nil || c.Password.IsZero())
+}
+
+// Equal checks if this instance equals the target.
+func (c HTTPProxyConfig) Equal(target HTTPProxyConfig) bool {
+	return c.URL.Equal(target.URL) &&
+		goutils.EqualPtr(c.Username, target.Username) &&
+		goutils.EqualPtr(c.Password, target.Password)
+}
+
+// ProxyURL resolves and validates the proxy URL with credentials.
+func (c HTTPProxyConfig) ProxyURL(getEnv goenvconf.GetEnvFunc) (*url.URL, error) {
+	rawURL, err := c.URL.GetCustom(getEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL, err := parseProxyURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Username == nil {
+		return proxyURL, nil
+	}
+
+	username, err := c.Username.GetCustom(getEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Password == nil {
+		proxyURL.User = url.User(username)
+
+		return proxyURL, nil
+	}
+
+	password, err := c.Password.GetCustom(getEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL.User = url.UserPassword(username, password)
+
+	return proxyURL, nil
 }
 
 // HTTPTransportConfig stores the http.Transport configuration for the http client.
@@ -98,6 +177,11 @@ type HTTPTransportConfig struct {
 	// DisableKeepAlives, if true, disables HTTP keep-alives and will only use the connection to the server for a single HTTP request.
 	// This is unrelated to the similarly named TCP keep-alives.
 	DisableKeepAlives bool `json:"disableKeepAlives,omitempty" yaml:"disableKeepAlives"`
+	// Proxy configures the transport to dial through the proxy server instead of the proxy from environment variables.
+	Proxy *HTTPProxyConfig `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// HTTP2PriorKnowledge enables HTTP/2 without TLS (h2c) for http:// URLs, without the HTTP/1.1 upgrade.
+	// HTTP/1.1 is disabled, so servers must support HTTP/2.
+	HTTP2PriorKnowledge bool `json:"http2PriorKnowledge,omitempty" yaml:"http2PriorKnowledge"`
 }
 
 // IsZero if the current instance is empty.
@@ -108,7 +192,8 @@ func (c *HTTPTransportConfig) IsZero() bool {
 		c.MaxIdleConns == nil && c.MaxIdleConnsPerHost == nil &&
 		c.MaxConnsPerHost == nil && c.MaxResponseHeaderBytes == nil &&
 		c.ReadBufferSize == nil && c.WriteBufferSize == nil &&
-		!c.DisableKeepAlives && c.ForceAttemptHTTP2 == nil
+		!c.DisableKeepAlives && c.ForceAttemptHTTP2 == nil &&
+		(c.Proxy == nil || c.Proxy.IsZero()) && !c.HTTP2PriorKnowledge
 }
 
 // Equal checks if this instance equals the target.
@@ -125,7 +210,45 @@ func (c HTTPTransportConfig) Equal(target HTTPTransportConfig) bool {
 		goutils.EqualComparablePtr(c.ReadBufferSize, target.ReadBufferSize) &&
 		goutils.EqualComparablePtr(c.WriteBufferSize, target.WriteBufferSize) &&
 		c.DisableKeepAlives == target.DisableKeepAlives &&
-		goutils.EqualComparablePtr(c.ForceAttemptHTTP2, target.ForceAttemptHTTP2)
+		goutils.EqualComparablePtr(c.ForceAttemptHTTP2, target.ForceAttemptHTTP2) &&
+		goutils.EqualPtr(c.Proxy, target.Proxy) &&
+		c.HTTP2PriorKnowledge == target.HTTP2PriorKnowledge
+}
+
+// Validate checks if the ranges of the transport configuration are valid.
+// All errors are joined so every invalid field is reported at once.
+func (c HTTPTransportConfig) Validate() error {
+	errs := []error{}
+
+	if c.Dialer != nil {
+		err := c.Dialer.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dialer: %w", err))
+		}
+	}
+
+	intFields := []struct {
+		name  string
+		value *int
+	}{
+		{"maxIdleConns", c.MaxIdleConns},
+		{"maxIdleConnsPerHost", c.MaxIdleConnsPerHost},
+		{"maxConnsPerHost", c.MaxConnsPerHost},
+		{"readBufferSize", c.ReadBufferSize},
+		{"writeBufferSize", c.WriteBufferSize},
+	}
+
+	for _, field := range intFields {
+		if field.value != nil && *field.value < 0 {
+			errs = append(errs, fmt.Errorf("%s %w", field.name, ErrNegativeConfigValue))
+		}
+	}
+
+	if c.MaxResponseHeaderBytes != nil && *c.MaxResponseHeaderBytes < 0 {
+		errs = append(errs, fmt.Errorf("maxResponseHeaderBytes %w", ErrNegativeConfigValue))
+	}
+
+	return errors.Join(errs...)
 }
 
 // TransportFromConfig creates an http transport from the configuration.
@@ -142,7 +265,7 @@ func TransportFromConfig(
 	dialer := DialerFromConfig(dialerConf)
 
 	defaultTransport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
+		Proxy:                 requestProxy(http.ProxyFromEnvironment),
 		MaxIdleConns:          100,
 		ResponseHeaderTimeout: time.Minute,
 		IdleConnTimeout:       90 * time.Second,
@@ -152,17 +275,61 @@ func TransportFromConfig(
 		DisableCompression:    true,
 	}
 
+	var dnsCache *dnsNegativeCache
+
+	if dialerConf != nil && dialerConf.DNSNegativeCacheTTL != nil && *dialerConf.DNSNegativeCacheTTL > 0 {
+		dnsCache = newDNSNegativeCache(time.Duration(*dialerConf.DNSNegativeCacheTTL))
+	}
+
 	defaultTransport.DialContext = transportDialContext(
-		dialer,
+		dialer.DialContext,
+		dnsCache,
 	)
 
+	if clientOptions != nil && clientOptions.VerifyPeerCertificate != nil {
+		defaultTransport.TLSClientConfig = &tls.Config{
+			MinVersion:            tls.VersionTLS12,
+			VerifyPeerCertificate: clientOptions.VerifyPeerCertificate,
+		}
+	}
+
+	if clientOptions != nil && clientOptions.HTTP2PriorKnowledge {
+		enableHTTP2PriorKnowledge(defaultTransport)
+	}
+
 	if ttc == nil {
 		return defaultTransport
 	}
 
+	if ttc.Proxy != nil && !ttc.Proxy.IsZero() {
+		getEnv := goenvconf.GetOSEnv
+
+		if clientOptions != nil {
+			getEnv = clientOptions.GetEnvFunc()
+		}
+
+		defaultTransport.Proxy = requestProxy(proxyFromConfig(ttc.Proxy, getEnv))
+	}
+
 	return applyTransport(ttc, defaultTransport)
 }
 
+// proxyFromConfig returns the proxy function of the proxy configuration.
+// Requests fail with the configuration error instead of bypassing the proxy if the configuration is invalid.
+func proxyFromConfig(
+	config *HTTPProxyConfig,
+	getEnv goenvconf.GetEnvFunc,
+) func(*http.Request) (*url.URL, error) {
+	proxyURL, err := config.ProxyURL(getEnv)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, err
+		}
+	}
+
+	return http.ProxyURL(proxyURL)
+}
+
 func applyTransport(ttc *HTTPTransportConfig, defaultTransport *http.Transport) *http.Transport {
 	if ttc.DisableKeepAlives {
 		defaultTransport.DisableKeepAlives = true
@@ -214,9 +381,22 @@ func applyTransport(ttc *HTTPTransportConfig, defaultTransport *http.Transport)
 		defaultTransport.ForceAttemptHTTP2 = *ttc.ForceAttemptHTTP2
 	}
 
+	if ttc.HTTP2PriorKnowledge {
+		enableHTTP2PriorKnowledge(defaultTransport)
+	}
+
 	return defaultTransport
 }
 
+// enableHTTP2PriorKnowledge restricts the transport to HTTP/2, including unencrypted HTTP/2 for http:// URLs.
+func enableHTTP2PriorKnowledge(transport *http.Transport) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	transport.Protocols = protocols
+}
+
 // DialerFromConfig creates a net dialer from the configuration.
 func DialerFromConfig(conf *HTTPDialerConfig) *net.Dialer {
 	dialer := &net.Dialer{
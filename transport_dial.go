@@ -16,7 +16,10 @@ package gohttpc
 
 import (
 	"context"
+	"errors"
 	"net"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hasura/gotel/otelutils"
@@ -25,19 +28,39 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 )
 
+type dialContextFunc func(ctx context.Context, network string, address string) (net.Conn, error)
+
 func transportDialContext(
-	dialer *net.Dialer,
-) func(context.Context, string, string) (net.Conn, error) {
+	dial dialContextFunc,
+	dnsCache *dnsNegativeCache,
+) dialContextFunc {
 	return func(ctx context.Context, network string, address string) (net.Conn, error) {
 		createdTime := time.Now()
+		metrics := metricsFromContext(ctx)
+
+		host, port, _ := otelutils.SplitHostPort(address, "")
+
+		if dnsCache != nil {
+			if err := dnsCache.get(host); err != nil {
+				metrics.DNSNegativeCacheHits.Add(
+					ctx,
+					1,
+					metric.WithAttributeSet(attribute.NewSet(semconv.DNSQuestionName(host))),
+				)
 
-		conn, err := dialer.DialContext(ctx, network, address)
+				return nil, err
+			}
+		}
+
+		conn, err := dial(ctx, network, address)
 		if err != nil {
+			if dnsCache != nil {
+				dnsCache.put(host, err)
+			}
+
 			return nil, err
 		}
 
-		_, port, _ := otelutils.SplitHostPort(address, "")
-		metrics := GetHTTPClientMetrics()
 		metricAttrSet := metric.WithAttributeSet(attribute.NewSet(
 			semconv.ServerAddress(address),
 			semconv.ServerPort(port),
@@ -74,3 +97,68 @@ func (c *connWithMetric) Close() error {
 
 	return c.Conn.Close()
 }
+
+// dnsNegativeCache caches the errors of hosts which are not found by DNS lookups for a short TTL,
+// so dials to misconfigured hosts fail fast without repeated slow lookups.
+type dnsNegativeCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries map[string]dnsNegativeCacheEntry
+}
+
+type dnsNegativeCacheEntry struct {
+	err       error
+	expiresAt time.Time
+}
+
+func newDNSNegativeCache(ttl time.Duration) *dnsNegativeCache {
+	return &dnsNegativeCache{
+		ttl:     ttl,
+		entries: map[string]dnsNegativeCacheEntry{},
+	}
+}
+
+// get returns the cached error of the host if the entry hasn't expired.
+func (dc *dnsNegativeCache) get(host string) error {
+	if host == "" {
+		return nil
+	}
+
+	host = strings.ToLower(host)
+
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	entry, ok := dc.entries[host]
+	if !ok {
+		return nil
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(dc.entries, host)
+
+		return nil
+	}
+
+	return entry.err
+}
+
+// put caches the dial error if the host is not found by the DNS lookup.
+func (dc *dnsNegativeCache) put(host string, err error) {
+	if host == "" || net.ParseIP(host) != nil {
+		return
+	}
+
+	dnsError, ok := errors.AsType[*net.DNSError](err)
+	if !ok || !dnsError.IsNotFound {
+		return
+	}
+
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	dc.entries[strings.ToLower(host)] = dnsNegativeCacheEntry{
+		err:       err,
+		expiresAt: time.Now().Add(dc.ttl),
+	}
+}
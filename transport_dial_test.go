@@ -0,0 +1,108 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTransportDialContext_DNSNegativeCache(t *testing.T) {
+	var lookups int
+
+	// The resolver of the fake dialer always returns NXDOMAIN after a slow lookup.
+	dial := func(_ context.Context, network string, address string) (net.Conn, error) {
+		lookups++
+
+		time.Sleep(50 * time.Millisecond)
+
+		return nil, &net.OpError{
+			Op:  "dial",
+			Net: network,
+			Err: &net.DNSError{
+				Err:        "no such host",
+				Name:       address,
+				IsNotFound: true,
+			},
+		}
+	}
+
+	dialContext := transportDialContext(dial, newDNSNegativeCache(200*time.Millisecond))
+
+	_, err := dialContext(t.Context(), "tcp", "example.invalid:443")
+	if !isDNSNotFound(err) {
+		t.Fatalf("expected the DNS not found error, got: %v", err)
+	}
+
+	for _, address := range []string{"example.invalid:443", "EXAMPLE.invalid:80"} {
+		start := time.Now()
+
+		_, err = dialContext(t.Context(), "tcp", address)
+		if !isDNSNotFound(err) {
+			t.Fatalf("expected the cached DNS not found error, got: %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+			t.Errorf("expected the cached lookup to fail fast, took: %s", elapsed)
+		}
+	}
+
+	if lookups != 1 {
+		t.Errorf("expected 1 lookup within the TTL, got: %d", lookups)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	_, err = dialContext(t.Context(), "tcp", "example.invalid:443")
+	if !isDNSNotFound(err) {
+		t.Fatalf("expected the DNS not found error, got: %v", err)
+	}
+
+	if lookups != 2 {
+		t.Errorf("expected the lookup to be retried after the TTL, got %d lookups", lookups)
+	}
+}
+
+func TestTransportDialContext_DNSNegativeCacheIgnoresOtherErrors(t *testing.T) {
+	var dials int
+
+	dial := func(context.Context, string, string) (net.Conn, error) {
+		dials++
+
+		return nil, &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	}
+
+	dialContext := transportDialContext(dial, newDNSNegativeCache(time.Minute))
+
+	for range 2 {
+		_, err := dialContext(t.Context(), "tcp", "example.com:443")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+
+	if dials != 2 {
+		t.Errorf("expected DNS timeout errors not to be cached, got %d dials", dials)
+	}
+}
+
+func isDNSNotFound(err error) bool {
+	dnsError, ok := errors.AsType[*net.DNSError](err)
+
+	return ok && dnsError.IsNotFound
+}
@@ -0,0 +1,336 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hasura/goenvconf"
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/gohttpc/httpconfig"
+	"github.com/relychan/goutils"
+)
+
+func TestTransportFromConfig_Proxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	t.Run("socks5", func(t *testing.T) {
+		proxy := newSOCKS5Server(t)
+
+		password := goenvconf.NewEnvStringVariable("SOCKS5_PASSWORD")
+		username := goenvconf.NewEnvStringValue("user")
+
+		options := gohttpc.NewClientOptions(
+			gohttpc.WithGetEnvFunc(func(name string) (string, error) {
+				if name == "SOCKS5_PASSWORD" {
+					return "secret", nil
+				}
+
+				return "", nil
+			}),
+		)
+
+		transport := gohttpc.TransportFromConfig(&gohttpc.HTTPTransportConfig{
+			Proxy: &gohttpc.HTTPProxyConfig{
+				URL:      goenvconf.NewEnvStringValue("socks5://" + proxy.addr),
+				Username: &username,
+				Password: &password,
+			},
+		}, options)
+
+		client := gohttpc.NewClient(gohttpc.WithHTTPClient(&http.Client{Transport: transport}))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		resp, err := client.R(http.MethodGet, server.URL).Execute(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		goutils.CloseResponse(resp)
+
+		if err != nil || string(body) != "ok" {
+			t.Fatalf("expected the response through the proxy, got: %s, %v", string(body), err)
+		}
+
+		target := <-proxy.targets
+		if target.address != server.Listener.Addr().String() {
+			t.Errorf("expected the proxy to connect to %s, got: %s", server.Listener.Addr(), target.address)
+		}
+
+		if target.credentials != "user:secret" {
+			t.Errorf("expected the proxy credentials user:secret, got: %s", target.credentials)
+		}
+	})
+
+	t.Run("invalid_scheme", func(t *testing.T) {
+		transport := gohttpc.TransportFromConfig(&gohttpc.HTTPTransportConfig{
+			Proxy: &gohttpc.HTTPProxyConfig{
+				URL: goenvconf.NewEnvStringValue("ftp://localhost:21"),
+			},
+		}, nil)
+
+		client := gohttpc.NewClient(gohttpc.WithHTTPClient(&http.Client{Transport: transport}))
+		defer goutils.CatchWarnErrorFunc(client.Close)
+
+		_, err := client.R(http.MethodGet, server.URL).Execute(t.Context()) //nolint:bodyclose
+		if !errors.Is(err, gohttpc.ErrUnsupportedProxyScheme) {
+			t.Errorf("expected the unsupported proxy scheme error, got: %v", err)
+		}
+	})
+}
+
+func TestHTTP2PriorKnowledge(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+
+	defer server.Close()
+
+	configClient, err := httpconfig.NewClientFromConfig(&httpconfig.HTTPClientConfig{
+		Transport: &gohttpc.HTTPTransportConfig{
+			HTTP2PriorKnowledge: true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		client        *gohttpc.Client
+		expectedProto int
+	}{
+		{
+			name:          "default",
+			client:        gohttpc.NewClient(),
+			expectedProto: 1,
+		},
+		{
+			name:          "option",
+			client:        gohttpc.NewClient(gohttpc.WithHTTP2PriorKnowledge()),
+			expectedProto: 2,
+		},
+		{
+			name:          "config",
+			client:        configClient,
+			expectedProto: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer goutils.CatchWarnErrorFunc(tc.client.Close)
+
+			resp, err := tc.client.R(http.MethodGet, server.URL).Execute(t.Context())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			if resp.ProtoMajor != tc.expectedProto {
+				t.Errorf("expected HTTP/%d, got: %s", tc.expectedProto, resp.Proto)
+			}
+		})
+	}
+}
+
+type socks5Target struct {
+	address     string
+	credentials string
+}
+
+type socks5Server struct {
+	addr    string
+	targets chan socks5Target
+}
+
+// newSOCKS5Server starts a minimal SOCKS5 server which supports the CONNECT command
+// with the username/password authentication.
+func newSOCKS5Server(t *testing.T) *socks5Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	server := &socks5Server{
+		addr:    listener.Addr().String(),
+		targets: make(chan socks5Target, 1),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *socks5Server) serve(conn net.Conn) {
+	defer goutils.CatchWarnErrorFunc(conn.Close)
+
+	// Greeting: version, number of methods and methods.
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return
+	}
+
+	// Select the username/password authentication.
+	if _, err := conn.Write([]byte{5, 2}); err != nil {
+		return
+	}
+
+	credentials, err := readSOCKS5Credentials(conn)
+	if err != nil {
+		return
+	}
+
+	if _, err := conn.Write([]byte{1, 0}); err != nil {
+		return
+	}
+
+	address, err := readSOCKS5Address(conn)
+	if err != nil {
+		return
+	}
+
+	target, err := net.Dial("tcp", address)
+	if err != nil {
+		_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+
+		return
+	}
+
+	defer goutils.CatchWarnErrorFunc(target.Close)
+
+	s.targets <- socks5Target{address: address, credentials: credentials}
+
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+
+	go func() {
+		_, _ = io.Copy(target, conn)
+	}()
+
+	_, _ = io.Copy(conn, target)
+}
+
+func readSOCKS5Credentials(conn net.Conn) (string, error) {
+	readField := func() (string, error) {
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return "", err
+		}
+
+		value := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, value); err != nil {
+			return "", err
+		}
+
+		return string(value), nil
+	}
+
+	version := make([]byte, 1)
+	if _, err := io.ReadFull(conn, version); err != nil {
+		return "", err
+	}
+
+	username, err := readField()
+	if err != nil {
+		return "", err
+	}
+
+	password, err := readField()
+	if err != nil {
+		return "", err
+	}
+
+	return username + ":" + password, nil
+}
+
+func readSOCKS5Address(conn net.Conn) (string, error) {
+	// Request: version, command, reserved and address type.
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+
+	var host string
+
+	switch header[3] {
+	case 1:
+		ip := make([]byte, net.IPv4len)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+
+		host = net.IP(ip).String()
+	case 3:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return "", err
+		}
+
+		name := make([]byte, size[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", err
+		}
+
+		host = string(name)
+	default:
+		return "", errors.New("unsupported address type")
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc
+
+import (
+	"strings"
+
+	"github.com/relychan/goutils"
+)
+
+// JoinURL joins the base URL and the reference URL of a request.
+// Absolute http and https references are returned as-is. An empty or root reference resolves to the base URL.
+// Otherwise, the reference is appended to the base URL with a single slash between them.
+// Trailing slashes of the result are trimmed.
+func JoinURL(base string, ref string) string {
+	if isAbsoluteHTTPURL(ref) {
+		return ref
+	}
+
+	base = strings.TrimRight(base, "/")
+
+	switch {
+	case ref == "" || ref == "/":
+		return base
+	case ref[0] == '/':
+		return strings.TrimRight(base+ref, "/")
+	default:
+		return strings.TrimRight(base+"/"+ref, "/")
+	}
+}
+
+func isAbsoluteHTTPURL(rawURL string) bool {
+	return goutils.HasStringPrefixFold(rawURL, "http://") ||
+		goutils.HasStringPrefixFold(rawURL, "https://")
+}
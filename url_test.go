@@ -0,0 +1,95 @@
+// Copyright 2026 RelyChan Pte. Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gohttpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/relychan/gohttpc"
+	"github.com/relychan/goutils"
+)
+
+func TestJoinURL(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Base     string
+		Ref      string
+		Expected string
+	}{
+		{Name: "empty_ref", Base: "https://example.com/api/", Ref: "", Expected: "https://example.com/api"},
+		{Name: "root_ref", Base: "https://example.com/api", Ref: "/", Expected: "https://example.com/api"},
+		{Name: "leading_slash", Base: "https://example.com/api", Ref: "/users", Expected: "https://example.com/api/users"},
+		{Name: "no_slash", Base: "https://example.com/api", Ref: "users", Expected: "https://example.com/api/users"},
+		{Name: "trailing_slash_base", Base: "https://example.com/api/", Ref: "/users", Expected: "https://example.com/api/users"},
+		{Name: "trailing_slash_ref", Base: "https://example.com", Ref: "users/", Expected: "https://example.com/users"},
+		{Name: "query", Base: "https://example.com/api/", Ref: "users?page=1", Expected: "https://example.com/api/users?page=1"},
+		{Name: "absolute_http", Base: "https://example.com/api", Ref: "http://other.com/x", Expected: "http://other.com/x"},
+		{Name: "absolute_https_case", Base: "https://example.com/api", Ref: "HTTPS://other.com/x", Expected: "HTTPS://other.com/x"},
+		{Name: "http_prefixed_path", Base: "https://example.com/api", Ref: "http-status", Expected: "https://example.com/api/http-status"},
+		{Name: "empty_base", Base: "", Ref: "/users", Expected: "/users"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result := gohttpc.JoinURL(tc.Base, tc.Ref)
+			if result != tc.Expected {
+				t.Errorf("expected %s, got: %s", tc.Expected, result)
+			}
+		})
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	var requestPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Base     string
+		Ref      string
+		Expected string
+	}{
+		{Name: "empty_ref", Base: server.URL + "/api/", Ref: "", Expected: "/api"},
+		{Name: "leading_slash", Base: server.URL + "/api", Ref: "/users", Expected: "/api/users"},
+		{Name: "no_slash", Base: server.URL + "/api/", Ref: "users?page=1", Expected: "/api/users?page=1"},
+		{Name: "absolute", Base: "http://invalid.example", Ref: server.URL + "/other", Expected: "/other"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			client := gohttpc.NewClient(gohttpc.WithBaseURL(tc.Base))
+			defer goutils.CatchWarnErrorFunc(client.Close)
+
+			resp, err := client.R(http.MethodGet, tc.Ref).Execute(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goutils.CloseResponse(resp)
+
+			if requestPath != tc.Expected {
+				t.Errorf("expected request path %s, got: %s", tc.Expected, requestPath)
+			}
+		})
+	}
+}